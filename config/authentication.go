@@ -0,0 +1,43 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const DefaultJWKSRefreshInterval = time.Minute
+
+type AuthConfig struct {
+	// Secret of the HS256 tokens, HMAC tokens are rejected when it is empty
+	JWTSecret string
+	// JWKS endpoint (JWKS_URL) or local JWKS file (JWKS_FILE) with the RS256/ES256 public keys of the gateway
+	JWKSSource string
+	// Minimum time between two JWKS refreshes, so tokens with made up kids cannot flood the endpoint
+	JWKSRefreshInterval time.Duration
+	// Expected "iss" and "aud" claims, these are not checked when empty
+	Issuer   string
+	Audience string
+}
+
+// Reads the authentication settings once at startup
+func GetAuthConfig() AuthConfig {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, relying on environment variables")
+	}
+
+	jwksSource := os.Getenv("JWKS_URL")
+	if jwksSource == "" {
+		jwksSource = os.Getenv("JWKS_FILE")
+	}
+
+	return AuthConfig{
+		JWTSecret:           os.Getenv("JWT_SECRET"),
+		JWKSSource:          jwksSource,
+		JWKSRefreshInterval: getDuration("JWKS_REFRESH_INTERVAL", DefaultJWKSRefreshInterval),
+		Issuer:              os.Getenv("JWT_ISSUER"),
+		Audience:            os.Getenv("JWT_AUDIENCE"),
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	DefaultSeatHoldTTL         = 10 * time.Minute
+	DefaultSeatHoldMaxLifetime = 30 * time.Minute
+	DefaultIdempotencyKeyTTL   = 24 * time.Hour
+	DefaultQuoteTTL            = 15 * time.Minute
+	DefaultPaymentTimeout      = 30 * time.Minute
+	DefaultPaymentExpiryMargin = 15 * time.Minute
+)
+
+// Reads how long seats stay held during checkout from SEAT_HOLD_TTL (e.g. "15m")
+func GetSeatHoldTTL() time.Duration {
+	return getDuration("SEAT_HOLD_TTL", DefaultSeatHoldTTL)
+}
+
+// Reads how long seats can stay held in total from SEAT_HOLD_MAX_LIFETIME (e.g. "45m"), extending a hold never goes past it
+func GetSeatHoldMaxLifetime() time.Duration {
+	return getDuration("SEAT_HOLD_MAX_LIFETIME", DefaultSeatHoldMaxLifetime)
+}
+
+// Reads how long the responses of idempotent requests are replayed from IDEMPOTENCY_KEY_TTL (e.g. "24h")
+func GetIdempotencyKeyTTL() time.Duration {
+	return getDuration("IDEMPOTENCY_KEY_TTL", DefaultIdempotencyKeyTTL)
+}
+
+// Reads how long a price quote can be booked from QUOTE_TTL (e.g. "30m")
+func GetQuoteTTL() time.Duration {
+	return getDuration("QUOTE_TTL", DefaultQuoteTTL)
+}
+
+// Reads how long a booking can stay unpaid before it expires from PAYMENT_TIMEOUT (e.g. "1h")
+func GetPaymentTimeout() time.Duration {
+	return getDuration("PAYMENT_TIMEOUT", DefaultPaymentTimeout)
+}
+
+// Reads how long an unpaid booking is kept after the payment timeout from PAYMENT_EXPIRY_MARGIN (e.g. "30m"),
+// so a payment the payment service accepted just before the timeout can still confirm the booking
+func GetPaymentExpiryMargin() time.Duration {
+	return getDuration("PAYMENT_EXPIRY_MARGIN", DefaultPaymentExpiryMargin)
+}
+
+func getDuration(name string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid %s '%s', using the default of %s", name, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"crypto/rand"
+	"log"
+	"os"
+)
+
+// Reads the secret the price quotes are signed with from QUOTE_SIGNING_SECRET
+// Every instance of the booking service needs the same secret, otherwise a quote can only be booked on the instance that issued it
+func GetQuoteSigningSecret() []byte {
+	if secret := os.Getenv("QUOTE_SIGNING_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+
+	log.Println("QUOTE_SIGNING_SECRET is not set, using a random secret, quotes are only valid on this instance until it restarts")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("Failed to generate a quote signing secret: %v", err)
+	}
+	return secret
+}
@@ -1,11 +1,291 @@
 package config
 
-import "github.com/rabbitmq/amqp091-go"
+import (
+	"context"
+	"flyhorizons-bookingservice/services/interfaces"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	initialReconnectDelay = time.Second
+	maxReconnectDelay     = 30 * time.Second
+)
 
 var RabbitMQClient *RabbitMQ
 
-// Struct to store RabbitMQ connection and channel
+// Struct to store the RabbitMQ connection and channel
+// The connection is re-established whenever the broker closes it, after which the queues
+// are declared again and the registered consumers are restarted on the new channel
 type RabbitMQ struct {
-	Connection *amqp091.Connection
-	Channel    *amqp091.Channel
+	url        string
+	dialer     interfaces.RabbitMQDialer
+	sleep      func(delay time.Duration)
+	mutex      sync.RWMutex
+	connection interfaces.RabbitMQConnection
+	channel    interfaces.RabbitMQChannel
+	connected  bool
+	closed     bool
+	consumers  []func(channel interfaces.RabbitMQChannel) error
+}
+
+var _ interfaces.MessagePublisher = (*RabbitMQ)(nil)
+var _ interfaces.MessageBroker = (*RabbitMQ)(nil)
+
+func NewRabbitMQ(url string) *RabbitMQ {
+	return NewRabbitMQWithDialer(url, amqpDialer{}, time.Sleep)
+}
+
+// The dialer opens every connection and sleep waits between the reconnect attempts
+func NewRabbitMQWithDialer(url string, dialer interfaces.RabbitMQDialer, sleep func(delay time.Duration)) *RabbitMQ {
+	return &RabbitMQ{
+		url:    url,
+		dialer: dialer,
+		sleep:  sleep,
+	}
+}
+
+// Connects to RabbitMQ and returns whether it succeeded, when it did not the connection is retried in the background
+func (rabbitMQ *RabbitMQ) Connect() bool {
+	if err := rabbitMQ.connect(); err != nil {
+		log.Printf("RabbitMQ is unavailable: %v", err)
+		go rabbitMQ.reconnect()
+		return false
+	}
+	return true
+}
+
+func (rabbitMQ *RabbitMQ) IsConnected() bool {
+	if rabbitMQ == nil {
+		return false
+	}
+
+	rabbitMQ.mutex.RLock()
+	defer rabbitMQ.mutex.RUnlock()
+
+	return rabbitMQ.connected && !rabbitMQ.connection.IsClosed()
+}
+
+// Registers a consumer, it is started straight away when connected and restarted after every reconnect
+func (rabbitMQ *RabbitMQ) RegisterConsumer(start func(channel interfaces.RabbitMQChannel) error) error {
+	rabbitMQ.mutex.Lock()
+	defer rabbitMQ.mutex.Unlock()
+
+	rabbitMQ.consumers = append(rabbitMQ.consumers, start)
+	if !rabbitMQ.connected {
+		log.Println("RabbitMQ is disconnected, the consumer starts once the connection is restored")
+		return nil
+	}
+	return start(rabbitMQ.channel)
+}
+
+// Opens a separate channel on the current connection, the caller is responsible for closing it
+func (rabbitMQ *RabbitMQ) OpenChannel() (interfaces.RabbitMQChannel, error) {
+	rabbitMQ.mutex.RLock()
+	defer rabbitMQ.mutex.RUnlock()
+
+	if !rabbitMQ.connected {
+		return nil, fmt.Errorf("RabbitMQ is disconnected")
+	}
+	return rabbitMQ.connection.Channel()
+}
+
+// Publishes a persistent message to the queue and waits until RabbitMQ has confirmed it
+func (rabbitMQ *RabbitMQ) Publish(queue string, body []byte) error {
+	return rabbitMQ.PublishWithHeaders(queue, body, nil)
+}
+
+func (rabbitMQ *RabbitMQ) PublishWithHeaders(queue string, body []byte, headers amqp091.Table) error {
+	if rabbitMQ == nil {
+		return fmt.Errorf("RabbitMQ has not been initialized")
+	}
+
+	rabbitMQ.mutex.RLock()
+	channel, connected := rabbitMQ.channel, rabbitMQ.connected
+	rabbitMQ.mutex.RUnlock()
+
+	if !connected {
+		return fmt.Errorf("RabbitMQ is disconnected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		"",
+		queue,
+		false,
+		false,
+		amqp091.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp091.Persistent,
+			Headers:      headers,
+			Body:         body,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// The channel is not in confirm mode, nothing to wait for
+	if confirmation == nil {
+		return nil
+	}
+
+	acknowledged, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acknowledged {
+		return fmt.Errorf("message to queue '%s' was rejected by RabbitMQ", queue)
+	}
+	return nil
+}
+
+// Closes the channel and connection, and stops reconnecting
+func (rabbitMQ *RabbitMQ) Close() {
+	rabbitMQ.mutex.Lock()
+	defer rabbitMQ.mutex.Unlock()
+
+	rabbitMQ.closed = true
+	rabbitMQ.connected = false
+	if rabbitMQ.channel != nil {
+		if err := rabbitMQ.channel.Close(); err != nil {
+			log.Printf("Error closing RabbitMQ channel: %v", err)
+		}
+	}
+	if rabbitMQ.connection != nil {
+		if err := rabbitMQ.connection.Close(); err != nil {
+			log.Printf("Error closing RabbitMQ connection: %v", err)
+		}
+	}
+}
+
+// Dials RabbitMQ, declares the queues and starts the registered consumers
+func (rabbitMQ *RabbitMQ) connect() error {
+	connection, err := rabbitMQ.dialer.Dial(rabbitMQ.url)
+	if err != nil {
+		return fmt.Errorf("an error occurred while connecting to RabbitMQ: %w", err)
+	}
+
+	channel, err := connection.Channel()
+	if err != nil {
+		connection.Close()
+		return fmt.Errorf("an error occurred while opening the RabbitMQ channel: %w", err)
+	}
+
+	// Publisher confirms, so a message only counts as published once RabbitMQ has accepted it
+	if err := channel.Confirm(false); err != nil {
+		connection.Close()
+		return fmt.Errorf("an error occurred while enabling publisher confirms: %w", err)
+	}
+
+	if err := declareQueues(channel); err != nil {
+		connection.Close()
+		return err
+	}
+
+	rabbitMQ.mutex.Lock()
+	defer rabbitMQ.mutex.Unlock()
+
+	if rabbitMQ.closed {
+		connection.Close()
+		return fmt.Errorf("RabbitMQ client has been closed")
+	}
+
+	rabbitMQ.connection = connection
+	rabbitMQ.channel = channel
+	rabbitMQ.connected = true
+
+	for _, start := range rabbitMQ.consumers {
+		if err := start(channel); err != nil {
+			log.Printf("Error restarting RabbitMQ consumer: %v", err)
+		}
+	}
+
+	// Registered before connect returns, so a close straight after connecting is not missed
+	connectionClosed := connection.NotifyClose(make(chan *amqp091.Error, 1))
+	channelClosed := channel.NotifyClose(make(chan *amqp091.Error, 1))
+	go rabbitMQ.watch(connection, connectionClosed, channelClosed)
+	return nil
+}
+
+// Waits until the connection or channel closes, then reconnects
+func (rabbitMQ *RabbitMQ) watch(connection interfaces.RabbitMQConnection, connectionClosed chan *amqp091.Error, channelClosed chan *amqp091.Error) {
+	var reason *amqp091.Error
+	select {
+	case reason = <-connectionClosed:
+	case reason = <-channelClosed:
+	}
+
+	rabbitMQ.mutex.Lock()
+	rabbitMQ.connected = false
+	closed := rabbitMQ.closed
+	rabbitMQ.mutex.Unlock()
+
+	if closed {
+		return
+	}
+
+	log.Printf("RabbitMQ connection lost: %v", reason)
+	if !connection.IsClosed() {
+		connection.Close()
+	}
+	rabbitMQ.reconnect()
+}
+
+// Keeps trying to connect with an exponential backoff
+func (rabbitMQ *RabbitMQ) reconnect() {
+	delay := initialReconnectDelay
+	for {
+		rabbitMQ.mutex.RLock()
+		closed := rabbitMQ.closed
+		rabbitMQ.mutex.RUnlock()
+		if closed {
+			return
+		}
+
+		log.Printf("Reconnecting to RabbitMQ in %s", delay)
+		rabbitMQ.sleep(delay)
+
+		if err := rabbitMQ.connect(); err != nil {
+			log.Printf("Error reconnecting to RabbitMQ: %v", err)
+			delay *= 2
+			if delay > maxReconnectDelay {
+				delay = maxReconnectDelay
+			}
+			continue
+		}
+
+		log.Println("RabbitMQ connection has been restored")
+		return
+	}
+}
+
+// Dials the broker with amqp091
+type amqpDialer struct{}
+
+func (amqpDialer) Dial(url string) (interfaces.RabbitMQConnection, error) {
+	connection, err := amqp091.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &amqpConnection{Connection: connection}, nil
+}
+
+type amqpConnection struct {
+	*amqp091.Connection
+}
+
+func (connection *amqpConnection) Channel() (interfaces.RabbitMQChannel, error) {
+	channel, err := connection.Connection.Channel()
+	if err != nil {
+		return nil, err
+	}
+	return channel, nil
 }
@@ -1,29 +1,46 @@
 package config
 
 import (
+	"flyhorizons-bookingservice/services/interfaces"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/rabbitmq/amqp091-go"
 )
 
+const (
+	RetryQueueSuffix      = ".retry"
+	DeadLetterQueueSuffix = ".dlq"
+	// Deliveries of a message before it is moved to the dead-letter queue
+	MaxDeliveryAttempts = 5
+	RetryDelay          = 10 * time.Second
+)
+
+// Queues this service consumes, each gets a retry queue and a dead-letter queue
+var ConsumedQueues = []string{
+	"user_deleted",
+	"payment.success",
+	"payment.failed",
+}
+
 func InitializeRabbitMQ() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, relying on environment variables")
 	}
 
-	connection, err := amqp091.Dial(os.Getenv("RABBITMQ_URL"))
-	if err != nil {
-		log.Fatalf("An error occurred while connecting to RabbitMQ: %s", err)
-	}
+	RabbitMQClient = NewRabbitMQ(os.Getenv("RABBITMQ_URL"))
 
-	channel, err := connection.Channel()
-	if err != nil {
-		log.Fatalf("An error occurred while opening the RabbitMQ channel: %s", err)
+	// The service keeps running while RabbitMQ is unavailable, the connection is retried in the background
+	if RabbitMQClient.Connect() {
+		log.Println("RabbitMQ has been initialized successfully.")
 	}
+}
 
-	// Declare the queues
+// Declares the queues, this is repeated after every reconnect
+func declareQueues(channel interfaces.RabbitMQChannel) error {
 	queues := []string{
 		"booking.created",
 		"booking.confirmed",
@@ -33,7 +50,7 @@ func InitializeRabbitMQ() {
 	}
 
 	for _, queueName := range queues {
-		_, err = channel.QueueDeclare(
+		_, err := channel.QueueDeclare(
 			queueName, // Queue Name
 			true,      // Durable
 			false,     // Auto Delete
@@ -42,14 +59,35 @@ func InitializeRabbitMQ() {
 			nil,       // Arguments
 		)
 		if err != nil {
-			log.Fatalf("An error occurred while declaring the '%s' queue: %s", queueName, err)
+			return fmt.Errorf("an error occurred while declaring the '%s' queue: %w", queueName, err)
 		}
 	}
 
-	RabbitMQClient = &RabbitMQ{
-		Connection: connection,
-		Channel:    channel,
+	for _, queueName := range ConsumedQueues {
+		// Failed messages wait in the retry queue until their TTL expires,
+		// after which RabbitMQ dead-letters them back onto the original queue
+		_, err := channel.QueueDeclare(
+			queueName+RetryQueueSuffix,
+			true,
+			false,
+			false,
+			false,
+			amqp091.Table{
+				"x-message-ttl":             RetryDelay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queueName,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("an error occurred while declaring the '%s' queue: %w", queueName+RetryQueueSuffix, err)
+		}
+
+		// Messages that keep failing are parked here until they are replayed
+		_, err = channel.QueueDeclare(queueName+DeadLetterQueueSuffix, true, false, false, false, nil)
+		if err != nil {
+			return fmt.Errorf("an error occurred while declaring the '%s' queue: %w", queueName+DeadLetterQueueSuffix, err)
+		}
 	}
 
-	log.Println("RabbitMQ has been initialized successfully.")
+	return nil
 }
@@ -10,11 +10,7 @@ func (c RabbitMQCheck) Name() string {
 	return "rabbitmq"
 }
 
+// Fails while the RabbitMQ client is reconnecting, so the service is reported as not ready
 func (c RabbitMQCheck) Pass() bool {
-	conn := config.RabbitMQClient
-	if conn == nil || conn.Connection == nil || conn.Channel == nil {
-		return false
-	}
-	// Try to ping the connection
-	return !conn.Connection.IsClosed()
+	return config.RabbitMQClient.IsConnected()
 }
@@ -9,7 +9,9 @@ import (
 	"flyhorizons-bookingservice/services"
 	"flyhorizons-bookingservice/services/authentication"
 	"flyhorizons-bookingservice/services/converter"
+	"flyhorizons-bookingservice/services/idempotency"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -25,22 +27,7 @@ func main() {
 	config.InitializeRabbitMQ()
 
 	// Setup close for RabbitMQ connections
-	if config.RabbitMQClient != nil {
-		defer func() {
-			if config.RabbitMQClient.Channel != nil {
-				err := config.RabbitMQClient.Channel.Close()
-				if err != nil {
-					log.Printf("Error closing RabbitMQ channel: %v", err)
-				}
-			}
-			if config.RabbitMQClient.Connection != nil {
-				err := config.RabbitMQClient.Connection.Close()
-				if err != nil {
-					log.Printf("Error closing RabbitMQ connection: %v", err)
-				}
-			}
-		}()
-	}
+	defer config.RabbitMQClient.Close()
 
 	router := gin.Default()
 
@@ -59,6 +46,11 @@ func main() {
 	// --- Microservices setup ---
 	bookingRepo := repositories.NewBookingRepository(&baseRepo)
 	seatRepo := repositories.NewSeatRepository(&baseRepo)
+	outboxRepo := repositories.NewOutboxRepository(&baseRepo)
+	seatHoldRepo := repositories.NewSeatHoldRepository(&baseRepo)
+	idempotencyRepo := repositories.NewIdempotencyRepository(&baseRepo)
+	fareRepo := repositories.NewFareRepository(&baseRepo)
+	ancillaryRepo := repositories.NewAncillaryRepository(&baseRepo)
 
 	// Converters
 	bookingConverter := converter.BookingConverter{}
@@ -66,11 +58,18 @@ func main() {
 	seatConverter := converter.SeatConverter{}
 
 	// Authentication
-	gatewayAuthMiddleware := authentication.NewGatewayAuthMiddleware()
+	gatewayAuthMiddleware := authentication.NewGatewayAuthMiddleware(config.GetAuthConfig())
+
+	// Idempotency
+	idempotencyMiddleware := idempotency.NewIdempotencyMiddleware(idempotencyRepo, config.GetIdempotencyKeyTTL())
 
 	// Services
-	bookingService := services.NewBookingService(bookingRepo, bookingConverter, passengerConverter, seatConverter)
 	seatService := services.NewSeatService(seatRepo, seatConverter)
+	ancillaryService := services.NewAncillaryService(ancillaryRepo)
+	pricingService := services.NewPricingService(fareRepo, seatService, ancillaryService, config.GetQuoteSigningSecret(), config.GetQuoteTTL())
+	bookingService := services.NewBookingService(bookingRepo, seatService, pricingService, ancillaryService, bookingConverter, passengerConverter, seatConverter)
+	deadLetterService := services.NewDeadLetterService(config.RabbitMQClient)
+	seatHoldService := services.NewSeatHoldService(seatHoldRepo, seatService, seatConverter, config.GetSeatHoldTTL(), config.GetSeatHoldMaxLifetime())
 
 	// Start the UserEventListener in a goroutine to not block the main thread
 	userDeletedListener := services.NewUserEventListener(config.RabbitMQClient, *bookingService)
@@ -82,9 +81,28 @@ func main() {
 	go paymentProcessedListener.StartPaymentProcessedConsumers()
 	log.Println("Payment processed consumer started in background")
 
+	// Start the OutboxRelay, which publishes the booking events written to the outbox
+	outboxRelay := services.NewOutboxRelay(outboxRepo, config.RabbitMQClient, 2*time.Second, 50)
+	go outboxRelay.Start()
+	log.Println("Outbox relay started in background")
+
+	// Start the sweeper, which removes the expired seat holds
+	go seatHoldService.StartSweeper(time.Minute)
+	log.Println("Seat hold sweeper started in background")
+
+	// Start the sweeper, which expires the unpaid bookings once the payment timeout and its margin have passed, and releases their seats
+	go bookingService.StartExpirySweeper(time.Minute, config.GetPaymentTimeout()+config.GetPaymentExpiryMargin())
+	log.Println("Booking expiry sweeper started in background")
+
+	// Start the sweeper, which removes the expired idempotency keys
+	go idempotencyMiddleware.StartSweeper(time.Hour)
+
 	// Routes
-	routes.RegisterBookingRoutes(router, bookingService, gatewayAuthMiddleware)
-	routes.RegisterSeatRoutes(router, seatService)
+	routes.RegisterBookingRoutes(router, bookingService, gatewayAuthMiddleware, idempotencyMiddleware)
+	routes.RegisterSeatRoutes(router, seatService, seatHoldService, gatewayAuthMiddleware)
+	routes.RegisterAncillaryRoutes(router, ancillaryService)
+	routes.RegisterAdminBookingRoutes(router, bookingService, gatewayAuthMiddleware)
+	routes.RegisterDeadLetterRoutes(router, deadLetterService, gatewayAuthMiddleware)
 
 	// Run the microservice
 	log.Println("Starting booking service on port 8083")
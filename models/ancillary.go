@@ -0,0 +1,13 @@
+package models
+
+import "flyhorizons-bookingservice/models/enums"
+
+// A product of the ancillary catalog, as it is sold in a flight class
+type Ancillary struct {
+	Code        string                  `json:"code"`
+	Category    enums.AncillaryCategory `json:"category"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Currency    string                  `json:"currency"`
+	PriceCents  int64                   `json:"price_cents"` // In cents of the Currency, 0 when it is included in the flight class
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"flyhorizons-bookingservice/models/enums"
+	"time"
 )
 
 type Booking struct {
@@ -9,9 +10,12 @@ type Booking struct {
 	UserID      int               `json:"user_id"`
 	FlightCode  string            `json:"flight_code"`
 	FlightClass enums.FlightClass `json:"flight_class"`
-	Luggage     []enums.Luggage   `json:"luggage"`
+	DepartureAt *time.Time        `json:"departure_at,omitempty"`
 	Seats       []Seat            `json:"seats"`
 	Passengers  []Passenger       `json:"passengers"`
 	Payment     Payment           `json:"payment"`
 	Status      enums.Status      `json:"status"`
+	Version     int               `json:"version"`
+	Price       *PriceBreakdown   `json:"price,omitempty"`    // Only set when the booking is created, never read from the request
+	QuoteID     string            `json:"quote_id,omitempty"` // Optional quote of POST /bookings/quote, only used when the booking is created
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type BookingCancellation struct {
+	Reason string `json:"reason"`
+}
+
+// Bookings deleted, cancelled, refunded or expired before DeletedBefore are purged
+type PurgeRequest struct {
+	DeletedBefore time.Time `json:"deleted_before" binding:"required"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"time"
+)
+
+const (
+	TimeframeUpcoming = "upcoming"
+	TimeframePast     = "past"
+)
+
+// Filters of the booking searches, empty fields are not filtered on
+type BookingSearchCriteria struct {
+	FlightCode     string
+	Status         enums.Status
+	UserID         int
+	Timeframe      string // upcoming or past, bookings without a departure time match neither
+	PassengerName  string // Part of the full name of one of the passengers, case insensitive
+	PassportNumber string
+	CreatedFrom    *time.Time // Inclusive
+	CreatedTo      *time.Time // Exclusive
+	SortBy         string     // created_at, flight_code, status or id
+	SortOrder      string     // asc or desc
+	Limit          int
+	Cursor         string // NextCursor of the previous page
+}
+
+type BookingSearchResult struct {
+	Bookings   []Booking `json:"bookings"`
+	TotalCount int64     `json:"total_count"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"time"
+)
+
+type BookingStatusChange struct {
+	FromStatus enums.Status `json:"from_status"`
+	ToStatus   enums.Status `json:"to_status"`
+	Reason     string       `json:"reason"`
+	ChangedAt  time.Time    `json:"changed_at"`
+}
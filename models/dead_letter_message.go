@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type DeadLetterMessage struct {
+	Queue          string     `json:"queue"`
+	Body           string     `json:"body"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error"`
+	DeadLetteredAt *time.Time `json:"dead_lettered_at"`
+}
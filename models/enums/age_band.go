@@ -0,0 +1,29 @@
+package enums
+
+import "time"
+
+type AgeBand string
+
+const (
+	Infant AgeBand = "Infant" // Younger than 2, travels on the lap of an adult
+	Child  AgeBand = "Child"  // 2 to 11
+	Adult  AgeBand = "Adult"  // 12 and older
+)
+
+// Returns the age band of a passenger born on dateOfBirth, on the date at
+func AgeBandAt(dateOfBirth time.Time, at time.Time) AgeBand {
+	age := at.Year() - dateOfBirth.Year()
+	// The birthday of this year has not been reached yet
+	if at.Month() < dateOfBirth.Month() || (at.Month() == dateOfBirth.Month() && at.Day() < dateOfBirth.Day()) {
+		age--
+	}
+
+	switch {
+	case age < 2:
+		return Infant
+	case age < 12:
+		return Child
+	default:
+		return Adult
+	}
+}
@@ -0,0 +1,11 @@
+package enums
+
+type AncillaryCategory string
+
+const (
+	Meal             AncillaryCategory = "Meal"
+	PriorityBoarding AncillaryCategory = "PriorityBoarding"
+	Lounge           AncillaryCategory = "Lounge"
+	TravelInsurance  AncillaryCategory = "TravelInsurance"
+	PetInCabin       AncillaryCategory = "PetInCabin"
+)
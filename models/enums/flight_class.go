@@ -17,3 +17,10 @@ func FlightClassFromInt(value int) FlightClass {
 		return Economy
 	}
 }
+
+func (flightClass FlightClass) String() string {
+	if flightClass == Business {
+		return "Business"
+	}
+	return "Economy"
+}
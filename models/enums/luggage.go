@@ -1,7 +1,5 @@
 package enums
 
-import "encoding/json"
-
 type Luggage string
 
 const (
@@ -13,27 +11,52 @@ const (
 	BabyCarrier     Luggage = "BabyCarrier"
 )
 
-func LuggageClassesFromJSONString(jsonInput string) []Luggage {
-	var luggageStrings []string
-	if err := json.Unmarshal([]byte(jsonInput), &luggageStrings); err != nil {
-		return []Luggage{}
-	}
-
-	var result []Luggage
-	for _, item := range luggageStrings {
-		switch Luggage(item) {
-		case SmallBag, CabinBag, Cargo20kg, Cargo30kg, SportsEquipment, BabyCarrier:
-			result = append(result, Luggage(item))
-		}
-	}
-
-	return result
+// Weight limit of a single piece of every luggage item, in kg
+var luggageMaxWeights = map[Luggage]int{
+	SmallBag:        8,
+	CabinBag:        10,
+	Cargo20kg:       20,
+	Cargo30kg:       30,
+	SportsEquipment: 32,
+	BabyCarrier:     15,
+}
+
+// The luggage a passenger can take per flight class
+// Included pieces are part of the fare, pieces above the included count are charged up to MaxCount
+type LuggageAllowance struct {
+	Included int
+	MaxCount int
+}
+
+var luggageAllowances = map[FlightClass]map[Luggage]LuggageAllowance{
+	Economy: {
+		SmallBag:        {Included: 1, MaxCount: 1},
+		CabinBag:        {Included: 0, MaxCount: 1},
+		Cargo20kg:       {Included: 0, MaxCount: 2},
+		Cargo30kg:       {Included: 0, MaxCount: 2},
+		SportsEquipment: {Included: 0, MaxCount: 1},
+		BabyCarrier:     {Included: 0, MaxCount: 1},
+	},
+	Business: {
+		SmallBag:        {Included: 1, MaxCount: 1},
+		CabinBag:        {Included: 1, MaxCount: 2},
+		Cargo20kg:       {Included: 0, MaxCount: 3},
+		Cargo30kg:       {Included: 1, MaxCount: 3},
+		SportsEquipment: {Included: 0, MaxCount: 2},
+		BabyCarrier:     {Included: 1, MaxCount: 1},
+	},
+}
+
+func (luggage Luggage) IsValid() bool {
+	_, ok := luggageMaxWeights[luggage]
+	return ok
+}
+
+func (luggage Luggage) MaxWeightKg() int {
+	return luggageMaxWeights[luggage]
 }
 
-func JSONStringToLuggageClasses(luggage []Luggage) string {
-	jsonData, err := json.Marshal(luggage)
-	if err != nil {
-		return "[]"
-	}
-	return string(jsonData)
+// Returns the allowance of a single passenger in the flight class, an unknown item is not allowed at all
+func (luggage Luggage) AllowanceIn(flightClass FlightClass) LuggageAllowance {
+	return luggageAllowances[flightClass][luggage]
 }
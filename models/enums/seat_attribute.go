@@ -0,0 +1,18 @@
+package enums
+
+type SeatPosition string
+
+const (
+	Window SeatPosition = "Window"
+	Middle SeatPosition = "Middle"
+	Aisle  SeatPosition = "Aisle"
+)
+
+type SeatAttribute string
+
+const (
+	ExitRow           SeatAttribute = "ExitRow"
+	ExtraLegroom      SeatAttribute = "ExtraLegroom"
+	Bassinet          SeatAttribute = "Bassinet"
+	RestrictedRecline SeatAttribute = "RestrictedRecline"
+)
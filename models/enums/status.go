@@ -3,6 +3,58 @@ package enums
 type Status string
 
 const (
-	Pending Status = "Pending"
-	Success Status = "Success"
+	Pending         Status = "Pending"
+	AwaitingPayment Status = "AwaitingPayment"
+	Confirmed       Status = "Confirmed"
+	CheckedIn       Status = "CheckedIn"
+	Boarded         Status = "Boarded"
+	Cancelled       Status = "Cancelled"
+	Refunded        Status = "Refunded"
+	Expired         Status = "Expired"
+	PaymentFailed   Status = "PaymentFailed"
 )
+
+// Booking lifecycle, every status lists the statuses it is allowed to move to
+// Boarded, Refunded and Expired are final
+var statusTransitions = map[Status][]Status{
+	Pending:         {AwaitingPayment, Confirmed, PaymentFailed, Cancelled, Expired},
+	AwaitingPayment: {Confirmed, PaymentFailed, Cancelled, Expired},
+	PaymentFailed:   {AwaitingPayment, Cancelled, Expired},
+	Confirmed:       {CheckedIn, Cancelled, Refunded},
+	CheckedIn:       {Boarded, Cancelled},
+	Cancelled:       {Refunded},
+	Boarded:         {},
+	Refunded:        {},
+	Expired:         {},
+}
+
+func StatusFromString(value string) Status {
+	// Bookings paid before the lifecycle was introduced were stored as "Success"
+	if value == "Success" {
+		return Confirmed
+	}
+	return Status(value)
+}
+
+func (status Status) IsValid() bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
+func (status Status) IsFinal() bool {
+	return status.IsValid() && len(statusTransitions[status]) == 0
+}
+
+func (status Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range statusTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Bookings in these statuses no longer occupy their seats
+func (status Status) ReleasesSeats() bool {
+	return status == Cancelled || status == Refunded || status == Expired
+}
@@ -0,0 +1,9 @@
+package models
+
+import "flyhorizons-bookingservice/models/enums"
+
+type LuggageItem struct {
+	Type     enums.Luggage `json:"type"`
+	Quantity int           `json:"quantity"`
+	WeightKg int           `json:"weight_kg,omitempty"` // Weight of each piece, optional, checked against the weight limit of the type
+}
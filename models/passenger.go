@@ -3,9 +3,11 @@ package models
 import "time"
 
 type Passenger struct {
-	ID             int       `json:"id"`
-	FullName       string    `json:"full_name"`
-	DateOfBirth    time.Time `json:"date_of_birth"`
-	PassportNumber string    `json:"passport_number"`
-	Email          string    `json:"email"`
+	ID             int           `json:"id"`
+	FullName       string        `json:"full_name"`
+	DateOfBirth    time.Time     `json:"date_of_birth"`
+	PassportNumber string        `json:"passport_number"`
+	Email          string        `json:"email"`
+	Luggage        []LuggageItem `json:"luggage"`
+	Ancillaries    []string      `json:"ancillaries"` // Codes of the ancillaries of the catalog
 }
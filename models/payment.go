@@ -1,10 +1,16 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
 
+// The card or bank details are only entered at the payment provider,
+// the booking service only ever sees the token the provider issued for them
 type Payment struct {
-	IBAN      string    `json:"iban"`
-	CVV       string    `json:"cvv"`
+	Token     string    `json:"token"` // Opaque payment token or payment intent ID of the payment provider
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	Amount    float64   `json:"amount"`
@@ -12,3 +18,64 @@ type Payment struct {
 	Nonce     string    `json:"nonce"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// Raw card and bank details are rejected instead of ignored, so a client sending them finds out right away
+var rawPaymentFields = []string{"iban", "cvv", "card_number"}
+
+func (payment *Payment) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for field := range fields {
+		if err := rejectRawPaymentField(field); err != nil {
+			return err
+		}
+	}
+
+	// The alias has no UnmarshalJSON method, so this does not recurse
+	type paymentFields Payment
+	return json.Unmarshal(data, (*paymentFields)(payment))
+}
+
+// Checks a whole request body for raw card and bank details, also outside of the payment,
+// e.g. a card number sent along with a passenger would otherwise just be dropped
+func RejectRawPaymentDetails(data []byte) error {
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	return rejectRawPaymentFields(body)
+}
+
+func rejectRawPaymentFields(value interface{}) error {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range value {
+			if err := rejectRawPaymentField(field); err != nil {
+				return err
+			}
+			if err := rejectRawPaymentFields(fieldValue); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := rejectRawPaymentFields(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func rejectRawPaymentField(field string) error {
+	// Field names are matched case-insensitively like encoding/json does, and also without separators (cardNumber, card-number)
+	normalizedField := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(field))
+	for _, rawField := range rawPaymentFields {
+		if normalizedField == strings.ReplaceAll(rawField, "_", "") {
+			return fmt.Errorf("payment must reference a payment token, raw %s is not accepted", rawField)
+		}
+	}
+	return nil
+}
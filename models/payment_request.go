@@ -1,6 +1,8 @@
 package models
 
+// Published on booking.created, the payment service charges the payment token of the booking
 type PaymentRequest struct {
-	BookingID int     `json:"booking_id"`
-	Payment   Payment `json:"payment"`
+	BookingID int            `json:"booking_id"`
+	Payment   Payment        `json:"payment"`
+	Price     PriceBreakdown `json:"price"` // Amount and Currency of the Payment are its total
 }
@@ -0,0 +1,22 @@
+package models
+
+// Calculated by the booking service, the amount sent by the client is never used
+// All amounts are in cents of Currency
+type PriceBreakdown struct {
+	Currency   string      `json:"currency"`
+	Items      []PriceItem `json:"items"`
+	TotalCents int64       `json:"total_cents"`
+}
+
+type PriceItem struct {
+	Type           string `json:"type"` // "fare", "seat", "luggage" or "ancillary"
+	Description    string `json:"description"`
+	Quantity       int    `json:"quantity"`
+	UnitPriceCents int64  `json:"unit_price_cents"`
+	AmountCents    int64  `json:"amount_cents"`
+}
+
+// The total in the major unit of the currency, as the payment service expects it
+func (price PriceBreakdown) Total() float64 {
+	return float64(price.TotalCents) / 100
+}
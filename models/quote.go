@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// The QuoteID is passed as quote_id when the booking is created, so exactly this price is charged
+type Quote struct {
+	QuoteID   string         `json:"quote_id"`
+	Price     PriceBreakdown `json:"price"`
+	ExpiresAt time.Time      `json:"expires_at"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "flyhorizons-bookingservice/models/enums"
+
+// Published on payment.refund_requested, the payment service refunds a payment that arrived after the booking was closed
+type RefundRequest struct {
+	BookingID int          `json:"booking_id"`
+	Status    enums.Status `json:"status"` // Status of the booking when the payment arrived, Expired or Cancelled
+	Reason    string       `json:"reason"`
+}
@@ -1,7 +1,13 @@
 package models
 
+import "flyhorizons-bookingservice/models/enums"
+
 type Seat struct {
-	Row       int    `json:"row"`
-	Column    string `json:"column"`
-	Available bool   `json:"available"`
+	Row        int                   `json:"row"`
+	Column     string                `json:"column"`
+	Available  bool                  `json:"available"`
+	Blocked    bool                  `json:"blocked,omitempty"` // Only set in the seat map, blocked seats can never be held or booked
+	Cabin      enums.FlightClass     `json:"cabin"`
+	Position   enums.SeatPosition    `json:"position,omitempty"`   // Only set in the seat map
+	Attributes []enums.SeatAttribute `json:"attributes,omitempty"` // Only set in the seat map
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type SeatHold struct {
+	HoldID     string    `json:"hold_id"`
+	FlightCode string    `json:"flight_code"`
+	Seats      []Seat    `json:"seats"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type SeatHoldRequest struct {
+	FlightCode string `json:"flight_code" binding:"required"`
+	Seats      []Seat `json:"seats" binding:"required,min=1"`
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+)
+
+type AncillaryRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.AncillaryRepository = (*AncillaryRepository)(nil)
+
+func NewAncillaryRepository(baseRepo *BaseRepository) *AncillaryRepository {
+	return &AncillaryRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+// Returns the whole catalog, ordered by category and code
+func (repo *AncillaryRepository) GetAll() ([]entities.AncillaryEntity, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	var ancillaries []entities.AncillaryEntity
+	if err := db.Order("Category, Code").Find(&ancillaries).Error; err != nil {
+		return nil, translateDatabaseError(err)
+	}
+	return ancillaries, nil
+}
@@ -1,11 +1,16 @@
 package repositories
 
 import (
+	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/models/enums"
 	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
 	"flyhorizons-bookingservice/services/interfaces"
 	"fmt"
 	"log"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type BookingRepository struct {
@@ -32,90 +37,327 @@ func NewBookingRepository(baseRepo *BaseRepository) *BookingRepository {
 	}
 }
 
-func (repo *BookingRepository) GetAll() []entities.BookingEntity {
-	var bookings []entities.BookingEntity
+// Runs fn against a repository bound to a single database transaction,
+// everything written through that repository is committed or rolled back together
+func (repo *BookingRepository) Transaction(fn func(repo interfaces.BookingRepository) error) error {
+	db, err := repo.connect()
+	if err != nil {
+		return err
+	}
 
-	repo.DB.Preload("Passengers").Preload("Seats").Find(&bookings)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return fn(&BookingRepository{BaseRepository: &BaseRepository{DB: tx}})
+	})
+	return translateDatabaseError(err)
+}
 
-	return bookings
+func (repo *BookingRepository) GetAll() ([]entities.BookingEntity, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	var bookings []entities.BookingEntity
+	if err := db.Preload("Passengers").Preload("Seats").Find(&bookings).Error; err != nil {
+		return nil, translateDatabaseError(err)
+	}
+	return bookings, nil
 }
 
-func (repo *BookingRepository) GetByID(id int) entities.BookingEntity {
-	db, _ := repo.CreateConnection()
+// Returns a BookingNotFoundError when there is no booking with the ID
+func (repo *BookingRepository) GetByID(id int) (*entities.BookingEntity, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, err
+	}
 
 	var booking entities.BookingEntity
 
 	// This preloads the related Passengers and Seats
-	db.Preload("Passengers").Preload("Seats").Where("ID = ?", id).Find(&booking)
-
-	return booking
+	result := db.Preload("Passengers").Preload("Seats").Where("ID = ?", id).Limit(1).Find(&booking)
+	if result.Error != nil {
+		return nil, translateDatabaseError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.NewBookingNotFoundError(id, 404)
+	}
+	return &booking, nil
 }
 
-func (repo *BookingRepository) GetByUserID(userID int) []entities.BookingEntity {
-	db, _ := repo.CreateConnection()
+// Looks the booking up by its primary key, without loading its passengers and seats
+func (repo *BookingRepository) Exists(id int) (bool, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return false, err
+	}
 
-	var bookings []entities.BookingEntity
+	exists, err := bookingExists(db, id)
+	if err != nil {
+		return false, translateDatabaseError(err)
+	}
+	return exists, nil
+}
 
-	// This preloads the related Passengers and Seats
-	db.Preload("Passengers").Preload("Seats").Where("UserID = ?", userID).Find(&bookings)
+func bookingExists(db *gorm.DB, id int) (bool, error) {
+	var count int64
+	if err := db.Model(&entities.BookingEntity{}).Where("ID = ?", id).Limit(1).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
 
-	return bookings
+// Returns a page of the bookings of the user, filtered and paged by the database like Search
+func (repo *BookingRepository) GetByUserID(userID int, criteria models.BookingSearchCriteria) ([]entities.BookingEntity, int64, string, error) {
+	criteria.UserID = userID
+	return repo.Search(criteria)
 }
 
-func (repo *BookingRepository) Create(bookingEntity entities.BookingEntity) *entities.BookingEntity {
-	db, _ := repo.CreateConnection()
+func (repo *BookingRepository) Create(bookingEntity entities.BookingEntity) (*entities.BookingEntity, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, err
+	}
 
-	if err := db.Create(&bookingEntity).Error; err != nil {
-		return nil
+	seats := convertSeatEntitiesToSeats(bookingEntity.Seats)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		bookedSeats, err := findBookedSeats(tx, bookingEntity.FlightCode, seats)
+		if err != nil {
+			return err
+		}
+		if len(bookedSeats) > 0 {
+			return errors.NewSeatAlreadyBookedError(bookingEntity.FlightCode, bookedSeats, 409)
+		}
+
+		// Seats held by another user cannot be booked until their hold expires
+		heldSeats, err := findSeatsHeldByOthers(tx, bookingEntity.FlightCode, bookingEntity.UserID, seats)
+		if err != nil {
+			return err
+		}
+		if len(heldSeats) > 0 {
+			return errors.NewSeatHeldError(bookingEntity.FlightCode, heldSeats, 409)
+		}
+
+		bookingEntity.Version = 1
+		if err := tx.Create(&bookingEntity).Error; err != nil {
+			return err
+		}
+
+		// The holds of the user on the booked seats are no longer needed
+		for _, seat := range seats {
+			if err := tx.Where("FlightCode = ? AND Row = ? AND [Column] = ? AND UserID = ?", bookingEntity.FlightCode, seat.Row, seat.Column, bookingEntity.UserID).
+				Delete(&entities.SeatHoldEntity{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Record the initial status, so the history covers the whole lifecycle of the booking
+		return tx.Create(&entities.BookingStatusHistoryEntity{
+			BookingID: bookingEntity.ID,
+			ToStatus:  bookingEntity.Status,
+			Reason:    "Booking created",
+			ChangedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		log.Printf("Error creating booking: %v", err)
+		switch err.(type) {
+		case *errors.SeatAlreadyBookedError, *errors.SeatHeldError:
+			return nil, err
+		}
+		// Another booking may have taken one of the seats at the same time, which violates the unique index
+		if bookedSeats, checkErr := findBookedSeats(db, bookingEntity.FlightCode, seats); checkErr == nil && len(bookedSeats) > 0 {
+			return nil, errors.NewSeatAlreadyBookedError(bookingEntity.FlightCode, bookedSeats, 409)
+		}
+		return nil, translateDatabaseError(err)
 	}
 
-	return &bookingEntity
+	return &bookingEntity, nil
 }
 
-func (repo *BookingRepository) DeleteByBookingID(bookingID int) bool {
-	db, _ := repo.CreateConnection()
+// Soft-deletes the booking, its passengers, seats and status history are kept until the booking is purged
+// Returns a BookingNotFoundError when there is no booking with the ID
+func (repo *BookingRepository) DeleteByBookingID(bookingID int) error {
+	db, err := repo.connect()
+	if err != nil {
+		return err
+	}
 
-	// Delete associated passengers
-	if err := db.Where("BookingID = ?", bookingID).Delete(&entities.PassengerEntity{}).Error; err != nil {
-		log.Printf("Error deleting associated passengers: %v", err)
-		return false
+	result := db.Delete(&entities.BookingEntity{}, bookingID)
+	if result.Error != nil {
+		return translateDatabaseError(result.Error)
 	}
+	if result.RowsAffected == 0 {
+		return errors.NewBookingNotFoundError(bookingID, 404)
+	}
+	return nil
+}
 
-	// Delete associated seats
-	if err := db.Where("BookingID = ?", bookingID).Delete(&entities.SeatEntity{}).Error; err != nil {
-		log.Printf("Error deleting associated seats: %v", err)
-		return false
+// Permanently deletes the bookings that were closed before closedBefore, together with their passengers, seats and status history
+// A booking is closed when it was soft-deleted, or when it was cancelled, refunded or expired and its status has not changed since
+// Returns the number of purged bookings, nothing is deleted when one of the statements fails
+func (repo *BookingRepository) Purge(closedBefore time.Time) (int64, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return 0, err
 	}
 
-	// Delete booking
-	result := repo.DB.Delete(&entities.BookingEntity{}, bookingID)
+	closedValues := []string{}
+	for _, status := range []enums.Status{enums.Cancelled, enums.Refunded, enums.Expired} {
+		closedValues = append(closedValues, storedStatusValues(status)...)
+	}
 
-	return result.Error == nil && result.RowsAffected > 0
+	var purged int64
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// The booking is only closed as of its last status change, so a recently refunded booking is kept
+		laterChanges := tx.Model(&entities.BookingStatusHistoryEntity{}).
+			Select("1").
+			Where("BookingStatusHistory.BookingID = Booking.ID AND BookingStatusHistory.ChangedAt >= ?", closedBefore)
+
+		var bookingIDs []int
+		if err := tx.Unscoped().Model(&entities.BookingEntity{}).
+			Where("DeletedAt IS NOT NULL AND DeletedAt < ?", closedBefore).
+			Or("Status IN ? AND CreatedAt < ? AND NOT EXISTS (?)", closedValues, closedBefore, laterChanges).
+			Pluck("ID", &bookingIDs).Error; err != nil {
+			return err
+		}
+		if len(bookingIDs) == 0 {
+			return nil
+		}
+
+		// Delete associated passengers
+		if err := tx.Where("BookingID IN ?", bookingIDs).Delete(&entities.PassengerEntity{}).Error; err != nil {
+			log.Printf("Error deleting associated passengers: %v", err)
+			return err
+		}
+
+		// Delete associated seats
+		if err := tx.Where("BookingID IN ?", bookingIDs).Delete(&entities.SeatEntity{}).Error; err != nil {
+			log.Printf("Error deleting associated seats: %v", err)
+			return err
+		}
+
+		// Delete associated status history
+		if err := tx.Where("BookingID IN ?", bookingIDs).Delete(&entities.BookingStatusHistoryEntity{}).Error; err != nil {
+			log.Printf("Error deleting associated status history: %v", err)
+			return err
+		}
+
+		// Delete bookings
+		result := tx.Unscoped().Delete(&entities.BookingEntity{}, bookingIDs)
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, translateDatabaseError(err)
+	}
+	return purged, nil
 }
 
-func (repo *BookingRepository) UpdateStatus(bookingID int, status enums.Status) {
-	db, _ := repo.CreateConnection()
+// Returns the IDs of the bookings created before createdBefore that have not been paid yet
+func (repo *BookingRepository) GetUnpaidIDs(createdBefore time.Time) ([]int, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	unpaidValues := []string{}
+	for _, status := range []enums.Status{enums.Pending, enums.AwaitingPayment, enums.PaymentFailed} {
+		unpaidValues = append(unpaidValues, storedStatusValues(status)...)
+	}
 
-	var bookingEntity entities.BookingEntity
+	var bookingIDs []int
+	if err := db.Model(&entities.BookingEntity{}).
+		Where("Status IN ? AND CreatedAt < ?", unpaidValues, createdBefore).
+		Order("ID").
+		Pluck("ID", &bookingIDs).Error; err != nil {
+		return nil, translateDatabaseError(err)
+	}
+	return bookingIDs, nil
+}
 
-	// Find booking by ID
-	if err := db.First(&bookingEntity, bookingID).Error; err != nil {
-		log.Printf("Booking not found with ID %d: %v", bookingID, err)
+func (repo *BookingRepository) UpdateStatus(bookingID int, from enums.Status, to enums.Status, reason string) error {
+	db, err := repo.connect()
+	if err != nil {
+		return err
 	}
 
-	// Update status
-	bookingEntity.Status = string(status)
+	fromValues := storedStatusValues(from)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Only update when the booking is still in the expected status,
+		// so two concurrent transitions cannot both be applied
+		result := tx.Model(&entities.BookingEntity{}).
+			Where("ID = ? AND Status IN ?", bookingID, fromValues).
+			Updates(map[string]interface{}{"Status": string(to), "Version": gorm.Expr("Version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Either the booking does not exist, or it has moved on to another status in the meantime
+			exists, err := bookingExists(tx, bookingID)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return errors.NewBookingNotFoundError(bookingID, 404)
+			}
+			return errors.NewInvalidStatusTransitionError(bookingID, from, to, 409)
+		}
+
+		// Seats of a cancelled or expired booking become available for other bookings
+		if to.ReleasesSeats() {
+			if err := tx.Model(&entities.SeatEntity{}).Where("BookingID = ?", bookingID).Update("Released", true).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&entities.BookingStatusHistoryEntity{
+			BookingID:  bookingID,
+			FromStatus: string(from),
+			ToStatus:   string(to),
+			Reason:     reason,
+			ChangedAt:  time.Now(),
+		}).Error
+	})
+	return translateDatabaseError(err)
+}
 
-	// Save updated entity
-	if err := db.Save(&bookingEntity).Error; err != nil {
-		log.Printf("Failed to update booking status: %v", err)
+func (repo *BookingRepository) GetStatusHistory(bookingID int) ([]entities.BookingStatusHistoryEntity, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, err
 	}
+
+	var history []entities.BookingStatusHistoryEntity
+	if err := db.Where("BookingID = ?", bookingID).Order("ChangedAt, ID").Find(&history).Error; err != nil {
+		return nil, translateDatabaseError(err)
+	}
+	return history, nil
 }
 
-func (repo *BookingRepository) Update(bookingEntity entities.BookingEntity) entities.BookingEntity {
-	db, _ := repo.CreateConnection()
+func (repo *BookingRepository) AddOutboxMessage(queue string, payload []byte) error {
+	db, err := repo.connect()
+	if err != nil {
+		return err
+	}
 
-	db.Save(&bookingEntity)
+	now := time.Now()
+	err = db.Create(&entities.OutboxMessageEntity{
+		Queue:         queue,
+		Payload:       string(payload),
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}).Error
+	return translateDatabaseError(err)
+}
 
-	return bookingEntity
+func convertSeatEntitiesToSeats(seatEntities []entities.SeatEntity) []models.Seat {
+	var seats []models.Seat
+	for _, seat := range seatEntities {
+		seats = append(seats, models.Seat{Row: seat.Row, Column: seat.Column})
+	}
+	return seats
 }
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Columns the bookings can be sorted on, ID breaks the ties so every row has a unique position
+var bookingSortColumns = map[string]string{
+	"created_at":  "CreatedAt",
+	"flight_code": "FlightCode",
+	"status":      "Status",
+	"id":          "ID",
+}
+
+// Position of the last booking of a page, the next page starts right after it
+type bookingCursor struct {
+	SortBy string `json:"s"`
+	Value  string `json:"v"`
+	ID     int    `json:"id"`
+}
+
+// Searches the bookings, filtering, counting, sorting and paging is all done by the database
+// Returns the bookings of the page, the total number of matching bookings and the cursor of the next page
+func (repo *BookingRepository) Search(criteria models.BookingSearchCriteria) ([]entities.BookingEntity, int64, string, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	sortColumn, ok := bookingSortColumns[criteria.SortBy]
+	if !ok {
+		return nil, 0, "", errors.NewInvalidSearchCriteriaError("sort", criteria.SortBy, 400)
+	}
+	direction, comparison := "ASC", ">"
+	if criteria.SortOrder == "desc" {
+		direction, comparison = "DESC", "<"
+	}
+
+	var totalCount int64
+	if err := filterBookings(db, criteria).Count(&totalCount).Error; err != nil {
+		return nil, 0, "", translateDatabaseError(err)
+	}
+
+	query := filterBookings(db, criteria)
+	if criteria.Cursor != "" {
+		cursorValue, cursorID, err := decodeBookingCursor(criteria.Cursor, criteria.SortBy)
+		if err != nil {
+			return nil, 0, "", errors.NewInvalidSearchCriteriaError("cursor", criteria.Cursor, 400)
+		}
+		query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND ID %s ?))", sortColumn, comparison, sortColumn, comparison), cursorValue, cursorValue, cursorID)
+	}
+
+	// One extra booking is loaded to find out whether there is a next page
+	var bookings []entities.BookingEntity
+	err = query.Preload("Passengers").Preload("Seats").
+		Order(fmt.Sprintf("%s %s", sortColumn, direction)).
+		Order("ID " + direction).
+		Limit(criteria.Limit + 1).
+		Find(&bookings).Error
+	if err != nil {
+		return nil, 0, "", translateDatabaseError(err)
+	}
+
+	nextCursor := ""
+	if len(bookings) > criteria.Limit {
+		bookings = bookings[:criteria.Limit]
+		nextCursor = encodeBookingCursor(criteria.SortBy, bookings[len(bookings)-1])
+	}
+	return bookings, totalCount, nextCursor, nil
+}
+
+func filterBookings(db *gorm.DB, criteria models.BookingSearchCriteria) *gorm.DB {
+	query := db.Model(&entities.BookingEntity{})
+
+	if criteria.FlightCode != "" {
+		query = query.Where("FlightCode = ?", criteria.FlightCode)
+	}
+	if criteria.Status != "" {
+		query = query.Where("Status IN ?", storedStatusValues(criteria.Status))
+	}
+	if criteria.UserID != 0 {
+		query = query.Where("UserID = ?", criteria.UserID)
+	}
+	// The departure of a booking is taken from the fare of its flight when it is created, never from the client
+	switch criteria.Timeframe {
+	case models.TimeframeUpcoming:
+		query = query.Where("DepartureAt >= ?", time.Now())
+	case models.TimeframePast:
+		query = query.Where("DepartureAt < ?", time.Now())
+	}
+	if criteria.PassengerName != "" {
+		passengers := db.Model(&entities.PassengerEntity{}).Select("BookingID").
+			Where("LOWER(FullName) LIKE ?", "%"+strings.ToLower(criteria.PassengerName)+"%")
+		query = query.Where("ID IN (?)", passengers)
+	}
+	if criteria.PassportNumber != "" {
+		passengers := db.Model(&entities.PassengerEntity{}).Select("BookingID").
+			Where("PassportNumber = ?", criteria.PassportNumber)
+		query = query.Where("ID IN (?)", passengers)
+	}
+	if criteria.CreatedFrom != nil {
+		query = query.Where("CreatedAt >= ?", *criteria.CreatedFrom)
+	}
+	if criteria.CreatedTo != nil {
+		query = query.Where("CreatedAt < ?", *criteria.CreatedTo)
+	}
+	return query
+}
+
+// Bookings paid before the lifecycle was introduced are still stored as "Success"
+func storedStatusValues(status enums.Status) []string {
+	values := []string{string(status)}
+	if status == enums.Confirmed {
+		values = append(values, "Success")
+	}
+	return values
+}
+
+func encodeBookingCursor(sortBy string, booking entities.BookingEntity) string {
+	cursor := bookingCursor{SortBy: sortBy, ID: booking.ID}
+	switch sortBy {
+	case "created_at":
+		cursor.Value = booking.CreatedAt.Format(time.RFC3339Nano)
+	case "flight_code":
+		cursor.Value = booking.FlightCode
+	case "status":
+		cursor.Value = booking.Status
+	case "id":
+		cursor.Value = strconv.Itoa(booking.ID)
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// A cursor can only be used with the sorting it was created for
+func decodeBookingCursor(encoded string, sortBy string) (interface{}, int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, 0, err
+	}
+	var cursor bookingCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, 0, err
+	}
+	if cursor.SortBy != sortBy {
+		return nil, 0, fmt.Errorf("cursor was created for sorting by %s", cursor.SortBy)
+	}
+
+	switch sortBy {
+	case "created_at":
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		return createdAt, cursor.ID, err
+	case "id":
+		id, err := strconv.Atoi(cursor.Value)
+		return id, cursor.ID, err
+	}
+	return cursor.Value, cursor.ID, nil
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"flyhorizons-bookingservice/models"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Updates the booking and reconciles its passengers and seats with the ones of bookingEntity,
+// missing children are removed, known ones are modified and the rest is added
+// The Version of bookingEntity is the version the client read, a BookingVersionConflictError is returned when
+// the booking has been changed since. Returns a BookingNotFoundError when there is no booking with the ID
+func (repo *BookingRepository) Update(bookingEntity entities.BookingEntity) (*entities.BookingEntity, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedEntity entities.BookingEntity
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var existingEntity entities.BookingEntity
+		result := tx.Preload("Passengers").Preload("Seats").Where("ID = ?", bookingEntity.ID).Limit(1).Find(&existingEntity)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.NewBookingNotFoundError(bookingEntity.ID, 404)
+		}
+		if existingEntity.Version != bookingEntity.Version {
+			return errors.NewBookingVersionConflictError(bookingEntity.ID, 412)
+		}
+
+		// The status is left alone, it only changes through UpdateStatus so the history stays complete
+		// The owner is left alone as well, a booking can never be moved to another account
+		// The version is checked again, as another change can be committed after the booking was read
+		expectedVersion := bookingEntity.Version
+		bookingEntity.Version = expectedVersion + 1
+		result = tx.Model(&entities.BookingEntity{ID: bookingEntity.ID}).
+			Where("Version = ?", expectedVersion).
+			Select("FlightCode", "FlightClass", "DepartureAt", "Version").
+			Omit(clause.Associations).
+			Updates(&bookingEntity)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.NewBookingVersionConflictError(bookingEntity.ID, 412)
+		}
+
+		if err := reconcilePassengers(tx, bookingEntity.ID, existingEntity.Passengers, bookingEntity.Passengers); err != nil {
+			return err
+		}
+		if err := reconcileSeats(tx, existingEntity.Seats, bookingEntity); err != nil {
+			return err
+		}
+
+		return tx.Preload("Passengers").Preload("Seats").Where("ID = ?", bookingEntity.ID).First(&updatedEntity).Error
+	})
+	if err != nil {
+		return nil, translateDatabaseError(err)
+	}
+	return &updatedEntity, nil
+}
+
+// Passengers are matched on their ID, the ID of a passenger of another booking is never reused
+func reconcilePassengers(tx *gorm.DB, bookingID int, existing []entities.PassengerEntity, requested []entities.PassengerEntity) error {
+	existingIDs := map[int]bool{}
+	for _, passenger := range existing {
+		existingIDs[passenger.ID] = true
+	}
+
+	keptIDs := map[int]bool{}
+	for _, passenger := range requested {
+		passenger.BookingID = bookingID
+		if existingIDs[passenger.ID] && !keptIDs[passenger.ID] {
+			keptIDs[passenger.ID] = true
+			if err := tx.Model(&entities.PassengerEntity{ID: passenger.ID}).
+				Select("FullName", "DateOfBirth", "PassportNumber", "Email", "Luggage", "Ancillaries").
+				Omit(clause.Associations).
+				Updates(&passenger).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		passenger.ID = 0
+		if err := tx.Omit(clause.Associations).Create(&passenger).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, passenger := range existing {
+		if keptIDs[passenger.ID] {
+			continue
+		}
+		if err := tx.Delete(&entities.PassengerEntity{}, passenger.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Seats have no ID outside the database, so they are matched on their row and column
+// Seats of another flight are removed, as the flight code is part of the seat
+func reconcileSeats(tx *gorm.DB, existing []entities.SeatEntity, bookingEntity entities.BookingEntity) error {
+	requested := map[string]bool{}
+	for _, seat := range bookingEntity.Seats {
+		requested[seatKey(seat.Row, seat.Column)] = true
+	}
+
+	kept := map[string]bool{}
+	for _, seat := range existing {
+		key := seatKey(seat.Row, seat.Column)
+		if seat.FlightCode == bookingEntity.FlightCode && requested[key] && !kept[key] {
+			kept[key] = true
+			continue
+		}
+		if err := tx.Delete(&entities.SeatEntity{}, seat.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	addedSeats := []models.Seat{}
+	for _, seat := range bookingEntity.Seats {
+		key := seatKey(seat.Row, seat.Column)
+		if kept[key] {
+			continue
+		}
+		kept[key] = true
+		addedSeats = append(addedSeats, models.Seat{Row: seat.Row, Column: seat.Column})
+	}
+	if len(addedSeats) == 0 {
+		return nil
+	}
+
+	// The added seats go through the same checks as the seats of a new booking
+	bookedSeats, err := findBookedSeats(tx, bookingEntity.FlightCode, addedSeats)
+	if err != nil {
+		return err
+	}
+	if len(bookedSeats) > 0 {
+		return errors.NewSeatAlreadyBookedError(bookingEntity.FlightCode, bookedSeats, 409)
+	}
+	heldSeats, err := findSeatsHeldByOthers(tx, bookingEntity.FlightCode, bookingEntity.UserID, addedSeats)
+	if err != nil {
+		return err
+	}
+	if len(heldSeats) > 0 {
+		return errors.NewSeatHeldError(bookingEntity.FlightCode, heldSeats, 409)
+	}
+
+	for _, seat := range addedSeats {
+		if err := tx.Omit(clause.Associations).Create(&entities.SeatEntity{
+			BookingID:  bookingEntity.ID,
+			FlightCode: bookingEntity.FlightCode,
+			Row:        seat.Row,
+			Column:     seat.Column,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
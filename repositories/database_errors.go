@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	apperrors "flyhorizons-bookingservice/services/errors"
+	"net"
+
+	"gorm.io/gorm"
+)
+
+// Wraps the errors of an unreachable database in a DatabaseUnavailableError,
+// so they can be told apart from queries that failed
+func translateDatabaseError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var unavailableErr *apperrors.DatabaseUnavailableError
+	if errors.As(err, &unavailableErr) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return apperrors.NewDatabaseUnavailableError(err, 503)
+	}
+	return err
+}
+
+// Like CreateConnection, but a connection failure is reported as the database being unavailable
+func (dal *BaseRepository) connect() (*gorm.DB, error) {
+	db, err := dal.CreateConnection()
+	if err != nil {
+		return nil, apperrors.NewDatabaseUnavailableError(err, 503)
+	}
+	return db, nil
+}
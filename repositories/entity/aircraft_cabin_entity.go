@@ -0,0 +1,15 @@
+package entities
+
+type AircraftCabinEntity struct {
+	ID              int    `gorm:"column:ID;primaryKey"`
+	ConfigurationID int    `gorm:"column:ConfigurationID;index"` // Foreign key for the AircraftConfiguration table
+	FlightClass     int    `gorm:"column:FlightClass"`
+	FirstRow        int    `gorm:"column:FirstRow"`
+	LastRow         int    `gorm:"column:LastRow"`
+	Columns         string `gorm:"column:Columns"` // Seat letters of every row, "-" marks an aisle, e.g. "ABC-DEF"
+}
+
+// Override the default table name
+func (AircraftCabinEntity) TableName() string {
+	return "AircraftCabin"
+}
@@ -0,0 +1,17 @@
+package entities
+
+type AircraftConfigurationEntity struct {
+	ID                    int                   `gorm:"column:ID;primaryKey"`
+	Name                  string                `gorm:"column:Name"`                              // E.g. "A320 180Y"
+	Cabins                []AircraftCabinEntity `gorm:"foreignKey:ConfigurationID;references:ID"` // One-to-many relationship
+	ExitRows              string                `gorm:"column:ExitRows;type:string"`              // JSON list of row numbers (string)
+	ExtraLegroomRows      string                `gorm:"column:ExtraLegroomRows;type:string"`      // JSON list of row numbers (string)
+	RestrictedReclineRows string                `gorm:"column:RestrictedReclineRows;type:string"` // JSON list of row numbers (string)
+	BassinetSeats         string                `gorm:"column:BassinetSeats;type:string"`         // JSON list of seats with a bassinet position, e.g. ["1A"] (string)
+	BlockedSeats          string                `gorm:"column:BlockedSeats;type:string"`          // JSON list of seats that cannot be sold, e.g. ["12C"] (string)
+}
+
+// Override the default table name
+func (AircraftConfigurationEntity) TableName() string {
+	return "AircraftConfiguration"
+}
@@ -0,0 +1,15 @@
+package entities
+
+type AncillaryEntity struct {
+	Code        string `gorm:"column:Code;primaryKey"`
+	Category    string `gorm:"column:Category"`
+	Name        string `gorm:"column:Name"`
+	Description string `gorm:"column:Description"`
+	Currency    string `gorm:"column:Currency"`           // ISO 4217 code of the prices, e.g. "EUR"
+	Prices      string `gorm:"column:Prices;type:string"` // JSON object of flight class to price in cents, e.g. {"Economy":1200,"Business":0} (string)
+}
+
+// Override the default table name
+func (AncillaryEntity) TableName() string {
+	return "Ancillary"
+}
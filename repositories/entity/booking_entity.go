@@ -1,6 +1,10 @@
 package entities
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type BookingEntity struct {
 	ID          int               `gorm:"column:ID;primaryKey"`
@@ -8,13 +12,23 @@ type BookingEntity struct {
 	FlightCode  string            `gorm:"column:FlightCode"`
 	FlightClass int               `gorm:"column:FlightClass"`
 	CreatedAt   time.Time         `gorm:"column:CreatedAt"`
+	DepartureAt *time.Time        `gorm:"column:DepartureAt"`                 // Unknown for bookings made before it was recorded
 	Passengers  []PassengerEntity `gorm:"foreignKey:BookingID;references:ID"` // One-to-many relationship
 	Seats       []SeatEntity      `gorm:"foreignKey:BookingID;references:ID"` // One-to-many relationship
-	Luggage     string            `gorm:"column:Luggage;type:string"`         // JSON list of integers (string)
 	Status      string            `gorm:"column:Status"`
+	Version     int               `gorm:"column:Version;not null;default:1"` // Incremented on every change
+	DeletedAt   gorm.DeletedAt    `gorm:"column:DeletedAt;index"`            // Soft-deleted bookings are hidden from every query until they are purged
 }
 
 // Override the default table name
 func (BookingEntity) TableName() string {
 	return "Booking"
 }
+
+// Copies the flight code onto the seats, the seat uniqueness per flight is enforced on the Seat table
+func (booking *BookingEntity) BeforeSave(tx *gorm.DB) error {
+	for i := range booking.Seats {
+		booking.Seats[i].FlightCode = booking.FlightCode
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+type BookingStatusHistoryEntity struct {
+	ID         int       `gorm:"column:ID;primaryKey"`
+	BookingID  int       `gorm:"column:BookingID;index"` // Foreign key for the Booking table
+	FromStatus string    `gorm:"column:FromStatus"`      // Empty for the initial status of the booking
+	ToStatus   string    `gorm:"column:ToStatus"`
+	Reason     string    `gorm:"column:Reason"`
+	ChangedAt  time.Time `gorm:"column:ChangedAt"`
+}
+
+// Override the default table name
+func (BookingStatusHistoryEntity) TableName() string {
+	return "BookingStatusHistory"
+}
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// All prices are in cents of the Currency of the fare
+type FlightFareEntity struct {
+	FlightCode     string     `gorm:"column:FlightCode;primaryKey"`
+	FlightClass    int        `gorm:"column:FlightClass;primaryKey"`
+	DepartureAt    *time.Time `gorm:"column:DepartureAt"`                // Same for every flight class, the passengers' ages are taken on this date
+	Currency       string     `gorm:"column:Currency"`                   // ISO 4217 code, e.g. "EUR"
+	BaseFareCents  int64      `gorm:"column:BaseFareCents"`              // Fare of an adult passenger
+	SeatSurcharges string     `gorm:"column:SeatSurcharges;type:string"` // JSON object of seat attribute to surcharge, e.g. {"ExtraLegroom":2500} (string)
+	LuggagePrices  string     `gorm:"column:LuggagePrices;type:string"`  // JSON object of luggage item to price, e.g. {"Cargo20kg":3500} (string)
+}
+
+// Override the default table name
+func (FlightFareEntity) TableName() string {
+	return "FlightFare"
+}
@@ -0,0 +1,12 @@
+package entities
+
+type FlightSeatConfigurationEntity struct {
+	FlightCode      string                      `gorm:"column:FlightCode;primaryKey"`
+	ConfigurationID int                         `gorm:"column:ConfigurationID"`                   // Foreign key for the AircraftConfiguration table
+	Configuration   AircraftConfigurationEntity `gorm:"foreignKey:ConfigurationID;references:ID"` // Relationship to AircraftConfigurationEntity
+}
+
+// Override the default table name
+func (FlightSeatConfigurationEntity) TableName() string {
+	return "FlightSeatConfiguration"
+}
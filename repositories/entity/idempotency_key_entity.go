@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+type IdempotencyKeyEntity struct {
+	Key          string    `gorm:"column:Key;primaryKey"`
+	RequestHash  string    `gorm:"column:RequestHash"`              // SHA-256 of the method, path and body of the first request
+	StatusCode   int       `gorm:"column:StatusCode"`               // 0 while the first request is still being processed
+	ResponseBody string    `gorm:"column:ResponseBody;type:string"` // Replayed on retries
+	CreatedAt    time.Time `gorm:"column:CreatedAt"`
+	ExpiresAt    time.Time `gorm:"column:ExpiresAt;index"`
+}
+
+// Override the default table name
+func (IdempotencyKeyEntity) TableName() string {
+	return "IdempotencyKey"
+}
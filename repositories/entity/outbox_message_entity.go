@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+type OutboxMessageEntity struct {
+	ID            int        `gorm:"column:ID;primaryKey"`
+	Queue         string     `gorm:"column:Queue"`
+	Payload       string     `gorm:"column:Payload"` // JSON body of the message
+	Attempts      int        `gorm:"column:Attempts"`
+	LastError     string     `gorm:"column:LastError"`
+	CreatedAt     time.Time  `gorm:"column:CreatedAt"`
+	NextAttemptAt time.Time  `gorm:"column:NextAttemptAt;index"`
+	DispatchedAt  *time.Time `gorm:"column:DispatchedAt;index"` // Nil until the message has been published
+}
+
+// Override the default table name
+func (OutboxMessageEntity) TableName() string {
+	return "OutboxMessage"
+}
@@ -10,6 +10,8 @@ type PassengerEntity struct {
 	DateOfBirth    time.Time     `gorm:"column:DateOfBirth"`
 	PassportNumber string        `gorm:"column:PassportNumber"`
 	Email          string        `gorm:"column:Email"`
+	Luggage        string        `gorm:"column:Luggage;type:string"`     // JSON list of luggage items with their quantity (string)
+	Ancillaries    string        `gorm:"column:Ancillaries;type:string"` // JSON list of ancillary codes (string)
 }
 
 // Override the default table name
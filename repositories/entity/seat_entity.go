@@ -1,11 +1,13 @@
 package entities
 
 type SeatEntity struct {
-	ID        int           `gorm:"column:ID;primaryKey"`
-	BookingID int           `gorm:"column:BookingID;index"`             // Foreign key for the Booking table
-	Booking   BookingEntity `gorm:"foreignKey:BookingID;references:ID"` // Relationship to BookingEntity
-	Row       int           `gorm:"column:Row"`
-	Column    string        `gorm:"column:Column"`
+	ID         int           `gorm:"column:ID;primaryKey"`
+	BookingID  int           `gorm:"column:BookingID;index"`                                              // Foreign key for the Booking table
+	Booking    BookingEntity `gorm:"foreignKey:BookingID;references:ID"`                                  // Relationship to BookingEntity
+	FlightCode string        `gorm:"column:FlightCode;uniqueIndex:UX_Seat_FlightSeat,where:Released = 0"` // Copied from the Booking, a seat can only be booked once per flight
+	Row        int           `gorm:"column:Row;uniqueIndex:UX_Seat_FlightSeat"`
+	Column     string        `gorm:"column:Column;uniqueIndex:UX_Seat_FlightSeat"`
+	Released   bool          `gorm:"column:Released"` // Seats of cancelled bookings can be booked again
 }
 
 // Override the default table name
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+type SeatHoldEntity struct {
+	ID         int       `gorm:"column:ID;primaryKey"`
+	HoldID     string    `gorm:"column:HoldID;index"` // Shared by all seats held together
+	UserID     int       `gorm:"column:UserID"`
+	FlightCode string    `gorm:"column:FlightCode;uniqueIndex:UX_SeatHold_Seat"`
+	Row        int       `gorm:"column:Row;uniqueIndex:UX_SeatHold_Seat"`
+	Column     string    `gorm:"column:Column;uniqueIndex:UX_SeatHold_Seat"`
+	CreatedAt  time.Time `gorm:"column:CreatedAt"`
+	ExpiresAt  time.Time `gorm:"column:ExpiresAt;index"`
+}
+
+// Override the default table name
+func (SeatHoldEntity) TableName() string {
+	return "SeatHold"
+}
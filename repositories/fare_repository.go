@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/interfaces"
+)
+
+type FareRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.FareRepository = (*FareRepository)(nil)
+
+func NewFareRepository(baseRepo *BaseRepository) *FareRepository {
+	return &FareRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+// Returns a FareNotFoundError when no fare has been configured for the flight class of the flight
+func (repo *FareRepository) GetByFlight(flightCode string, flightClass enums.FlightClass) (*entities.FlightFareEntity, error) {
+	db, err := repo.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	var fare entities.FlightFareEntity
+	result := db.Where("FlightCode = ? AND FlightClass = ?", flightCode, int(flightClass)).Limit(1).Find(&fare)
+	if result.Error != nil {
+		return nil, translateDatabaseError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.NewFareNotFoundError(flightCode, flightClass, 404)
+	}
+	return &fare, nil
+}
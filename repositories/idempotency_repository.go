@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"errors"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.IdempotencyRepository = (*IdempotencyRepository)(nil)
+
+func NewIdempotencyRepository(baseRepo *BaseRepository) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+// Claims the key for a new request, returns nil when the key was claimed
+// or the existing record when the key has been used before and has not expired yet
+func (repo *IdempotencyRepository) Reserve(key entities.IdempotencyKeyEntity) (*entities.IdempotencyKeyEntity, error) {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	// An expired key can be used again
+	if err := db.Where("[Key] = ? AND ExpiresAt <= ?", key.Key, time.Now()).Delete(&entities.IdempotencyKeyEntity{}).Error; err != nil {
+		return nil, err
+	}
+
+	// The primary key makes sure only one of two concurrent requests claims the key
+	createErr := db.Create(&key).Error
+	if createErr == nil {
+		return nil, nil
+	}
+
+	var existing entities.IdempotencyKeyEntity
+	err = db.Where("[Key] = ?", key.Key).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, createErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (repo *IdempotencyRepository) Complete(key string, statusCode int, responseBody string) error {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&entities.IdempotencyKeyEntity{}).
+		Where("[Key] = ?", key).
+		Updates(map[string]interface{}{"StatusCode": statusCode, "ResponseBody": responseBody}).Error
+}
+
+func (repo *IdempotencyRepository) Release(key string) error {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return err
+	}
+
+	return db.Where("[Key] = ?", key).Delete(&entities.IdempotencyKeyEntity{}).Error
+}
+
+// Releases the key when its request is still in flight since before reservedBefore, returns whether it was released
+// A completed key is never released, so its response keeps being replayed
+func (repo *IdempotencyRepository) ReleaseAbandoned(key string, reservedBefore time.Time) (bool, error) {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return false, err
+	}
+
+	result := db.Where("[Key] = ? AND StatusCode = 0 AND CreatedAt < ?", key, reservedBefore).Delete(&entities.IdempotencyKeyEntity{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (repo *IdempotencyRepository) DeleteExpired() (int64, error) {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Where("ExpiresAt <= ?", time.Now()).Delete(&entities.IdempotencyKeyEntity{})
+	return result.RowsAffected, result.Error
+}
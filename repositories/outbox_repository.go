@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.OutboxRepository = (*OutboxRepository)(nil)
+
+func NewOutboxRepository(baseRepo *BaseRepository) *OutboxRepository {
+	return &OutboxRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+func (repo *OutboxRepository) GetPending(limit int) ([]entities.OutboxMessageEntity, error) {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []entities.OutboxMessageEntity
+
+	// Oldest first, so the messages are published in the order they were written
+	err = db.Where("DispatchedAt IS NULL AND NextAttemptAt <= ?", time.Now()).
+		Order("ID").
+		Limit(limit).
+		Find(&messages).Error
+
+	return messages, err
+}
+
+func (repo *OutboxRepository) MarkDispatched(id int) error {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&entities.OutboxMessageEntity{}).
+		Where("ID = ?", id).
+		Update("DispatchedAt", time.Now()).Error
+}
+
+func (repo *OutboxRepository) MarkFailed(id int, lastError string, nextAttemptAt time.Time) error {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&entities.OutboxMessageEntity{}).
+		Where("ID = ?", id).
+		Updates(map[string]interface{}{
+			"Attempts":      gorm.Expr("Attempts + 1"),
+			"LastError":     lastError,
+			"NextAttemptAt": nextAttemptAt,
+		}).Error
+}
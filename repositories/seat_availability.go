@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"flyhorizons-bookingservice/models"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Returns the requested seats that are booked, or held by a user other than userID
+func findUnavailableSeats(db *gorm.DB, flightCode string, userID int, requested []models.Seat) ([]models.Seat, error) {
+	heldSeats, err := findSeatsHeldByOthers(db, flightCode, userID, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	bookedSeats, err := findBookedSeats(db, flightCode, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := map[string]bool{}
+	for _, seat := range append(bookedSeats, heldSeats...) {
+		taken[seatKey(seat.Row, seat.Column)] = true
+	}
+	return filterSeats(requested, taken), nil
+}
+
+// Returns the requested seats that are booked by an active booking on the flight
+func findBookedSeats(db *gorm.DB, flightCode string, requested []models.Seat) ([]models.Seat, error) {
+	var bookedSeats []entities.SeatEntity
+	if err := db.Where("FlightCode = ? AND Released = ?", flightCode, false).Find(&bookedSeats).Error; err != nil {
+		return nil, err
+	}
+
+	booked := map[string]bool{}
+	for _, seat := range bookedSeats {
+		booked[seatKey(seat.Row, seat.Column)] = true
+	}
+	return filterSeats(requested, booked), nil
+}
+
+// Returns the requested seats with an active hold of a user other than userID
+func findSeatsHeldByOthers(db *gorm.DB, flightCode string, userID int, requested []models.Seat) ([]models.Seat, error) {
+	var holds []entities.SeatHoldEntity
+	if err := db.Where("FlightCode = ? AND UserID <> ? AND ExpiresAt > ?", flightCode, userID, time.Now()).
+		Find(&holds).Error; err != nil {
+		return nil, err
+	}
+
+	held := map[string]bool{}
+	for _, hold := range holds {
+		held[seatKey(hold.Row, hold.Column)] = true
+	}
+	return filterSeats(requested, held), nil
+}
+
+func filterSeats(seats []models.Seat, keep map[string]bool) []models.Seat {
+	filtered := []models.Seat{}
+	for _, seat := range seats {
+		if keep[seatKey(seat.Row, seat.Column)] {
+			filtered = append(filtered, models.Seat{Row: seat.Row, Column: seat.Column})
+		}
+	}
+	return filtered
+}
+
+func seatKey(row int, column string) string {
+	return fmt.Sprintf("%d%s", row, column)
+}
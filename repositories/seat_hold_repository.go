@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"flyhorizons-bookingservice/models"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/interfaces"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SeatHoldRepository struct {
+	*BaseRepository
+}
+
+var _ interfaces.SeatHoldRepository = (*SeatHoldRepository)(nil)
+
+func NewSeatHoldRepository(baseRepo *BaseRepository) *SeatHoldRepository {
+	return &SeatHoldRepository{
+		BaseRepository: baseRepo,
+	}
+}
+
+// Holds the seats for a single user and flight, fails with a SeatHeldError when
+// any of the seats is booked or held by another user
+func (repo *SeatHoldRepository) Create(holds []entities.SeatHoldEntity) error {
+	if len(holds) == 0 {
+		return nil
+	}
+
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return err
+	}
+
+	flightCode, userID := holds[0].FlightCode, holds[0].UserID
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Expired holds no longer reserve anything, even when the sweeper has not removed them yet
+		if err := tx.Where("FlightCode = ? AND ExpiresAt <= ?", flightCode, time.Now()).Delete(&entities.SeatHoldEntity{}).Error; err != nil {
+			return err
+		}
+
+		// Holding a seat again replaces the previous hold of the same user
+		for _, hold := range holds {
+			if err := tx.Where("FlightCode = ? AND Row = ? AND [Column] = ? AND UserID = ?", flightCode, hold.Row, hold.Column, userID).
+				Delete(&entities.SeatHoldEntity{}).Error; err != nil {
+				return err
+			}
+		}
+
+		unavailableSeats, err := findUnavailableSeats(tx, flightCode, userID, convertSeatHoldsToSeats(holds))
+		if err != nil {
+			return err
+		}
+		if len(unavailableSeats) > 0 {
+			return errors.NewSeatHeldError(flightCode, unavailableSeats, 409)
+		}
+
+		return tx.Create(&holds).Error
+	})
+
+	if err != nil {
+		if _, ok := err.(*errors.SeatHeldError); ok {
+			return err
+		}
+		// Another user may have held one of the seats at the same time, which violates the unique index
+		if unavailableSeats, checkErr := findUnavailableSeats(db, flightCode, userID, convertSeatHoldsToSeats(holds)); checkErr == nil && len(unavailableSeats) > 0 {
+			return errors.NewSeatHeldError(flightCode, unavailableSeats, 409)
+		}
+	}
+	return err
+}
+
+func (repo *SeatHoldRepository) GetActiveByHoldID(holdID string) ([]entities.SeatHoldEntity, error) {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var holds []entities.SeatHoldEntity
+	err = db.Where("HoldID = ? AND ExpiresAt > ?", holdID, time.Now()).Order("Row, [Column]").Find(&holds).Error
+
+	return holds, err
+}
+
+func (repo *SeatHoldRepository) GetActiveByUser(userID int, flightCode string) ([]entities.SeatHoldEntity, error) {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var holds []entities.SeatHoldEntity
+	err = db.Where("UserID = ? AND FlightCode = ? AND ExpiresAt > ?", userID, flightCode, time.Now()).Order("Row, [Column]").Find(&holds).Error
+
+	return holds, err
+}
+
+func (repo *SeatHoldRepository) Extend(holdID string, expiresAt time.Time) error {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return err
+	}
+
+	result := db.Model(&entities.SeatHoldEntity{}).
+		Where("HoldID = ? AND ExpiresAt > ?", holdID, time.Now()).
+		Update("ExpiresAt", expiresAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.NewSeatHoldNotFoundError(holdID, 404)
+	}
+	return nil
+}
+
+func (repo *SeatHoldRepository) Release(holdID string) error {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return err
+	}
+
+	return db.Where("HoldID = ?", holdID).Delete(&entities.SeatHoldEntity{}).Error
+}
+
+func (repo *SeatHoldRepository) DeleteExpired() (int64, error) {
+	db, err := repo.CreateConnection()
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Where("ExpiresAt <= ?", time.Now()).Delete(&entities.SeatHoldEntity{})
+	return result.RowsAffected, result.Error
+}
+
+func convertSeatHoldsToSeats(holds []entities.SeatHoldEntity) []models.Seat {
+	var seats []models.Seat
+	for _, hold := range holds {
+		seats = append(seats, models.Seat{Row: hold.Row, Column: hold.Column})
+	}
+	return seats
+}
@@ -1,52 +1,68 @@
 package repositories
 
 import (
+	"errors"
+	"flyhorizons-bookingservice/models"
 	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
 	"fmt"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type SeatRepository struct {
 	*BaseRepository
 }
 
+var _ interfaces.SeatRepository = (*SeatRepository)(nil)
+
 func NewSeatRepository(baseRepo *BaseRepository) *SeatRepository {
 	return &SeatRepository{
 		BaseRepository: baseRepo,
 	}
 }
 
-func (r *SeatRepository) GetByFlightCode(flightCode string) ([]entities.SeatOptionEntity, error) {
+// Returns the aircraft configuration the flight is operated with, or nil when the flight has none
+func (r *SeatRepository) GetConfigurationByFlightCode(flightCode string) (*entities.AircraftConfigurationEntity, error) {
 	db, err := r.CreateConnection()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create DB connection: %v", err)
 	}
 
-	var results []entities.SeatOptionEntity
-
-	err = db.Raw(`
-		SELECT 
-			so.Row AS row,
-			so.[Column] AS seat_column,
-			CASE 
-				WHEN s.BookingID IS NULL THEN 1  -- Available (no booking)
-				ELSE 0  -- Booked (has a booking)
-			END AS status
-		FROM 
-			SeatOption so
-		LEFT JOIN 
-			Seat s ON so.Row = s.Row AND so.[Column] = s.[Column]
-		LEFT JOIN 
-			Booking b ON s.BookingID = b.ID 
-		WHERE 
-			(b.FlightCode = ? OR b.FlightCode IS NULL)  -- Select only bookings for the FlightID
-		ORDER BY 
-			so.Row, so.[Column]
-	`, flightCode).Scan(&results).Error
+	var flightConfiguration entities.FlightSeatConfigurationEntity
+
+	// This preloads the related Configuration and its Cabins
+	err = db.Preload("Configuration.Cabins").Where("FlightCode = ?", flightCode).First(&flightConfiguration).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		fmt.Printf("Error fetching the seat configuration for flightCode %s: %v\n", flightCode, err)
+		return nil, err
+	}
+
+	return &flightConfiguration.Configuration, nil
+}
 
+// Returns the seats of the flight that are booked by an active booking or held by any user
+func (r *SeatRepository) GetOccupiedSeats(flightCode string) ([]models.Seat, error) {
+	db, err := r.CreateConnection()
 	if err != nil {
-		fmt.Printf("Error fetching seat options for flightCode %s: %v\n", flightCode, err)
+		return nil, fmt.Errorf("failed to create DB connection: %v", err)
+	}
+
+	var bookedSeats []entities.SeatEntity
+	if err := db.Where("FlightCode = ? AND Released = ?", flightCode, false).Find(&bookedSeats).Error; err != nil {
+		return nil, err
+	}
+
+	var holds []entities.SeatHoldEntity
+	if err := db.Where("FlightCode = ? AND ExpiresAt > ?", flightCode, time.Now()).Find(&holds).Error; err != nil {
 		return nil, err
 	}
 
-	return results, nil
+	occupiedSeats := convertSeatEntitiesToSeats(bookedSeats)
+	occupiedSeats = append(occupiedSeats, convertSeatHoldsToSeats(holds)...)
+	return occupiedSeats, nil
 }
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/services/authorization"
+	"flyhorizons-bookingservice/services/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handles searching the bookings of all users
+func RegisterAdminBookingRoutes(router *gin.Engine, bookingService interfaces.BookingService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	adminBookingGroup := router.Group("/admin/bookings")
+	adminBookingGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected routes
+	// Can only be accessible by support agents and administrators
+	adminBookingGroup.Use(authorization.RequirePermission(authorization.ReadAnyBooking))
+
+	// e.g. /admin/bookings?flight_code=FR788&status=Confirmed&sort=created_at&order=desc&limit=20
+	adminBookingGroup.GET("", func(ctx *gin.Context) {
+		criteria, ok := bindBookingSearchCriteria(ctx)
+		if !ok {
+			return
+		}
+		if userID := ctx.Query("user_id"); userID != "" {
+			var err error
+			if criteria.UserID, err = strconv.Atoi(userID); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+				return
+			}
+		}
+
+		result, err := bookingService.Search(criteria)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	})
+
+	// Permanently deletes the bookings deleted, cancelled, refunded or expired before deleted_before, used for the retention policies
+	// e.g. {"deleted_before": "2025-01-01T00:00:00Z"}
+	// Can only be accessible by administrators
+	adminBookingGroup.POST("/purge", authorization.RequirePermission(authorization.PurgeBookings), func(ctx *gin.Context) {
+		var request models.PurgeRequest
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		purged, err := bookingService.Purge(request.DeletedBefore)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"purged": purged})
+	})
+}
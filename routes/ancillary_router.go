@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/services/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAncillaryRoutes(router *gin.Engine, ancillaryService interfaces.AncillaryService) {
+	// The catalog is public like the seat map, e.g. GET /bookings/ancillaries?flight_class=1
+	router.GET("/bookings/ancillaries", func(ctx *gin.Context) {
+		flightClass, err := strconv.Atoi(ctx.DefaultQuery("flight_class", "0"))
+		if err != nil || (enums.FlightClass(flightClass) != enums.Economy && enums.FlightClass(flightClass) != enums.Business) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "flight_class must be 0 (Economy) or 1 (Business)"})
+			return
+		}
+
+		ancillaries, err := ancillaryService.GetAvailable(enums.FlightClass(flightClass))
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, ancillaries)
+	})
+}
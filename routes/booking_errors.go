@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"flyhorizons-bookingservice/services/errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Translates the errors of the BookingService into the same status code on every booking route
+func respondWithBookingError(ctx *gin.Context, err error) {
+	switch typedErr := err.(type) {
+	// 400 Bad Request
+	case *errors.InvalidSearchCriteriaError, *errors.InvalidPaymentError, *errors.InvalidQuoteError:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case *errors.InvalidSeatSelectionError:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "seats": typedErr.Seats})
+	case *errors.InvalidBookingError:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "violations": typedErr.Violations})
+	case *errors.InvalidLuggageError:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "violations": typedErr.Violations})
+	case *errors.InvalidAncillaryError:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "violations": typedErr.Violations})
+	// 404 Not Found
+	case *errors.BookingNotFoundError, *errors.SeatMapNotFoundError, *errors.FareNotFoundError, *errors.DepartureNotFoundError:
+		ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	// 409 Conflict, the seats that are taken are returned so they can be deselected
+	case *errors.BookingExistsError:
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case *errors.SeatHeldError:
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error(), "seats": typedErr.Seats})
+	case *errors.SeatAlreadyBookedError:
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error(), "seats": typedErr.Seats})
+	// e.g. a booking that is already cancelled cannot be cancelled again, or a boarded booking changed
+	case *errors.InvalidStatusTransitionError, *errors.BookingNotModifiableError:
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	// 410 Gone, a new quote has to be requested
+	case *errors.QuoteExpiredError:
+		ctx.JSON(http.StatusGone, gin.H{"message": err.Error()})
+	// 412 Precondition Failed, the booking has to be read again before it can be updated
+	case *errors.BookingVersionConflictError:
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"message": err.Error()})
+	// 503 Service Unavailable, the details of the connection failure are only logged
+	case *errors.DatabaseUnavailableError:
+		log.Printf("Database unavailable: %v", typedErr.Err)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"message": "The booking service is temporarily unavailable, please try again later"})
+	// 500 Internal Server Error
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"flyhorizons-bookingservice/models"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Binds the booking in the request body like ShouldBindJSON does,
+// raw card and bank details are rejected wherever they are in the body
+func bindBooking(ctx *gin.Context, booking *models.Booking) error {
+	return bindWithoutRawPaymentDetails(ctx, booking)
+}
+
+// Binds the payment in the request body, the same way as a booking
+func bindPayment(ctx *gin.Context, payment *models.Payment) error {
+	return bindWithoutRawPaymentDetails(ctx, payment)
+}
+
+func bindWithoutRawPaymentDetails(ctx *gin.Context, obj interface{}) error {
+	if ctx.Request.Body == nil {
+		return fmt.Errorf("invalid request")
+	}
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return err
+	}
+	if err := models.RejectRawPaymentDetails(body); err != nil {
+		return err
+	}
+	return binding.JSON.BindBody(body, obj)
+}
@@ -2,7 +2,7 @@ package routes
 
 import (
 	"flyhorizons-bookingservice/models"
-	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/authorization"
 	"flyhorizons-bookingservice/services/interfaces"
 	"net/http"
 	"strconv"
@@ -10,69 +10,125 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const accessDeniedMessage = "unauthorized: cannot access the bookings belonging to another user"
+
 // Handles the booking CRUD functionality
-func RegisterBookingRoutes(router *gin.Engine, bookingService interfaces.BookingService, authMiddleware interfaces.GatewayAuthMiddleware) {
-	// Public routes
-	router.DELETE("/bookings/:ID", func(ctx *gin.Context) {
-		bookingIDString := ctx.Param("ID")
+func RegisterBookingRoutes(router *gin.Engine, bookingService interfaces.BookingService, authMiddleware interfaces.GatewayAuthMiddleware, idempotencyMiddleware interfaces.IdempotencyMiddleware) {
+	bookingGroup := router.Group("/bookings")
+	bookingGroup.Use(authMiddleware.GatewayAuthMiddleware())
 
-		// Convert string to int
-		bookingID, err := strconv.Atoi(bookingIDString)
+	// Protected routes
+	// Can only be accessible by the logged in user (userID)
+	// Retries with the same Idempotency-Key replay the first response instead of creating another booking
+	bookingGroup.POST("", authorization.RequirePermission(authorization.CreateBooking), idempotencyMiddleware.IdempotencyMiddleware(), func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
 
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookingID"})
+		userID, ok := userIDRaw.(int)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
 			return
 		}
 
-		success, err := bookingService.DeleteByBookingID(bookingID)
-		if err != nil {
-			if _, ok := err.(*errors.BookingNotFoundError); ok {
-				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()}) // 404 Not Found
-				return
-			}
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		var booking models.Booking
+
+		if err := bindBooking(ctx, &booking); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		// Uses success to confirm the deletion
-		if success {
-			ctx.JSON(http.StatusOK, gin.H{
-				"message": "Booking deleted successfully",
-			})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"message": "Failed to delete Booking, but no error has occurred",
-			})
+
+		// The booking always belongs to the logged in user, whatever user_id the body contains
+		booking.UserID = userID
+
+		postBooking, err := bookingService.Create(booking)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
 		}
+		ctx.JSON(http.StatusCreated, postBooking)
 	})
 
-	router.POST("/bookings", func(ctx *gin.Context) {
-		var booking models.Booking
+	// Returns the itemized price of the booking, and a quote_id that can be passed to POST /bookings
+	// to be charged exactly this price until the quote expires
+	bookingGroup.POST("/quote", authorization.RequirePermission(authorization.CreateBooking), func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
+
+		userID, ok := userIDRaw.(int)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
+			return
+		}
 
-		if err := ctx.ShouldBindJSON(&booking); err != nil {
+		var booking models.Booking
+		if err := bindBooking(ctx, &booking); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		postBooking, err := bookingService.Create(booking)
+		// The quote is made for the logged in user, like the booking it is used for
+		booking.UserID = userID
+
+		quote, err := bookingService.Quote(booking)
 		if err != nil {
-			// 409 Conflict
-			if _, ok := err.(*errors.BookingExistsError); ok {
-				ctx.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+			respondWithBookingError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, quote)
+	})
+
+	// Cancels the booking instead of deleting it, the booking and its history are kept and its seats are released
+	// An optional body {"reason": "..."} is recorded in the status history
+	// Can only be accessible by the owner of the booking or an administrator
+	bookingGroup.DELETE("/:ID", authorization.RequirePermission(authorization.DeleteOwnBookings, authorization.DeleteAnyBooking), func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
+
+		if _, ok := userIDRaw.(int); !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
+			return
+		}
+
+		bookingIDString := ctx.Param("ID")
+
+		// Convert string to int
+		bookingID, err := strconv.Atoi(bookingIDString)
+
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookingID"})
+			return
+		}
+
+		booking, err := bookingService.GetByID(bookingID)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+		if !authorization.CanAccessBooking(ctx, booking.UserID, authorization.DeleteAnyBooking) {
+			authorization.Deny(ctx, authorization.ReasonNotBookingOwner, accessDeniedMessage)
+			return
+		}
+
+		var cancellation models.BookingCancellation
+		if ctx.Request.ContentLength != 0 {
+			if err := ctx.ShouldBindJSON(&cancellation); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			}
-			// 500 Internal Server Error
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+		if cancellation.Reason == "" {
+			cancellation.Reason = "Cancelled by user"
+		}
+
+		if err := bookingService.Cancel(bookingID, cancellation.Reason); err != nil {
+			respondWithBookingError(ctx, err)
 			return
 		}
-		ctx.JSON(http.StatusCreated, postBooking)
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "Booking cancelled successfully",
+		})
 	})
 
-	bookingGroup := router.Group("/bookings")
-	bookingGroup.Use(authMiddleware.GatewayAuthMiddleware())
-
-	// Protected routes
-	// Can only be accessible by the logged in user (userID)
-	bookingGroup.GET("/", func(ctx *gin.Context) {
+	// Supports the filters, sorting and paging of the admin search, e.g. ?timeframe=upcoming&status=Confirmed&limit=20
+	// Support agents and administrators can pass ?user_id= to look up the bookings of another user
+	bookingGroup.GET("/", authorization.RequirePermission(authorization.ReadOwnBookings, authorization.ReadAnyBooking), func(ctx *gin.Context) {
 		userIDRaw, _ := ctx.Get("user_id")
 
 		userID, ok := userIDRaw.(int)
@@ -81,50 +137,216 @@ func RegisterBookingRoutes(router *gin.Engine, bookingService interfaces.Booking
 			return
 		}
 
-		bookings := bookingService.GetByUserID(userID)
+		if requestedUserID := ctx.Query("user_id"); requestedUserID != "" {
+			otherUserID, err := strconv.Atoi(requestedUserID)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+				return
+			}
+			if !authorization.CanAccessBooking(ctx, otherUserID, authorization.ReadAnyBooking) {
+				authorization.Deny(ctx, authorization.ReasonNotBookingOwner, accessDeniedMessage)
+				return
+			}
+			userID = otherUserID
+		}
+
+		criteria, ok := bindBookingSearchCriteria(ctx)
+		if !ok {
+			return
+		}
+
+		result, err := bookingService.GetByUserID(userID, criteria)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
 
 		// Check that the bookings userID matches the logged in user
-		for _, booking := range bookings {
+		for _, booking := range result.Bookings {
 			if userID != booking.UserID {
-				ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: cannot access the bookings belonging to another user"})
+				authorization.Deny(ctx, authorization.ReasonNotBookingOwner, accessDeniedMessage)
 				return
 			}
 		}
 
-		ctx.JSON(http.StatusOK, bookings)
+		// The body stays a plain list of bookings, the paging metadata is sent in the headers
+		ctx.Header("X-Total-Count", strconv.FormatInt(result.TotalCount, 10))
+		if result.NextCursor != "" {
+			ctx.Header("X-Next-Cursor", result.NextCursor)
+		}
+		ctx.JSON(http.StatusOK, result.Bookings)
 	})
 
-	bookingGroup.PUT("/", func(ctx *gin.Context) {
+	// Clients can send the ETag of their copy in If-None-Match, a 304 Not Modified is returned when it is still up to date
+	bookingGroup.GET("/:ID", authorization.RequirePermission(authorization.ReadOwnBookings, authorization.ReadAnyBooking), func(ctx *gin.Context) {
 		userIDRaw, _ := ctx.Get("user_id")
 
-		userID, ok := userIDRaw.(int)
-		if !ok {
+		if _, ok := userIDRaw.(int); !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
+			return
+		}
+
+		bookingID, err := strconv.Atoi(ctx.Param("ID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookingID"})
+			return
+		}
+
+		booking, err := bookingService.GetByID(bookingID)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+
+		// Check that the booking belongs to the logged in user
+		if !authorization.CanAccessBooking(ctx, booking.UserID, authorization.ReadAnyBooking) {
+			authorization.Deny(ctx, authorization.ReasonNotBookingOwner, accessDeniedMessage)
+			return
+		}
+
+		etag := computeETag(booking)
+		ctx.Header("ETag", etag)
+		// The booking can only be cached by the client, as it holds personal data
+		ctx.Header("Cache-Control", "private, no-cache")
+		if etagMatches(ctx, etag) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+		ctx.JSON(http.StatusOK, booking)
+	})
+
+	// Support agents and administrators can modify the bookings of any user
+	// The ETag of the booking has to be sent in If-Match, so changes made since it was read are not overwritten
+	bookingGroup.PUT("/", authorization.RequirePermission(authorization.UpdateOwnBookings, authorization.UpdateAnyBooking), func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
+
+		if _, ok := userIDRaw.(int); !ok {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
 			return
 		}
 
+		// 428 Precondition Required
+		if ctx.GetHeader("If-Match") == "" {
+			ctx.JSON(http.StatusPreconditionRequired, gin.H{"message": "The If-Match header with the ETag of the booking is required"})
+			return
+		}
+
 		var booking models.Booking
 		// Convert JSON to a Booking object
-		if err := ctx.ShouldBindJSON(&booking); err != nil {
+		if err := bindBooking(ctx, &booking); err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
 		// Check if the booking userID matches the logged in user
-		if userID != booking.UserID {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": "unauthorized: cannot access the bookings belonging to another user"})
+		if !authorization.CanAccessBooking(ctx, booking.UserID, authorization.UpdateAnyBooking) {
+			authorization.Deny(ctx, authorization.ReasonNotBookingOwner, accessDeniedMessage)
+			return
+		}
+
+		currentBooking, err := bookingService.GetByID(booking.ID)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+		if !authorization.CanAccessBooking(ctx, currentBooking.UserID, authorization.UpdateAnyBooking) {
+			authorization.Deny(ctx, authorization.ReasonNotBookingOwner, accessDeniedMessage)
+			return
+		}
+
+		// 412 Precondition Failed, the current ETag is returned so the client knows its copy is outdated
+		currentETag := computeETag(currentBooking)
+		if !ifMatchSatisfied(ctx, currentETag) {
+			ctx.Header("ETag", currentETag)
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{"message": "The booking has been changed since it was read"})
 			return
 		}
 
+		// The update is only applied to the version the ETag belongs to, whatever version the body contains
+		booking.Version = currentBooking.Version
 		put_booking, err := bookingService.Update(booking)
 		if err != nil {
-			if _, ok := err.(*errors.BookingNotFoundError); ok {
-				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
-				return
-			}
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			respondWithBookingError(ctx, err)
 			return
 		}
+
+		etag := computeETag(put_booking)
+		ctx.Header("ETag", etag)
 		ctx.JSON(http.StatusOK, put_booking)
 	})
+
+	// Retries the payment of a booking whose payment failed, the body is the new payment with its payment token
+	// Can only be accessible by the owner of the booking, support agents and administrators
+	bookingGroup.POST("/:ID/payment", authorization.RequirePermission(authorization.UpdateOwnBookings, authorization.UpdateAnyBooking), func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
+
+		if _, ok := userIDRaw.(int); !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
+			return
+		}
+
+		bookingID, err := strconv.Atoi(ctx.Param("ID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookingID"})
+			return
+		}
+
+		var payment models.Payment
+		if err := bindPayment(ctx, &payment); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		booking, err := bookingService.GetByID(bookingID)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+		if !authorization.CanAccessBooking(ctx, booking.UserID, authorization.UpdateAnyBooking) {
+			authorization.Deny(ctx, authorization.ReasonNotBookingOwner, accessDeniedMessage)
+			return
+		}
+
+		retriedBooking, err := bookingService.RetryPayment(bookingID, payment)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+		ctx.Header("ETag", computeETag(retriedBooking))
+		ctx.JSON(http.StatusOK, retriedBooking)
+	})
+
+	bookingGroup.GET("/:ID/status-history", authorization.RequirePermission(authorization.ReadOwnBookings, authorization.ReadAnyBooking), func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
+
+		if _, ok := userIDRaw.(int); !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
+			return
+		}
+
+		bookingID, err := strconv.Atoi(ctx.Param("ID"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookingID"})
+			return
+		}
+
+		history, err := bookingService.GetStatusHistory(bookingID)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+
+		// Check that the booking belongs to the logged in user
+		booking, err := bookingService.GetByID(bookingID)
+		if err != nil {
+			respondWithBookingError(ctx, err)
+			return
+		}
+		if !authorization.CanAccessBooking(ctx, booking.UserID, authorization.ReadAnyBooking) {
+			authorization.Deny(ctx, authorization.ReasonNotBookingOwner, accessDeniedMessage)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, history)
+	})
 }
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Reads the filters, sorting and paging of a booking listing from the query string
+// Replies with 400 Bad Request and returns false when a parameter cannot be parsed
+func bindBookingSearchCriteria(ctx *gin.Context) (models.BookingSearchCriteria, bool) {
+	criteria := models.BookingSearchCriteria{
+		FlightCode:     ctx.Query("flight_code"),
+		Status:         enums.Status(ctx.Query("status")),
+		Timeframe:      ctx.Query("timeframe"),
+		PassengerName:  ctx.Query("passenger_name"),
+		PassportNumber: ctx.Query("passport_number"),
+		SortBy:         ctx.Query("sort"),
+		SortOrder:      ctx.Query("order"),
+		Cursor:         ctx.Query("cursor"),
+	}
+
+	if limit := ctx.Query("limit"); limit != "" {
+		var err error
+		if criteria.Limit, err = strconv.Atoi(limit); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return criteria, false
+		}
+	}
+	// Dates are RFC 3339 timestamps, e.g. 2025-04-03T09:00:00Z
+	if createdFrom := ctx.Query("created_from"); createdFrom != "" {
+		date, err := time.Parse(time.RFC3339, createdFrom)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid created_from"})
+			return criteria, false
+		}
+		criteria.CreatedFrom = &date
+	}
+	if createdTo := ctx.Query("created_to"); createdTo != "" {
+		date, err := time.Parse(time.RFC3339, createdTo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid created_to"})
+			return criteria, false
+		}
+		criteria.CreatedTo = &date
+	}
+	return criteria, true
+}
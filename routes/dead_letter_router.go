@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"flyhorizons-bookingservice/services/authorization"
+	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/interfaces"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultDeadLetterLimit = 50
+
+// Handles inspecting and replaying the dead-lettered RabbitMQ messages
+func RegisterDeadLetterRoutes(router *gin.Engine, deadLetterService interfaces.DeadLetterService, authMiddleware interfaces.GatewayAuthMiddleware) {
+	deadLetterGroup := router.Group("/admin/dead-letters")
+	deadLetterGroup.Use(authMiddleware.GatewayAuthMiddleware())
+
+	// Protected routes
+	// Can only be accessible by administrators
+	deadLetterGroup.Use(authorization.RequirePermission(authorization.ManageDeadLetters))
+
+	deadLetterGroup.GET("/:queue", func(ctx *gin.Context) {
+		limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultDeadLetterLimit)))
+		if err != nil || limit < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+
+		messages, err := deadLetterService.List(ctx.Param("queue"), limit)
+		if err != nil {
+			if _, ok := err.(*errors.UnknownQueueError); ok {
+				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, messages)
+	})
+
+	deadLetterGroup.POST("/:queue/replay", func(ctx *gin.Context) {
+		limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultDeadLetterLimit)))
+		if err != nil || limit < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+
+		replayed, err := deadLetterService.Replay(ctx.Param("queue"), limit)
+		if err != nil {
+			if _, ok := err.(*errors.UnknownQueueError); ok {
+				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error(), "replayed": replayed})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"replayed": replayed})
+	})
+}
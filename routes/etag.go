@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flyhorizons-bookingservice/models"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Strong ETag of a booking, every change of the booking increments its version
+// The response body is not hashed, the passengers and seats are loaded in no particular order
+func computeETag(booking *models.Booking) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d:%d", booking.ID, booking.Version)))
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// Checks the If-Match header of an update, which can list several ETags
+// Weak ETags and "*" are not accepted, the client has to send the ETag of the booking it read
+func ifMatchSatisfied(ctx *gin.Context, etag string) bool {
+	for _, candidate := range strings.Split(ctx.GetHeader("If-Match"), ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks the If-None-Match header, which can list several ETags or "*"
+func etagMatches(ctx *gin.Context, etag string) bool {
+	ifNoneMatch := ctx.GetHeader("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
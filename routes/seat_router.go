@@ -1,21 +1,119 @@
 package routes
 
 import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/services/authorization"
+	"flyhorizons-bookingservice/services/errors"
 	"flyhorizons-bookingservice/services/interfaces"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterSeatRoutes(router *gin.Engine, seatService interfaces.SeatService) {
+func RegisterSeatRoutes(router *gin.Engine, seatService interfaces.SeatService, seatHoldService interfaces.SeatHoldService, authMiddleware interfaces.GatewayAuthMiddleware) {
 	router.GET("/bookings/seats/:flightCode", func(ctx *gin.Context) {
 		flightCode := ctx.Param("flightCode")
 		seats, err := seatService.GetByFlightCode(flightCode)
 
 		if err != nil {
+			if _, ok := err.(*errors.SeatMapNotFoundError); ok {
+				ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()}) // 404 Not Found
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
 		ctx.JSON(http.StatusOK, seats)
 	})
+
+	// Protected routes
+	// Seats are held for the logged in user (userID) during checkout
+	seatHoldGroup := router.Group("/bookings/seat-holds")
+	seatHoldGroup.Use(authMiddleware.GatewayAuthMiddleware())
+	// Seats are only held to book them afterwards
+	seatHoldGroup.Use(authorization.RequirePermission(authorization.CreateBooking))
+
+	seatHoldGroup.POST("", func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
+
+		userID, ok := userIDRaw.(int)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
+			return
+		}
+
+		var request models.SeatHoldRequest
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		seatHold, err := seatHoldService.Hold(userID, request)
+		if err != nil {
+			respondWithSeatHoldError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, seatHold)
+	})
+
+	seatHoldGroup.PUT("/:holdID", func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
+
+		userID, ok := userIDRaw.(int)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
+			return
+		}
+
+		seatHold, err := seatHoldService.Extend(userID, ctx.Param("holdID"))
+		if err != nil {
+			respondWithSeatHoldError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, seatHold)
+	})
+
+	seatHoldGroup.DELETE("/:holdID", func(ctx *gin.Context) {
+		userIDRaw, _ := ctx.Get("user_id")
+
+		userID, ok := userIDRaw.(int)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "userID not a string"})
+			return
+		}
+
+		if err := seatHoldService.Release(userID, ctx.Param("holdID")); err != nil {
+			respondWithSeatHoldError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "Seat hold released successfully"})
+	})
+}
+
+func respondWithSeatHoldError(ctx *gin.Context, err error) {
+	// 400 Bad Request, the seats that cannot be held are returned so they can be deselected
+	if invalidSeatHoldErr, ok := err.(*errors.InvalidSeatHoldError); ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "seats": invalidSeatHoldErr.Seats})
+		return
+	}
+	// 409 Conflict, the seats that are taken are returned so they can be deselected
+	if seatHeldErr, ok := err.(*errors.SeatHeldError); ok {
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error(), "seats": seatHeldErr.Seats})
+		return
+	}
+	if _, ok := err.(*errors.SeatHoldLimitError); ok {
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	// 404 Not Found
+	if _, ok := err.(*errors.SeatHoldNotFoundError); ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	if _, ok := err.(*errors.SeatMapNotFoundError); ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	// 500 Internal Server Error
+	ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 }
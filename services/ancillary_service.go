@@ -0,0 +1,45 @@
+package services
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/services/interfaces"
+)
+
+type AncillaryService struct {
+	ancillaryRepo interfaces.AncillaryRepository
+}
+
+var _ interfaces.AncillaryService = (*AncillaryService)(nil)
+
+func NewAncillaryService(ancillaryRepo interfaces.AncillaryRepository) *AncillaryService {
+	return &AncillaryService{
+		ancillaryRepo: ancillaryRepo,
+	}
+}
+
+// Returns the ancillaries sold in the flight class with their price in that class,
+// an ancillary without a price for the flight class is not available in it
+func (s *AncillaryService) GetAvailable(flightClass enums.FlightClass) ([]models.Ancillary, error) {
+	ancillaryEntities, err := s.ancillaryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	ancillaries := []models.Ancillary{}
+	for _, entity := range ancillaryEntities {
+		price, ok := pricesFromJSONString(entity.Prices)[flightClass.String()]
+		if !ok {
+			continue
+		}
+		ancillaries = append(ancillaries, models.Ancillary{
+			Code:        entity.Code,
+			Category:    enums.AncillaryCategory(entity.Category),
+			Name:        entity.Name,
+			Description: entity.Description,
+			Currency:    entity.Currency,
+			PriceCents:  price,
+		})
+	}
+	return ancillaries, nil
+}
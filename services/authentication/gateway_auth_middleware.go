@@ -1,28 +1,26 @@
 package authentication
 
 import (
+	"flyhorizons-bookingservice/config"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
-	"github.com/joho/godotenv"
 )
 
-type GatewayAuthMiddlewareHandler struct{}
+type GatewayAuthMiddlewareHandler struct {
+	config config.AuthConfig
+	// Nil when no JWKS is configured, RS256 and ES256 tokens are rejected then
+	keySet *KeySet
+	parser *jwt.Parser
+}
 
 func (g *GatewayAuthMiddlewareHandler) GatewayAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Load .env file
-		if err := godotenv.Load(); err != nil {
-			log.Println("No .env file found, relying on environment variables")
-		}
-
-		JwtSecret := []byte(os.Getenv("JWT_SECRET"))
-
 		// Get JWT token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 
@@ -33,13 +31,8 @@ func (g *GatewayAuthMiddlewareHandler) GatewayAuthMiddleware() gin.HandlerFunc {
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// Parse the JWT token
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return JwtSecret, nil
-		})
+		// Parse the JWT token, this also rejects expired tokens and tokens that are not valid yet (nbf)
+		token, err := g.parser.Parse(tokenStr, g.getVerificationKey)
 
 		if err != nil || !token.Valid {
 			fmt.Println("JWT parsing failed:", err)
@@ -53,10 +46,10 @@ func (g *GatewayAuthMiddlewareHandler) GatewayAuthMiddleware() gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid JWT claims"})
 			return
 		}
-
-		fmt.Println("--- Extracted JWT Claims ---")
-		for k, v := range claims {
-			fmt.Printf("%s: %v\n", k, v)
+		if err := g.verifyClaims(claims); err != nil {
+			fmt.Println("JWT claims rejected:", err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid JWT claims"})
+			return
 		}
 
 		// Set claims
@@ -75,6 +68,61 @@ func (g *GatewayAuthMiddlewareHandler) GatewayAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
-func NewGatewayAuthMiddleware() *GatewayAuthMiddlewareHandler {
-	return &GatewayAuthMiddlewareHandler{}
+// Picks the key matching the signing method of the token
+func (g *GatewayAuthMiddlewareHandler) getVerificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if g.config.JWTSecret == "" {
+			return nil, fmt.Errorf("HMAC tokens are not accepted")
+		}
+		return []byte(g.config.JWTSecret), nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		if g.keySet == nil {
+			return nil, fmt.Errorf("no JWKS configured for %v tokens", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return g.keySet.GetKey(kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+}
+
+// The parser only checks exp when it is present, tokens without an expiry are rejected here
+func (g *GatewayAuthMiddlewareHandler) verifyClaims(claims jwt.MapClaims) error {
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return fmt.Errorf("missing or expired exp claim")
+	}
+	if g.config.Issuer != "" && !claims.VerifyIssuer(g.config.Issuer, true) {
+		return fmt.Errorf("unexpected issuer: %v", claims["iss"])
+	}
+	if g.config.Audience != "" && !claims.VerifyAudience(g.config.Audience, true) {
+		return fmt.Errorf("unexpected audience: %v", claims["aud"])
+	}
+	return nil
+}
+
+func NewGatewayAuthMiddleware(authConfig config.AuthConfig) *GatewayAuthMiddlewareHandler {
+	validMethods := []string{}
+	if authConfig.JWTSecret != "" {
+		validMethods = append(validMethods, "HS256")
+	}
+
+	// Without an expected issuer and audience any token signed with the keys is accepted, also tokens meant for other services
+	if authConfig.Issuer == "" {
+		log.Println("WARNING: JWT_ISSUER is not set, the iss claim of the tokens is not checked")
+	}
+	if authConfig.Audience == "" {
+		log.Println("WARNING: JWT_AUDIENCE is not set, the aud claim of the tokens is not checked")
+	}
+
+	var keySet *KeySet
+	if authConfig.JWKSSource != "" {
+		keySet = NewKeySet(authConfig.JWKSSource, authConfig.JWKSRefreshInterval)
+		validMethods = append(validMethods, "RS256", "ES256")
+	}
+
+	return &GatewayAuthMiddlewareHandler{
+		config: authConfig,
+		keySet: keySet,
+		parser: jwt.NewParser(jwt.WithValidMethods(validMethods)),
+	}
 }
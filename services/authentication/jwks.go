@@ -0,0 +1,204 @@
+package authentication
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Caches the public keys of a JSON Web Key Set by their kid
+// The set is fetched again when a token is signed with an unknown kid, so the gateway can rotate its keys
+type KeySet struct {
+	source             string
+	minRefreshInterval time.Duration
+	client             *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]interface{}
+	lastRefresh time.Time
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// The source is either an http(s) URL or the path of a local JWKS file
+func NewKeySet(source string, minRefreshInterval time.Duration) *KeySet {
+	keySet := &KeySet{
+		source:             source,
+		minRefreshInterval: minRefreshInterval,
+		client:             &http.Client{Timeout: 5 * time.Second},
+		keys:               map[string]interface{}{},
+	}
+
+	// The service still starts when the JWKS is unavailable, the keys are fetched again on the first token
+	if err := keySet.Refresh(); err != nil {
+		log.Printf("Failed to load the JWKS from %s: %v", source, err)
+	}
+	return keySet
+}
+
+func (k *KeySet) GetKey(kid string) (interface{}, error) {
+	if key, ok := k.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	if err := k.refreshIfStale(); err != nil {
+		return nil, err
+	}
+	if key, ok := k.cachedKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key '%s'", kid)
+}
+
+// Tokens without a kid are only accepted when the set has a single key
+func (k *KeySet) cachedKey(kid string) (interface{}, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+func (k *KeySet) Refresh() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.refresh()
+}
+
+// Concurrent requests with the same unknown kid only fetch the set once
+func (k *KeySet) refreshIfStale() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if time.Since(k.lastRefresh) < k.minRefreshInterval {
+		return nil
+	}
+	return k.refresh()
+}
+
+// The caller must hold the write lock
+func (k *KeySet) refresh() error {
+	k.lastRefresh = time.Now()
+	data, err := k.read()
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	k.keys = keys
+	return nil
+}
+
+func (k *KeySet) read() ([]byte, error) {
+	if !strings.HasPrefix(k.source, "http://") && !strings.HasPrefix(k.source, "https://") {
+		return os.ReadFile(strings.TrimPrefix(k.source, "file://"))
+	}
+
+	response, err := k.client.Get(k.source)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from the JWKS endpoint", response.StatusCode)
+	}
+	return io.ReadAll(response.Body)
+}
+
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range jwks.Keys {
+		// Encryption keys cannot verify signatures
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.Printf("Skipping JWKS key '%s': %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve '%s'", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve '%s'", jwk.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type '%s'", jwk.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
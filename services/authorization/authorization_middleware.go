@@ -0,0 +1,47 @@
+package authorization
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Machine-readable reasons of a 403 Forbidden response
+const (
+	ReasonMissingPermission = "missing_permission"
+	ReasonNotBookingOwner   = "not_booking_owner"
+)
+
+// Reads the role set by the GatewayAuthMiddleware
+func RoleFromContext(ctx *gin.Context) Role {
+	role, _ := ctx.Get("role")
+	roleString, _ := role.(string)
+	return Role(roleString)
+}
+
+// Only lets the request through when the role of the logged in user grants one of the permissions
+func RequirePermission(permissions ...Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := RoleFromContext(ctx)
+		for _, permission := range permissions {
+			if role.HasPermission(permission) {
+				ctx.Next()
+				return
+			}
+		}
+		Deny(ctx, ReasonMissingPermission, "unauthorized: your role does not allow this action")
+	}
+}
+
+// Owners can always access their own booking, anyone else needs the "any" permission (e.g. ReadAnyBooking)
+func CanAccessBooking(ctx *gin.Context, ownerID int, anyPermission Permission) bool {
+	userID, _ := ctx.Get("user_id")
+	if userID == ownerID {
+		return true
+	}
+	return RoleFromContext(ctx).HasPermission(anyPermission)
+}
+
+func Deny(ctx *gin.Context, reason string, message string) {
+	ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": message, "reason": reason})
+}
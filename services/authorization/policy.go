@@ -0,0 +1,66 @@
+package authorization
+
+type Role string
+
+const (
+	Customer     Role = "customer"
+	SupportAgent Role = "support-agent"
+	Admin        Role = "admin"
+	// Tokens issued before the roles were introduced use "user" for customers
+	User Role = "user"
+)
+
+type Permission string
+
+const (
+	CreateBooking     Permission = "bookings:create"
+	ReadOwnBookings   Permission = "bookings:read:own"
+	ReadAnyBooking    Permission = "bookings:read:any"
+	UpdateOwnBookings Permission = "bookings:update:own"
+	UpdateAnyBooking  Permission = "bookings:update:any"
+	DeleteOwnBookings Permission = "bookings:delete:own"
+	DeleteAnyBooking  Permission = "bookings:delete:any"
+	ManageDeadLetters Permission = "dead-letters:manage"
+	PurgeBookings     Permission = "bookings:purge"
+)
+
+var customerPermissions = []Permission{
+	CreateBooking,
+	ReadOwnBookings,
+	UpdateOwnBookings,
+	DeleteOwnBookings,
+}
+
+// Every role lists the permissions it grants, roles that are not listed have no permissions
+var rolePermissions = map[Role][]Permission{
+	Customer: customerPermissions,
+	User:     customerPermissions,
+	// Support agents help customers with their bookings, but cannot delete them
+	SupportAgent: {
+		CreateBooking,
+		ReadOwnBookings,
+		ReadAnyBooking,
+		UpdateOwnBookings,
+		UpdateAnyBooking,
+	},
+	Admin: {
+		CreateBooking,
+		ReadOwnBookings,
+		ReadAnyBooking,
+		UpdateOwnBookings,
+		UpdateAnyBooking,
+		DeleteOwnBookings,
+		DeleteAnyBooking,
+		ManageDeadLetters,
+		PurgeBookings,
+	},
+}
+
+func (role Role) HasPermission(permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -2,124 +2,648 @@ package services
 
 import (
 	"encoding/json"
-	"flyhorizons-bookingservice/config"
 	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
 	"flyhorizons-bookingservice/services/converter"
 	"flyhorizons-bookingservice/services/errors"
 	"flyhorizons-bookingservice/services/interfaces"
+	"fmt"
 	"log"
+	"time"
+)
 
-	"github.com/rabbitmq/amqp091-go"
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
 )
 
 type BookingService struct {
 	bookingRepo        interfaces.BookingRepository
+	seatService        interfaces.SeatService
+	pricingService     interfaces.PricingService
+	ancillaryService   interfaces.AncillaryService
 	bookingConverter   converter.BookingConverter
 	passengerConverter converter.PassengerConverter
 	seatConverter      converter.SeatConverter
 }
 
-func NewBookingService(repo interfaces.BookingRepository, bookingConverter converter.BookingConverter, passengerConverter converter.PassengerConverter, seatConverter converter.SeatConverter) *BookingService {
+func NewBookingService(repo interfaces.BookingRepository, seatService interfaces.SeatService, pricingService interfaces.PricingService, ancillaryService interfaces.AncillaryService, bookingConverter converter.BookingConverter, passengerConverter converter.PassengerConverter, seatConverter converter.SeatConverter) *BookingService {
 	return &BookingService{
 		bookingRepo:        repo,
+		seatService:        seatService,
+		pricingService:     pricingService,
+		ancillaryService:   ancillaryService,
 		bookingConverter:   bookingConverter,
 		passengerConverter: passengerConverter,
 		seatConverter:      seatConverter,
 	}
 }
 
-func (s *BookingService) BookingExists(bookingID int) bool {
-	for _, booking := range s.bookingRepo.GetAll() {
-		if booking.ID == bookingID {
-			return true
-		}
+func (s *BookingService) BookingExists(bookingID int) (bool, error) {
+	return s.bookingRepo.Exists(bookingID)
+}
+
+// Returns a BookingNotFoundError for an unknown ID, and a DatabaseUnavailableError when the database cannot be reached
+func (s *BookingService) GetByID(id int) (*models.Booking, error) {
+	bookingEntity, err := s.bookingRepo.GetByID(id)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	booking := s.bookingConverter.ConvertBookingEntityToBooking(*bookingEntity)
+	return &booking, nil
 }
 
-func (s *BookingService) GetByID(id int) models.Booking {
-	bookingEntity := s.bookingRepo.GetByID(id)
-	booking := s.bookingConverter.ConvertBookingEntityToBooking(bookingEntity)
-	return booking
+func (s *BookingService) GetByUserID(userID int, criteria models.BookingSearchCriteria) (*models.BookingSearchResult, error) {
+	criteria, err := validateSearchCriteria(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	bookingEntities, totalCount, nextCursor, err := s.bookingRepo.GetByUserID(userID, criteria)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertSearchResult(bookingEntities, totalCount, nextCursor), nil
 }
 
-func (s *BookingService) GetByUserID(userID int) []models.Booking {
-	bookingEntities := s.bookingRepo.GetByUserID(userID)
-	var bookings []models.Booking
+func (s *BookingService) Search(criteria models.BookingSearchCriteria) (*models.BookingSearchResult, error) {
+	criteria, err := validateSearchCriteria(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	bookingEntities, totalCount, nextCursor, err := s.bookingRepo.Search(criteria)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertSearchResult(bookingEntities, totalCount, nextCursor), nil
+}
+
+// Fills in the default sorting and page size, and rejects criteria the repository cannot search on
+func validateSearchCriteria(criteria models.BookingSearchCriteria) (models.BookingSearchCriteria, error) {
+	// The newest bookings are shown first by default
+	if criteria.SortBy == "" {
+		criteria.SortBy = "created_at"
+	}
+	if criteria.SortOrder == "" {
+		criteria.SortOrder = "desc"
+	}
+	if criteria.Limit == 0 {
+		criteria.Limit = DefaultSearchLimit
+	}
+
+	if criteria.SortOrder != "asc" && criteria.SortOrder != "desc" {
+		return criteria, errors.NewInvalidSearchCriteriaError("order", criteria.SortOrder, 400)
+	}
+	if criteria.Limit < 1 || criteria.Limit > MaxSearchLimit {
+		return criteria, errors.NewInvalidSearchCriteriaError("limit", fmt.Sprint(criteria.Limit), 400)
+	}
+	if criteria.Status != "" && !criteria.Status.IsValid() {
+		return criteria, errors.NewInvalidSearchCriteriaError("status", string(criteria.Status), 400)
+	}
+	if criteria.Timeframe != "" && criteria.Timeframe != models.TimeframeUpcoming && criteria.Timeframe != models.TimeframePast {
+		return criteria, errors.NewInvalidSearchCriteriaError("timeframe", criteria.Timeframe, 400)
+	}
+	if criteria.CreatedFrom != nil && criteria.CreatedTo != nil && !criteria.CreatedTo.After(*criteria.CreatedFrom) {
+		return criteria, errors.NewInvalidSearchCriteriaError("created_to", criteria.CreatedTo.String(), 400)
+	}
+	return criteria, nil
+}
+
+func (s *BookingService) convertSearchResult(bookingEntities []entities.BookingEntity, totalCount int64, nextCursor string) *models.BookingSearchResult {
+	bookings := []models.Booking{}
 	for _, entity := range bookingEntities {
 		bookings = append(bookings, s.bookingConverter.ConvertBookingEntityToBooking(entity))
 	}
-	return bookings
+	return &models.BookingSearchResult{Bookings: bookings, TotalCount: totalCount, NextCursor: nextCursor}
+}
+
+// Prices the booking without creating it, the seats, luggage and ancillaries are checked like they are when the booking is created
+func (s *BookingService) Quote(booking models.Booking) (*models.Quote, error) {
+	if err := validateBooking(booking); err != nil {
+		return nil, err
+	}
+	if err := s.validateSeatSelection(booking); err != nil {
+		return nil, err
+	}
+	if err := validateLuggage(booking); err != nil {
+		return nil, err
+	}
+	if err := s.validateAncillaries(booking); err != nil {
+		return nil, err
+	}
+	if err := s.setDeparture(&booking); err != nil {
+		return nil, err
+	}
+	return s.pricingService.Quote(booking)
 }
 
 func (s *BookingService) Create(booking models.Booking) (*models.Booking, error) {
-	if s.BookingExists(booking.ID) {
+	// The payment service charges the token, so a booking without one could never be paid
+	if booking.Payment.Token == "" {
+		return nil, errors.NewInvalidPaymentError("a payment token is required", 400)
+	}
+
+	exists, err := s.BookingExists(booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
 		return nil, errors.NewBookingExistsError(booking.ID, 409)
 	}
+	if err := validateBooking(booking); err != nil {
+		return nil, err
+	}
+	if err := s.validateSeatSelection(booking); err != nil {
+		return nil, err
+	}
+	if err := validateLuggage(booking); err != nil {
+		return nil, err
+	}
+	if err := s.validateAncillaries(booking); err != nil {
+		return nil, err
+	}
+	if err := s.setDeparture(&booking); err != nil {
+		return nil, err
+	}
+
+	// The amount is always calculated here or taken from a signed quote, whatever amount the client sent
+	price, err := s.priceBooking(booking)
+	if err != nil {
+		return nil, err
+	}
+	booking.Payment.Amount = price.Total()
+	booking.Payment.Currency = price.Currency
 
 	// Set the initial booking status to "Pending"
 	// This is when the booking payment has not been (successfully) processed yet
 	booking.Status = enums.Pending
 	bookingEntity := s.bookingConverter.ConvertBookingToBookingEntity(booking)
 
-	createdEntityPtr := s.bookingRepo.Create(bookingEntity)
-	if createdEntityPtr == nil {
+	// The booking and its booking.created event are written in one transaction,
+	// the OutboxRelay publishes the event to RabbitMQ afterwards
+	var createdEntity entities.BookingEntity
+	err = s.bookingRepo.Transaction(func(repo interfaces.BookingRepository) error {
+		createdEntityPtr, err := repo.Create(bookingEntity)
+		if err != nil {
+			return err
+		}
+		createdEntity = *createdEntityPtr
+
+		// Extract the payment information from the original request
+		// Only the payment token is published, raw card data never reaches the booking service
+		paymentRequest := models.PaymentRequest{
+			BookingID: createdEntity.ID,
+			Payment:   booking.Payment,
+			Price:     *price,
+		}
+		body, err := json.Marshal(paymentRequest)
+		if err != nil {
+			return err
+		}
+		if err := repo.AddOutboxMessage("booking.created", body); err != nil {
+			return err
+		}
+
+		// The payment has been requested, so the booking now waits for its payment
+		createdEntity.Status = string(enums.AwaitingPayment)
+		return repo.UpdateStatus(createdEntity.ID, enums.Pending, enums.AwaitingPayment, "Payment requested")
+	})
+	if err != nil {
+		log.Printf("Error creating booking: %v\n", err)
+		switch err.(type) {
+		case *errors.SeatAlreadyBookedError, *errors.SeatHeldError, *errors.DatabaseUnavailableError:
+			return nil, err
+		}
 		return nil, errors.NewBookingCreateError(booking.ID, 500)
 	}
 
-	createdEntity := *createdEntityPtr
 	createdBooking := s.bookingConverter.ConvertBookingEntityToBooking(createdEntity)
+	createdBooking.Price = price
+	return &createdBooking, nil
+}
 
-	// Extract the payment information from the original request
-	paymentRequest := models.PaymentRequest{
-		BookingID: createdBooking.ID,
-		Payment:   booking.Payment,
-	}
-
-	// Publish to RabbitMQ
-	channel := config.RabbitMQClient.Channel
-	body, err := json.Marshal(paymentRequest)
-	if err != nil {
-		log.Printf("Error marshaling payment details to JSON: %v\n", err)
-	} else {
-		err = channel.Publish(
-			"",
-			"booking.created",
-			false,
-			false,
-			amqp091.Publishing{
-				ContentType: "application/json",
-				Body:        body,
-			},
-		)
-		if err != nil {
-			log.Printf("Error publishing booking created event to RabbitMQ: %v\n", err)
+// A booking made from a quote is charged the quoted price, even when the fares have changed since
+func (s *BookingService) priceBooking(booking models.Booking) (*models.PriceBreakdown, error) {
+	if booking.QuoteID != "" {
+		return s.pricingService.PriceFromQuote(booking.QuoteID, booking)
+	}
+	return s.pricingService.Calculate(booking)
+}
+
+// Checks that the booking has passengers, no more seats than passengers and every seat only once
+func validateBooking(booking models.Booking) error {
+	violations := []string{}
+	if len(booking.Passengers) == 0 {
+		violations = append(violations, "a booking needs at least one passenger")
+	}
+	if len(booking.Seats) > len(booking.Passengers) {
+		violations = append(violations, fmt.Sprintf("%d seats were selected for %d passengers", len(booking.Seats), len(booking.Passengers)))
+	}
+	// The same seat twice would otherwise only be rejected by the Seat table when the booking is stored
+	selectedSeats := map[string]bool{}
+	for _, seat := range booking.Seats {
+		seatName := fmt.Sprintf("%d%s", seat.Row, seat.Column)
+		if selectedSeats[seatName] {
+			violations = append(violations, fmt.Sprintf("seat %s was selected more than once", seatName))
 		}
+		selectedSeats[seatName] = true
 	}
 
-	return &createdBooking, nil
+	if len(violations) > 0 {
+		return errors.NewInvalidBookingError(violations, 400)
+	}
+	return nil
+}
+
+// Replaces the departure sent by the client with the departure of the flight, a flight that has departed cannot be booked
+// The departure decides the age bands of the passengers, and whether the booking is upcoming or past
+func (s *BookingService) setDeparture(booking *models.Booking) error {
+	departureAt, err := s.pricingService.GetDeparture(booking.FlightCode, booking.FlightClass)
+	if err != nil {
+		return err
+	}
+	if departureAt.Before(time.Now()) {
+		violation := fmt.Sprintf("flight %s departed at %s", booking.FlightCode, departureAt.UTC().Format(time.RFC3339))
+		return errors.NewInvalidBookingError([]string{violation}, 400)
+	}
+	booking.DepartureAt = departureAt
+	return nil
+}
+
+// The flight and flight class decide the fare of the booking, they cannot be changed once it is booked
+func validateBookingChange(currentBooking models.Booking, booking models.Booking) error {
+	violations := []string{}
+	if booking.FlightCode != currentBooking.FlightCode {
+		violations = append(violations, "the flight code cannot be changed")
+	}
+	if booking.FlightClass != currentBooking.FlightClass {
+		violations = append(violations, "the flight class cannot be changed")
+	}
+
+	if len(violations) > 0 {
+		return errors.NewInvalidBookingError(violations, 400)
+	}
+	return nil
+}
+
+// Prices the booking before and after the change with the current fares, so only the change itself is compared
+// e.g. an extra checked bag or a seat with extra legroom would have to be paid for
+func (s *BookingService) validatePriceUnchanged(currentBooking models.Booking, booking models.Booking) error {
+	currentPrice, err := s.pricingService.Calculate(currentBooking)
+	if err != nil {
+		return err
+	}
+	price, err := s.pricingService.Calculate(booking)
+	if err != nil {
+		return err
+	}
+
+	if price.TotalCents != currentPrice.TotalCents {
+		violation := fmt.Sprintf("the changes would change the price of the booking from %.2f to %.2f %s", currentPrice.Total(), price.Total(), price.Currency)
+		return errors.NewInvalidBookingError([]string{violation}, 400)
+	}
+	return nil
+}
+
+// Checks that every selected seat is on the seat map of the flight, that it is not blocked, and that
+// the seats are not in a cabin above the flight class of the booking
+// Booked and held seats are left to the repository, which reports them as a conflict and knows which holds are the user's own
+func (s *BookingService) validateSeatSelection(booking models.Booking) error {
+	if len(booking.Seats) == 0 {
+		return nil
+	}
+
+	seatMap, err := s.seatService.GetByFlightCode(booking.FlightCode)
+	if err != nil {
+		return err
+	}
+	seats := map[string]models.Seat{}
+	for _, seat := range seatMap {
+		seats[fmt.Sprintf("%d%s", seat.Row, seat.Column)] = seat
+	}
+
+	invalidSeats := []models.Seat{}
+	for _, seat := range booking.Seats {
+		mapSeat, ok := seats[fmt.Sprintf("%d%s", seat.Row, seat.Column)]
+		if !ok || mapSeat.Blocked || mapSeat.Cabin > booking.FlightClass {
+			invalidSeats = append(invalidSeats, models.Seat{Row: seat.Row, Column: seat.Column, Cabin: mapSeat.Cabin})
+		}
+	}
+	if len(invalidSeats) > 0 {
+		return errors.NewInvalidSeatSelectionError(booking.FlightCode, booking.FlightClass, invalidSeats, 400)
+	}
+	return nil
 }
 
+// Checks the luggage of every passenger against the allowance of the flight class of the booking,
+// all violations are returned together so they can be corrected at once
+func validateLuggage(booking models.Booking) error {
+	violations := []string{}
+	for _, passenger := range booking.Passengers {
+		pieces := map[enums.Luggage]int{}
+		var order []enums.Luggage
+		for _, item := range passenger.Luggage {
+			if !item.Type.IsValid() {
+				violations = append(violations, fmt.Sprintf("%s: unknown luggage item %q", passenger.FullName, item.Type))
+				continue
+			}
+			if item.Quantity < 1 {
+				violations = append(violations, fmt.Sprintf("%s: the quantity of %s must be at least 1", passenger.FullName, item.Type))
+				continue
+			}
+			if item.WeightKg > item.Type.MaxWeightKg() {
+				violations = append(violations, fmt.Sprintf("%s: %s weighs %d kg, the limit is %d kg", passenger.FullName, item.Type, item.WeightKg, item.Type.MaxWeightKg()))
+			}
+			if pieces[item.Type] == 0 {
+				order = append(order, item.Type)
+			}
+			pieces[item.Type] += item.Quantity
+		}
+
+		// The same item can be listed more than once, the maximum applies to the pieces together
+		for _, luggage := range order {
+			maxCount := luggage.AllowanceIn(booking.FlightClass).MaxCount
+			if pieces[luggage] > maxCount {
+				violations = append(violations, fmt.Sprintf("%s: %d x %s exceeds the maximum of %d in %s", passenger.FullName, pieces[luggage], luggage, maxCount, booking.FlightClass))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.NewInvalidLuggageError(booking.FlightClass, violations, 400)
+	}
+	return nil
+}
+
+// Checks that the ancillaries of every passenger are available in the flight class of the booking,
+// a passenger can have a single ancillary of every category, e.g. one meal
+func (s *BookingService) validateAncillaries(booking models.Booking) error {
+	hasAncillaries := false
+	for _, passenger := range booking.Passengers {
+		hasAncillaries = hasAncillaries || len(passenger.Ancillaries) > 0
+	}
+	if !hasAncillaries {
+		return nil
+	}
+
+	available, err := s.ancillaryService.GetAvailable(booking.FlightClass)
+	if err != nil {
+		return err
+	}
+	catalog := map[string]models.Ancillary{}
+	for _, ancillary := range available {
+		catalog[ancillary.Code] = ancillary
+	}
+
+	violations := []string{}
+	for _, passenger := range booking.Passengers {
+		categories := map[enums.AncillaryCategory]bool{}
+		for _, code := range passenger.Ancillaries {
+			ancillary, ok := catalog[code]
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%s: %s is not available in %s", passenger.FullName, code, booking.FlightClass))
+				continue
+			}
+			if categories[ancillary.Category] {
+				violations = append(violations, fmt.Sprintf("%s: only one %s can be added", passenger.FullName, ancillary.Category))
+				continue
+			}
+			categories[ancillary.Category] = true
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.NewInvalidAncillaryError(booking.FlightClass, violations, 400)
+	}
+	return nil
+}
+
+// Cancels the booking with the reason, which is recorded in the status history together with the time of the cancellation
+// The seats of the booking are released, so they can be booked again
+func (s *BookingService) Cancel(bookingID int, reason string) error {
+	if reason == "" {
+		reason = "Booking cancelled"
+	}
+	return s.UpdateStatus(bookingID, enums.Cancelled, reason)
+}
+
+// Soft-deletes the booking, it is hidden from every query until it is purged
 func (s *BookingService) DeleteByBookingID(id int) (bool, error) {
-	if !s.BookingExists(id) {
-		return false, errors.NewBookingNotFoundError(id, 404)
+	if err := s.bookingRepo.DeleteByBookingID(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Permanently deletes the bookings that were deleted, cancelled, refunded or expired before closedBefore
+func (s *BookingService) Purge(closedBefore time.Time) (int64, error) {
+	return s.bookingRepo.Purge(closedBefore)
+}
+
+// Expires the bookings that were not paid before createdBefore, which releases their seats
+// Returns the number of expired bookings, bookings that were paid in the meantime are skipped
+func (s *BookingService) ExpireUnpaid(createdBefore time.Time) (int, error) {
+	bookingIDs, err := s.bookingRepo.GetUnpaidIDs(createdBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, bookingID := range bookingIDs {
+		err := s.UpdateStatus(bookingID, enums.Expired, "Payment not received in time")
+		switch err.(type) {
+		case nil:
+			expired++
+		case *errors.BookingNotFoundError, *errors.InvalidStatusTransitionError:
+			// Paid or deleted since the unpaid bookings were read
+		default:
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+// Expires the unpaid bookings older than the payment timeout every interval, run it in a goroutine
+func (s *BookingService) StartExpirySweeper(interval time.Duration, paymentTimeout time.Duration) {
+	for {
+		expired, err := s.ExpireUnpaid(time.Now().Add(-paymentTimeout))
+		if err != nil {
+			log.Printf("Error expiring unpaid bookings: %v", err)
+		} else if expired > 0 {
+			log.Printf("Expired %d unpaid bookings", expired)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (s *BookingService) UpdateStatus(bookingID int, status enums.Status, reason string) error {
+	booking, err := s.GetByID(bookingID)
+	if err != nil {
+		return err
+	}
+
+	// Only allow the transitions defined by the booking lifecycle
+	currentStatus := booking.Status
+	if !currentStatus.CanTransitionTo(status) {
+		return errors.NewInvalidStatusTransitionError(bookingID, currentStatus, status, 409)
+	}
+
+	return s.bookingRepo.Transaction(func(repo interfaces.BookingRepository) error {
+		if err := repo.UpdateStatus(bookingID, currentStatus, status, reason); err != nil {
+			return err
+		}
+		if status != enums.Confirmed {
+			return nil
+		}
+
+		// Queue the booking.confirmed event together with the status change
+		// This is listened by the Email Service, therefore a confirmation email will be sent consecutively
+		confirmedEntity, err := repo.GetByID(bookingID)
+		if err != nil {
+			return err
+		}
+		confirmedBooking := s.bookingConverter.ConvertBookingEntityToBooking(*confirmedEntity)
+		body, err := json.Marshal(confirmedBooking)
+		if err != nil {
+			return err
+		}
+		return repo.AddOutboxMessage("booking.confirmed", body)
+	})
+}
+
+// Confirms the booking of a successful payment
+// The payment can arrive after the booking expired or was cancelled, the customer has been charged by then,
+// so a refund is requested from the payment service instead of rejecting the message
+func (s *BookingService) ConfirmPayment(bookingID int) error {
+	err := s.UpdateStatus(bookingID, enums.Confirmed, "Payment succeeded")
+	if _, ok := err.(*errors.InvalidStatusTransitionError); !ok {
+		return err
+	}
+
+	// The status is read again, the booking can have expired while it was being confirmed
+	booking, getErr := s.GetByID(bookingID)
+	if getErr != nil {
+		return getErr
+	}
+	if booking.Status != enums.Expired && booking.Status != enums.Cancelled {
+		return err
+	}
+
+	log.Printf("Booking %d was paid after it became %s, requesting a refund of the payment", bookingID, booking.Status)
+	body, err := json.Marshal(models.RefundRequest{
+		BookingID: bookingID,
+		Status:    booking.Status,
+		Reason:    fmt.Sprintf("Payment received after the booking became %s", booking.Status),
+	})
+	if err != nil {
+		return err
+	}
+	return s.bookingRepo.AddOutboxMessage("payment.refund_requested", body)
+}
+
+// Requests the payment of a booking whose payment failed again, with the new payment token of the customer
+// The quoted price is not stored with the booking, so the retry is charged the current price of the booking
+// The booking still expires PAYMENT_TIMEOUT after it was created, a payment arriving later is refunded
+func (s *BookingService) RetryPayment(bookingID int, payment models.Payment) (*models.Booking, error) {
+	if payment.Token == "" {
+		return nil, errors.NewInvalidPaymentError("a payment token is required", 400)
+	}
+
+	booking, err := s.GetByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != enums.PaymentFailed {
+		return nil, errors.NewInvalidStatusTransitionError(bookingID, booking.Status, enums.AwaitingPayment, 409)
+	}
+
+	price, err := s.pricingService.Calculate(*booking)
+	if err != nil {
+		return nil, err
+	}
+	payment.Amount = price.Total()
+	payment.Currency = price.Currency
+
+	// The payment is requested again like it was when the booking was created
+	err = s.bookingRepo.Transaction(func(repo interfaces.BookingRepository) error {
+		if err := repo.UpdateStatus(bookingID, enums.PaymentFailed, enums.AwaitingPayment, "Payment retried"); err != nil {
+			return err
+		}
+		body, err := json.Marshal(models.PaymentRequest{BookingID: bookingID, Payment: payment, Price: *price})
+		if err != nil {
+			return err
+		}
+		return repo.AddOutboxMessage("booking.created", body)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	retriedBooking, err := s.GetByID(bookingID)
+	if err != nil {
+		return nil, err
 	}
-	return s.bookingRepo.DeleteByBookingID(id), nil
+	retriedBooking.Price = price
+	return retriedBooking, nil
 }
 
-func (s *BookingService) UpdateStatus(bookingID int, status enums.Status) {
-	s.bookingRepo.UpdateStatus(bookingID, status)
+func (s *BookingService) GetStatusHistory(bookingID int) ([]models.BookingStatusChange, error) {
+	exists, err := s.BookingExists(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewBookingNotFoundError(bookingID, 404)
+	}
+
+	historyEntities, err := s.bookingRepo.GetStatusHistory(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return s.bookingConverter.ConvertStatusHistoryEntitiesToStatusChanges(historyEntities), nil
 }
 
+// Changes the passengers and seats of the booking
+// A booking in a final status, or one that released its seats, can no longer be changed
+// The booking has been paid already, so changes that would change its price are rejected
 func (s *BookingService) Update(booking models.Booking) (*models.Booking, error) {
-	if !s.BookingExists(booking.ID) {
-		return nil, errors.NewBookingNotFoundError(booking.ID, 404)
+	currentBooking, err := s.GetByID(booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	if currentBooking.Status.IsFinal() || currentBooking.Status.ReleasesSeats() {
+		return nil, errors.NewBookingNotModifiableError(booking.ID, currentBooking.Status, 409)
+	}
+	// The booking stays with its owner and keeps the departure of its flight, whatever the body contains
+	booking.UserID = currentBooking.UserID
+	booking.DepartureAt = currentBooking.DepartureAt
+	if err := validateBookingChange(*currentBooking, booking); err != nil {
+		return nil, err
+	}
+	if err := validateBooking(booking); err != nil {
+		return nil, err
+	}
+	if err := s.validateSeatSelection(booking); err != nil {
+		return nil, err
+	}
+	if err := validateLuggage(booking); err != nil {
+		return nil, err
+	}
+	if err := s.validateAncillaries(booking); err != nil {
+		return nil, err
+	}
+	if err := s.validatePriceUnchanged(*currentBooking, booking); err != nil {
+		return nil, err
 	}
 
 	entity := s.bookingConverter.ConvertBookingToBookingEntity(booking)
-	updatedEntity := s.bookingRepo.Update(entity)
-	updatedBooking := s.bookingConverter.ConvertBookingEntityToBooking(updatedEntity)
+	updatedEntity, err := s.bookingRepo.Update(entity)
+	if err != nil {
+		return nil, err
+	}
+	updatedBooking := s.bookingConverter.ConvertBookingEntityToBooking(*updatedEntity)
 
 	return &updatedBooking, nil
 }
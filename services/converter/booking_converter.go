@@ -18,10 +18,11 @@ func (bookingConverter *BookingConverter) ConvertBookingEntityToBooking(entity e
 		UserID:      entity.UserID,
 		FlightCode:  entity.FlightCode,
 		FlightClass: enums.FlightClassFromInt(entity.FlightClass),
-		Luggage:     enums.LuggageClassesFromJSONString(entity.Luggage),
+		DepartureAt: entity.DepartureAt,
 		Seats:       bookingConverter.seatConverter.ConvertSeatEntitiesToSeats(entity.Seats),
 		Passengers:  bookingConverter.passengerConverter.ConvertPassengerEntitiesToPassengers(entity.Passengers),
-		Status:      enums.Status(entity.Status),
+		Status:      enums.StatusFromString(entity.Status),
+		Version:     entity.Version,
 	}
 }
 
@@ -32,8 +33,9 @@ func (bookingConverter *BookingConverter) ConvertBookingToBookingEntity(booking
 		FlightCode:  booking.FlightCode,
 		FlightClass: int(booking.FlightClass),
 		CreatedAt:   time.Now(),
-		Luggage:     enums.JSONStringToLuggageClasses(booking.Luggage),
+		DepartureAt: booking.DepartureAt,
 		Status:      string(booking.Status),
+		Version:     booking.Version,
 	}
 
 	bookingEntity.Passengers = bookingConverter.passengerConverter.ConvertPassengersToPassengerEntities(booking.Passengers, bookingEntity.ID)
@@ -41,3 +43,16 @@ func (bookingConverter *BookingConverter) ConvertBookingToBookingEntity(booking
 
 	return bookingEntity
 }
+
+func (bookingConverter *BookingConverter) ConvertStatusHistoryEntitiesToStatusChanges(historyEntities []entities.BookingStatusHistoryEntity) []models.BookingStatusChange {
+	statusChanges := []models.BookingStatusChange{}
+	for _, entity := range historyEntities {
+		statusChanges = append(statusChanges, models.BookingStatusChange{
+			FromStatus: enums.StatusFromString(entity.FromStatus),
+			ToStatus:   enums.StatusFromString(entity.ToStatus),
+			Reason:     entity.Reason,
+			ChangedAt:  entity.ChangedAt,
+		})
+	}
+	return statusChanges
+}
@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"encoding/json"
 	"flyhorizons-bookingservice/models"
 	entities "flyhorizons-bookingservice/repositories/entity"
 )
@@ -17,6 +18,8 @@ func (passengerConverter *PassengerConverter) ConvertPassengerEntitiesToPassenge
 			DateOfBirth:    entity.DateOfBirth,
 			PassportNumber: entity.PassportNumber,
 			Email:          entity.Email,
+			Luggage:        luggageFromJSONString(entity.Luggage),
+			Ancillaries:    ancillariesFromJSONString(entity.Ancillaries),
 		})
 	}
 	return passengers
@@ -32,7 +35,55 @@ func (passengerConverter *PassengerConverter) ConvertPassengersToPassengerEntiti
 			DateOfBirth:    passenger.DateOfBirth,
 			PassportNumber: passenger.PassportNumber,
 			Email:          passenger.Email,
+			Luggage:        luggageToJSONString(passenger.Luggage),
+			Ancillaries:    ancillariesToJSONString(passenger.Ancillaries),
 		})
 	}
 	return passengerEntities
 }
+
+// Unknown luggage items are left out, they cannot be priced or checked against an allowance
+func luggageFromJSONString(jsonInput string) []models.LuggageItem {
+	var items []models.LuggageItem
+	if err := json.Unmarshal([]byte(jsonInput), &items); err != nil {
+		return []models.LuggageItem{}
+	}
+
+	luggage := []models.LuggageItem{}
+	for _, item := range items {
+		if item.Type.IsValid() {
+			luggage = append(luggage, item)
+		}
+	}
+	return luggage
+}
+
+func luggageToJSONString(luggage []models.LuggageItem) string {
+	if len(luggage) == 0 {
+		return "[]"
+	}
+	jsonData, err := json.Marshal(luggage)
+	if err != nil {
+		return "[]"
+	}
+	return string(jsonData)
+}
+
+func ancillariesFromJSONString(jsonInput string) []string {
+	ancillaries := []string{}
+	if err := json.Unmarshal([]byte(jsonInput), &ancillaries); err != nil || ancillaries == nil {
+		return []string{}
+	}
+	return ancillaries
+}
+
+func ancillariesToJSONString(ancillaries []string) string {
+	if len(ancillaries) == 0 {
+		return "[]"
+	}
+	jsonData, err := json.Marshal(ancillaries)
+	if err != nil {
+		return "[]"
+	}
+	return string(jsonData)
+}
@@ -1,9 +1,13 @@
 package converter
 
 import (
+	"encoding/json"
 	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
 	entities "flyhorizons-bookingservice/repositories/entity"
 	"fmt"
+	"sort"
+	"time"
 )
 
 type SeatConverter struct {
@@ -33,15 +37,129 @@ func (seatConverter *SeatConverter) ConvertSeatsToSeatEntities(seats []models.Se
 	return seatEntities
 }
 
-func (seatConverter *SeatConverter) ConvertSeatOptionEntitiesToSeats(seatOptionEntities []entities.SeatOptionEntity) []models.Seat {
-	var seats []models.Seat
-	for _, entity := range seatOptionEntities {
-		fmt.Printf("Entity - Row: %d, Column: %s, Status: %v\n", entity.Row, entity.Column, entity.Status)
-		seats = append(seats, models.Seat{
+// Expands the cabins of the configuration into its seats, ordered by row and column
+// Blocked and occupied seats are not available
+func (seatConverter *SeatConverter) ConvertAircraftConfigurationEntityToSeats(configuration entities.AircraftConfigurationEntity, occupiedSeats []models.Seat) []models.Seat {
+	unavailable := map[string]bool{}
+	for _, seat := range occupiedSeats {
+		unavailable[seatKey(seat.Row, seat.Column)] = true
+	}
+	blockedSeats := map[string]bool{}
+	for _, seat := range seatsFromJSONString(configuration.BlockedSeats) {
+		unavailable[seat] = true
+		blockedSeats[seat] = true
+	}
+	bassinetSeats := map[string]bool{}
+	for _, seat := range seatsFromJSONString(configuration.BassinetSeats) {
+		bassinetSeats[seat] = true
+	}
+	exitRows := rowsFromJSONString(configuration.ExitRows)
+	extraLegroomRows := rowsFromJSONString(configuration.ExtraLegroomRows)
+	restrictedReclineRows := rowsFromJSONString(configuration.RestrictedReclineRows)
+
+	cabins := append([]entities.AircraftCabinEntity{}, configuration.Cabins...)
+	sort.Slice(cabins, func(i, j int) bool { return cabins[i].FirstRow < cabins[j].FirstRow })
+
+	seats := []models.Seat{}
+	for _, cabin := range cabins {
+		for row := cabin.FirstRow; row <= cabin.LastRow; row++ {
+			for i, column := range cabin.Columns {
+				if column == '-' {
+					continue
+				}
+				key := seatKey(row, string(column))
+
+				var attributes []enums.SeatAttribute
+				if exitRows[row] {
+					attributes = append(attributes, enums.ExitRow)
+				}
+				if extraLegroomRows[row] {
+					attributes = append(attributes, enums.ExtraLegroom)
+				}
+				if bassinetSeats[key] {
+					attributes = append(attributes, enums.Bassinet)
+				}
+				if restrictedReclineRows[row] {
+					attributes = append(attributes, enums.RestrictedRecline)
+				}
+
+				seats = append(seats, models.Seat{
+					Row:        row,
+					Column:     string(column),
+					Available:  !unavailable[key],
+					Blocked:    blockedSeats[key],
+					Cabin:      enums.FlightClassFromInt(cabin.FlightClass),
+					Position:   seatPosition(cabin.Columns, i),
+					Attributes: attributes,
+				})
+			}
+		}
+	}
+	return seats
+}
+
+func (seatConverter *SeatConverter) ConvertSeatsToSeatHoldEntities(seats []models.Seat, holdID string, userID int, flightCode string, createdAt time.Time, expiresAt time.Time) []entities.SeatHoldEntity {
+	var holdEntities []entities.SeatHoldEntity
+	for _, seat := range seats {
+		holdEntities = append(holdEntities, entities.SeatHoldEntity{
+			HoldID:     holdID,
+			UserID:     userID,
+			FlightCode: flightCode,
+			Row:        seat.Row,
+			Column:     seat.Column,
+			CreatedAt:  createdAt,
+			ExpiresAt:  expiresAt,
+		})
+	}
+	return holdEntities
+}
+
+func (seatConverter *SeatConverter) ConvertSeatHoldEntitiesToSeatHold(holdEntities []entities.SeatHoldEntity) models.SeatHold {
+	seatHold := models.SeatHold{Seats: []models.Seat{}}
+	for _, entity := range holdEntities {
+		seatHold.HoldID = entity.HoldID
+		seatHold.FlightCode = entity.FlightCode
+		seatHold.ExpiresAt = entity.ExpiresAt
+		seatHold.Seats = append(seatHold.Seats, models.Seat{
 			Row:       entity.Row,
 			Column:    entity.Column,
-			Available: entity.Status,
+			Available: false,
 		})
 	}
+	return seatHold
+}
+
+// Seats at either end of the row are window seats, seats next to a "-" are aisle seats
+func seatPosition(columns string, index int) enums.SeatPosition {
+	if index == 0 || index == len(columns)-1 {
+		return enums.Window
+	}
+	if columns[index-1] == '-' || columns[index+1] == '-' {
+		return enums.Aisle
+	}
+	return enums.Middle
+}
+
+func seatsFromJSONString(jsonInput string) []string {
+	var seats []string
+	if err := json.Unmarshal([]byte(jsonInput), &seats); err != nil {
+		return []string{}
+	}
 	return seats
 }
+
+func rowsFromJSONString(jsonInput string) map[int]bool {
+	var rows []int
+	result := map[int]bool{}
+	if err := json.Unmarshal([]byte(jsonInput), &rows); err != nil {
+		return result
+	}
+	for _, row := range rows {
+		result[row] = true
+	}
+	return result
+}
+
+func seatKey(row int, column string) string {
+	return fmt.Sprintf("%d%s", row, column)
+}
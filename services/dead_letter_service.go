@@ -0,0 +1,117 @@
+package services
+
+import (
+	"flyhorizons-bookingservice/config"
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/interfaces"
+	"log"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+type DeadLetterService struct {
+	rabbitMQClient *config.RabbitMQ
+}
+
+var _ interfaces.DeadLetterService = (*DeadLetterService)(nil)
+
+func NewDeadLetterService(client *config.RabbitMQ) *DeadLetterService {
+	return &DeadLetterService{
+		rabbitMQClient: client,
+	}
+}
+
+// Lists the dead-lettered messages of a consumed queue without removing them
+func (s *DeadLetterService) List(queue string, limit int) ([]models.DeadLetterMessage, error) {
+	if !isConsumedQueue(queue) {
+		return nil, errors.NewUnknownQueueError(queue, 404)
+	}
+
+	// Closing the channel returns the unacknowledged messages to the dead-letter queue
+	channel, err := s.rabbitMQClient.OpenChannel()
+	if err != nil {
+		return nil, err
+	}
+	defer channel.Close()
+
+	messages := []models.DeadLetterMessage{}
+	for len(messages) < limit {
+		message, ok, err := channel.Get(queue+config.DeadLetterQueueSuffix, false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		messages = append(messages, convertDeliveryToDeadLetterMessage(queue, message))
+	}
+
+	return messages, nil
+}
+
+// Moves up to limit dead-lettered messages back onto the original queue and returns how many were moved
+func (s *DeadLetterService) Replay(queue string, limit int) (int, error) {
+	if !isConsumedQueue(queue) {
+		return 0, errors.NewUnknownQueueError(queue, 404)
+	}
+
+	channel, err := s.rabbitMQClient.OpenChannel()
+	if err != nil {
+		return 0, err
+	}
+	defer channel.Close()
+
+	replayed := 0
+	for replayed < limit {
+		message, ok, err := channel.Get(queue+config.DeadLetterQueueSuffix, false)
+		if err != nil {
+			return replayed, err
+		}
+		if !ok {
+			break
+		}
+
+		// Replayed messages start over with a fresh number of attempts
+		if err := s.rabbitMQClient.Publish(queue, message.Body); err != nil {
+			if nackErr := message.Nack(false, true); nackErr != nil {
+				log.Printf("Error returning message to %s: %v", queue+config.DeadLetterQueueSuffix, nackErr)
+			}
+			return replayed, err
+		}
+		if err := message.Ack(false); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	log.Printf("Replayed %d dead-lettered messages onto %s", replayed, queue)
+	return replayed, nil
+}
+
+func isConsumedQueue(queue string) bool {
+	for _, consumedQueue := range config.ConsumedQueues {
+		if consumedQueue == queue {
+			return true
+		}
+	}
+	return false
+}
+
+func convertDeliveryToDeadLetterMessage(queue string, message amqp091.Delivery) models.DeadLetterMessage {
+	deadLetterMessage := models.DeadLetterMessage{
+		Queue:    queue,
+		Body:     string(message.Body),
+		Attempts: deliveryAttempts(message),
+	}
+	if lastError, ok := message.Headers[lastErrorHeader].(string); ok {
+		deadLetterMessage.LastError = lastError
+	}
+	if deadLetteredAt, ok := message.Headers[deadLetteredAtHeader].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, deadLetteredAt); err == nil {
+			deadLetterMessage.DeadLetteredAt = &parsed
+		}
+	}
+	return deadLetterMessage
+}
@@ -0,0 +1,17 @@
+package errors
+
+import "fmt"
+
+type AncillaryCurrencyError struct {
+	Code         string
+	Currency     string
+	FareCurrency string
+}
+
+func (e *AncillaryCurrencyError) Error() string {
+	return fmt.Sprintf("the ancillary %s is priced in %s, but the fare is in %s", e.Code, e.Currency, e.FareCurrency)
+}
+
+func NewAncillaryCurrencyError(code string, currency string, fareCurrency string, errorCode int) *AncillaryCurrencyError {
+	return &AncillaryCurrencyError{Code: code, Currency: currency, FareCurrency: fareCurrency}
+}
@@ -0,0 +1,19 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"fmt"
+)
+
+type BookingNotModifiableError struct {
+	ID     int
+	Status enums.Status
+}
+
+func (e *BookingNotModifiableError) Error() string {
+	return fmt.Sprintf("Booking with the ID %d cannot be changed in status %s", e.ID, e.Status)
+}
+
+func NewBookingNotModifiableError(id int, status enums.Status, errorCode int) *BookingNotModifiableError {
+	return &BookingNotModifiableError{ID: id, Status: status}
+}
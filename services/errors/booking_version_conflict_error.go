@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type BookingVersionConflictError struct {
+	ID int
+}
+
+func (e *BookingVersionConflictError) Error() string {
+	return fmt.Sprintf("Booking with the ID %d has been changed since it was read", e.ID)
+}
+
+func NewBookingVersionConflictError(id int, errorCode int) *BookingVersionConflictError {
+	return &BookingVersionConflictError{ID: id}
+}
@@ -0,0 +1,20 @@
+package errors
+
+import "fmt"
+
+// Returned when the database cannot be reached, the request can be retried later
+type DatabaseUnavailableError struct {
+	Err error
+}
+
+func (e *DatabaseUnavailableError) Error() string {
+	return fmt.Sprintf("The database is unavailable: %v", e.Err)
+}
+
+func (e *DatabaseUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+func NewDatabaseUnavailableError(err error, errorCode int) *DatabaseUnavailableError {
+	return &DatabaseUnavailableError{Err: err}
+}
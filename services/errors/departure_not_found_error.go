@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type DepartureNotFoundError struct {
+	FlightCode string
+}
+
+func (e *DepartureNotFoundError) Error() string {
+	return fmt.Sprintf("The departure of flight %s is unknown", e.FlightCode)
+}
+
+func NewDepartureNotFoundError(flightCode string, errorCode int) *DepartureNotFoundError {
+	return &DepartureNotFoundError{FlightCode: flightCode}
+}
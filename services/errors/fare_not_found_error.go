@@ -0,0 +1,19 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"fmt"
+)
+
+type FareNotFoundError struct {
+	FlightCode  string
+	FlightClass enums.FlightClass
+}
+
+func (e *FareNotFoundError) Error() string {
+	return fmt.Sprintf("No %s fare has been configured for flight %s", e.FlightClass, e.FlightCode)
+}
+
+func NewFareNotFoundError(flightCode string, flightClass enums.FlightClass, errorCode int) *FareNotFoundError {
+	return &FareNotFoundError{FlightCode: flightCode, FlightClass: flightClass}
+}
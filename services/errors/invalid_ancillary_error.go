@@ -0,0 +1,19 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"fmt"
+)
+
+type InvalidAncillaryError struct {
+	FlightClass enums.FlightClass
+	Violations  []string
+}
+
+func (e *InvalidAncillaryError) Error() string {
+	return fmt.Sprintf("the ancillaries of the booking cannot be booked in %s: %d violations", e.FlightClass, len(e.Violations))
+}
+
+func NewInvalidAncillaryError(flightClass enums.FlightClass, violations []string, errorCode int) *InvalidAncillaryError {
+	return &InvalidAncillaryError{FlightClass: flightClass, Violations: violations}
+}
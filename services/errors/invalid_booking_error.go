@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type InvalidBookingError struct {
+	Violations []string
+}
+
+func (e *InvalidBookingError) Error() string {
+	return fmt.Sprintf("the booking is invalid: %d violations", len(e.Violations))
+}
+
+func NewInvalidBookingError(violations []string, errorCode int) *InvalidBookingError {
+	return &InvalidBookingError{Violations: violations}
+}
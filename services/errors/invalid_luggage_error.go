@@ -0,0 +1,19 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"fmt"
+)
+
+type InvalidLuggageError struct {
+	FlightClass enums.FlightClass
+	Violations  []string
+}
+
+func (e *InvalidLuggageError) Error() string {
+	return fmt.Sprintf("the luggage of the booking exceeds the %s allowance: %d violations", e.FlightClass, len(e.Violations))
+}
+
+func NewInvalidLuggageError(flightClass enums.FlightClass, violations []string, errorCode int) *InvalidLuggageError {
+	return &InvalidLuggageError{FlightClass: flightClass, Violations: violations}
+}
@@ -0,0 +1,13 @@
+package errors
+
+type InvalidPaymentError struct {
+	Reason string
+}
+
+func (e *InvalidPaymentError) Error() string {
+	return "invalid payment: " + e.Reason
+}
+
+func NewInvalidPaymentError(reason string, errorCode int) *InvalidPaymentError {
+	return &InvalidPaymentError{Reason: reason}
+}
@@ -0,0 +1,13 @@
+package errors
+
+type InvalidQuoteError struct {
+	Reason string
+}
+
+func (e *InvalidQuoteError) Error() string {
+	return "invalid quote: " + e.Reason
+}
+
+func NewInvalidQuoteError(reason string, errorCode int) *InvalidQuoteError {
+	return &InvalidQuoteError{Reason: reason}
+}
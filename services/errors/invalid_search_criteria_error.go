@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+type InvalidSearchCriteriaError struct {
+	Parameter string
+	Value     string
+}
+
+func (e *InvalidSearchCriteriaError) Error() string {
+	return fmt.Sprintf("invalid value '%s' for %s", e.Value, e.Parameter)
+}
+
+func NewInvalidSearchCriteriaError(parameter string, value string, errorCode int) *InvalidSearchCriteriaError {
+	return &InvalidSearchCriteriaError{Parameter: parameter, Value: value}
+}
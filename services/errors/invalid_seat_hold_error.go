@@ -0,0 +1,19 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models"
+	"fmt"
+)
+
+type InvalidSeatHoldError struct {
+	FlightCode string
+	Seats      []models.Seat
+}
+
+func (e *InvalidSeatHoldError) Error() string {
+	return fmt.Sprintf("%d of the selected seats on flight %s do not exist or are blocked", len(e.Seats), e.FlightCode)
+}
+
+func NewInvalidSeatHoldError(flightCode string, seats []models.Seat, errorCode int) *InvalidSeatHoldError {
+	return &InvalidSeatHoldError{FlightCode: flightCode, Seats: seats}
+}
@@ -0,0 +1,21 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"fmt"
+)
+
+type InvalidSeatSelectionError struct {
+	FlightCode  string
+	FlightClass enums.FlightClass
+	Seats       []models.Seat
+}
+
+func (e *InvalidSeatSelectionError) Error() string {
+	return fmt.Sprintf("%d of the selected seats on flight %s cannot be booked in %s", len(e.Seats), e.FlightCode, e.FlightClass)
+}
+
+func NewInvalidSeatSelectionError(flightCode string, flightClass enums.FlightClass, seats []models.Seat, errorCode int) *InvalidSeatSelectionError {
+	return &InvalidSeatSelectionError{FlightCode: flightCode, FlightClass: flightClass, Seats: seats}
+}
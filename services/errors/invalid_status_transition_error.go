@@ -0,0 +1,20 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"fmt"
+)
+
+type InvalidStatusTransitionError struct {
+	ID   int
+	From enums.Status
+	To   enums.Status
+}
+
+func (e *InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("Booking with the ID %d cannot move from status %s to %s", e.ID, e.From, e.To)
+}
+
+func NewInvalidStatusTransitionError(id int, from enums.Status, to enums.Status, errorCode int) *InvalidStatusTransitionError {
+	return &InvalidStatusTransitionError{ID: id, From: from, To: to}
+}
@@ -0,0 +1,18 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+type QuoteExpiredError struct {
+	ExpiresAt time.Time
+}
+
+func (e *QuoteExpiredError) Error() string {
+	return fmt.Sprintf("The quote expired at %s, request a new quote", e.ExpiresAt.Format(time.RFC3339))
+}
+
+func NewQuoteExpiredError(expiresAt time.Time, errorCode int) *QuoteExpiredError {
+	return &QuoteExpiredError{ExpiresAt: expiresAt}
+}
@@ -0,0 +1,19 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models"
+	"fmt"
+)
+
+type SeatAlreadyBookedError struct {
+	FlightCode string
+	Seats      []models.Seat
+}
+
+func (e *SeatAlreadyBookedError) Error() string {
+	return fmt.Sprintf("%d of the selected seats on flight %s have already been booked", len(e.Seats), e.FlightCode)
+}
+
+func NewSeatAlreadyBookedError(flightCode string, seats []models.Seat, errorCode int) *SeatAlreadyBookedError {
+	return &SeatAlreadyBookedError{FlightCode: flightCode, Seats: seats}
+}
@@ -0,0 +1,19 @@
+package errors
+
+import (
+	"flyhorizons-bookingservice/models"
+	"fmt"
+)
+
+type SeatHeldError struct {
+	FlightCode string
+	Seats      []models.Seat
+}
+
+func (e *SeatHeldError) Error() string {
+	return fmt.Sprintf("%d of the selected seats on flight %s are held or booked by someone else", len(e.Seats), e.FlightCode)
+}
+
+func NewSeatHeldError(flightCode string, seats []models.Seat, errorCode int) *SeatHeldError {
+	return &SeatHeldError{FlightCode: flightCode, Seats: seats}
+}
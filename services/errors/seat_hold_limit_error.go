@@ -0,0 +1,28 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// Returned when a user holds too many seats on a flight, or holds seats for too long
+type SeatHoldLimitError struct {
+	FlightCode  string
+	MaxSeats    int
+	MaxLifetime time.Duration
+}
+
+func (e *SeatHoldLimitError) Error() string {
+	if e.MaxLifetime > 0 {
+		return fmt.Sprintf("Seats on flight %s cannot be held for longer than %s", e.FlightCode, e.MaxLifetime)
+	}
+	return fmt.Sprintf("No more than %d seats can be held on flight %s", e.MaxSeats, e.FlightCode)
+}
+
+func NewSeatHoldCountLimitError(flightCode string, maxSeats int, errorCode int) *SeatHoldLimitError {
+	return &SeatHoldLimitError{FlightCode: flightCode, MaxSeats: maxSeats}
+}
+
+func NewSeatHoldLifetimeLimitError(flightCode string, maxLifetime time.Duration, errorCode int) *SeatHoldLimitError {
+	return &SeatHoldLimitError{FlightCode: flightCode, MaxLifetime: maxLifetime}
+}
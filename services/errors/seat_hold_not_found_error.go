@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type SeatHoldNotFoundError struct {
+	HoldID string
+}
+
+func (e *SeatHoldNotFoundError) Error() string {
+	return fmt.Sprintf("Seat hold with the ID %s was not found or has expired", e.HoldID)
+}
+
+func NewSeatHoldNotFoundError(holdID string, errorCode int) *SeatHoldNotFoundError {
+	return &SeatHoldNotFoundError{HoldID: holdID}
+}
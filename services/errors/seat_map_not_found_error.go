@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type SeatMapNotFoundError struct {
+	FlightCode string
+}
+
+func (e *SeatMapNotFoundError) Error() string {
+	return fmt.Sprintf("No seat map has been configured for flight %s", e.FlightCode)
+}
+
+func NewSeatMapNotFoundError(flightCode string, errorCode int) *SeatMapNotFoundError {
+	return &SeatMapNotFoundError{FlightCode: flightCode}
+}
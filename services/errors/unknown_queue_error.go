@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type UnknownQueueError struct {
+	Queue string
+}
+
+func (e *UnknownQueueError) Error() string {
+	return fmt.Sprintf("Queue %s is not consumed by the booking service", e.Queue)
+}
+
+func NewUnknownQueueError(queue string, errorCode int) *UnknownQueueError {
+	return &UnknownQueueError{Queue: queue}
+}
@@ -0,0 +1,22 @@
+package errors
+
+import "fmt"
+
+// Returned by message handlers for messages that will never succeed,
+// these are moved to the dead-letter queue straight away instead of being retried
+type UnprocessableMessageError struct {
+	Queue string
+	Err   error
+}
+
+func (e *UnprocessableMessageError) Error() string {
+	return fmt.Sprintf("Message from the queue %s cannot be processed: %v", e.Queue, e.Err)
+}
+
+func (e *UnprocessableMessageError) Unwrap() error {
+	return e.Err
+}
+
+func NewUnprocessableMessageError(queue string, err error) *UnprocessableMessageError {
+	return &UnprocessableMessageError{Queue: queue, Err: err}
+}
@@ -0,0 +1,174 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderName     = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+	maxKeyLength   = 200
+	// A request that is still in flight after this long has crashed without releasing its key
+	maxProcessingTime = time.Minute
+)
+
+type IdempotencyMiddlewareHandler struct {
+	idempotencyRepo interfaces.IdempotencyRepository
+	window          time.Duration
+}
+
+var _ interfaces.IdempotencyMiddleware = (*IdempotencyMiddlewareHandler)(nil)
+
+func NewIdempotencyMiddleware(repo interfaces.IdempotencyRepository, window time.Duration) *IdempotencyMiddlewareHandler {
+	return &IdempotencyMiddlewareHandler{
+		idempotencyRepo: repo,
+		window:          window,
+	}
+}
+
+// Stores the first response for an Idempotency-Key and replays it when the request is retried
+// Requests without the header are handled as usual
+func (m *IdempotencyMiddlewareHandler) IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderName)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key cannot be longer than 200 characters"})
+			return
+		}
+
+		// Read the body and put it back, so the handler can still bind it
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read the request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the logged in user, so users cannot replay each other's responses
+		if userID, ok := c.Get("user_id"); ok {
+			key = fmt.Sprintf("%v:%s", userID, key)
+		}
+
+		requestHash := hashRequest(c.Request.Method, c.FullPath(), body)
+		existing, err := m.reserve(key, requestHash)
+		if err != nil {
+			log.Printf("Error reserving idempotency key: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "failed to process the Idempotency-Key"})
+			return
+		}
+
+		if existing != nil {
+			if existing.RequestHash != requestHash {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key has already been used for a different request"})
+				return
+			}
+			if existing.StatusCode == 0 {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still being processed"})
+				return
+			}
+
+			c.Header(ReplayedHeader, "true")
+			c.Data(existing.StatusCode, "application/json; charset=utf-8", []byte(existing.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		// A panicking handler releases the key as well, so the request can be retried straight away
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := m.idempotencyRepo.Release(key); err != nil {
+				log.Printf("Error releasing idempotency key: %v", err)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		completed = true
+
+		// Server errors are not stored, so the request can be retried with the same key
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := m.idempotencyRepo.Release(key); err != nil {
+				log.Printf("Error releasing idempotency key: %v", err)
+			}
+			return
+		}
+		if err := m.idempotencyRepo.Complete(key, recorder.Status(), recorder.body.String()); err != nil {
+			log.Printf("Error storing idempotent response: %v", err)
+		}
+	}
+}
+
+// Claims the key for the request, the reservation of a request that has been in flight for longer
+// than maxProcessingTime is taken over, e.g. when the instance handling it crashed
+func (m *IdempotencyMiddlewareHandler) reserve(key string, requestHash string) (*entities.IdempotencyKeyEntity, error) {
+	now := time.Now()
+	reservation := entities.IdempotencyKeyEntity{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(m.window),
+	}
+	existing, err := m.idempotencyRepo.Reserve(reservation)
+	if err != nil || existing == nil || existing.StatusCode != 0 || existing.RequestHash != requestHash || now.Sub(existing.CreatedAt) < maxProcessingTime {
+		return existing, err
+	}
+
+	released, err := m.idempotencyRepo.ReleaseAbandoned(key, now.Add(-maxProcessingTime))
+	if err != nil || !released {
+		return existing, err
+	}
+	log.Printf("Took over the abandoned idempotency key %s", key)
+	return m.idempotencyRepo.Reserve(reservation)
+}
+
+// Removes the expired keys every interval, run it in a goroutine
+func (m *IdempotencyMiddlewareHandler) StartSweeper(interval time.Duration) {
+	for {
+		if _, err := m.idempotencyRepo.DeleteExpired(); err != nil {
+			log.Printf("Error removing expired idempotency keys: %v", err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func hashRequest(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Copies everything written to the response, so it can be stored
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(data string) (int, error) {
+	r.body.WriteString(data)
+	return r.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+)
+
+type AncillaryRepository interface {
+	GetAll() ([]entities.AncillaryEntity, error)
+}
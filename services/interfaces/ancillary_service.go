@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+)
+
+type AncillaryService interface {
+	GetAvailable(flightClass enums.FlightClass) ([]models.Ancillary, error)
+}
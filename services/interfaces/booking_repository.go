@@ -1,16 +1,25 @@
 package interfaces
 
 import (
+	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/models/enums"
 	entities "flyhorizons-bookingservice/repositories/entity"
+	"time"
 )
 
 type BookingRepository interface {
-	GetAll() []entities.BookingEntity
-	GetByID(id int) entities.BookingEntity
-	GetByUserID(userID int) []entities.BookingEntity
-	Create(booking entities.BookingEntity) *entities.BookingEntity
-	DeleteByBookingID(bookingID int) bool
-	UpdateStatus(bookingID int, status enums.Status)
-	Update(booking entities.BookingEntity) entities.BookingEntity
+	Transaction(fn func(repo BookingRepository) error) error
+	GetAll() ([]entities.BookingEntity, error)
+	GetByID(id int) (*entities.BookingEntity, error)
+	Exists(id int) (bool, error)
+	GetByUserID(userID int, criteria models.BookingSearchCriteria) ([]entities.BookingEntity, int64, string, error)
+	Search(criteria models.BookingSearchCriteria) ([]entities.BookingEntity, int64, string, error)
+	Create(booking entities.BookingEntity) (*entities.BookingEntity, error)
+	DeleteByBookingID(bookingID int) error
+	Purge(closedBefore time.Time) (int64, error)
+	GetUnpaidIDs(createdBefore time.Time) ([]int, error)
+	UpdateStatus(bookingID int, from enums.Status, to enums.Status, reason string) error
+	GetStatusHistory(bookingID int) ([]entities.BookingStatusHistoryEntity, error)
+	AddOutboxMessage(queue string, payload []byte) error
+	Update(booking entities.BookingEntity) (*entities.BookingEntity, error)
 }
@@ -2,12 +2,20 @@ package interfaces
 
 import (
 	"flyhorizons-bookingservice/models"
+	"time"
 )
 
 type BookingService interface {
-	BookingExists(bookingID int) bool
-	GetByUserID(userID int) []models.Booking
+	BookingExists(bookingID int) (bool, error)
+	GetByID(id int) (*models.Booking, error)
+	GetByUserID(userID int, criteria models.BookingSearchCriteria) (*models.BookingSearchResult, error)
+	Search(criteria models.BookingSearchCriteria) (*models.BookingSearchResult, error)
+	Quote(booking models.Booking) (*models.Quote, error)
 	Create(booking models.Booking) (*models.Booking, error)
+	Cancel(bookingID int, reason string) error
 	DeleteByBookingID(id int) (bool, error)
+	Purge(closedBefore time.Time) (int64, error)
 	Update(booking models.Booking) (*models.Booking, error)
+	RetryPayment(bookingID int, payment models.Payment) (*models.Booking, error)
+	GetStatusHistory(bookingID int) ([]models.BookingStatusChange, error)
 }
@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"flyhorizons-bookingservice/models"
+)
+
+type DeadLetterService interface {
+	List(queue string, limit int) ([]models.DeadLetterMessage, error)
+	Replay(queue string, limit int) (int, error)
+}
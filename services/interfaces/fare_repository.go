@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
+)
+
+type FareRepository interface {
+	GetByFlight(flightCode string, flightClass enums.FlightClass) (*entities.FlightFareEntity, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	Reserve(key entities.IdempotencyKeyEntity) (*entities.IdempotencyKeyEntity, error)
+	Complete(key string, statusCode int, responseBody string) error
+	Release(key string) error
+	ReleaseAbandoned(key string, reservedBefore time.Time) (bool, error)
+	DeleteExpired() (int64, error)
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "github.com/rabbitmq/amqp091-go"
+
+type MessageBroker interface {
+	RegisterConsumer(start func(channel RabbitMQChannel) error) error
+	PublishWithHeaders(queue string, body []byte, headers amqp091.Table) error
+}
@@ -0,0 +1,5 @@
+package interfaces
+
+type MessagePublisher interface {
+	Publish(queue string, body []byte) error
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"time"
+)
+
+type OutboxRepository interface {
+	GetPending(limit int) ([]entities.OutboxMessageEntity, error)
+	MarkDispatched(id int) error
+	MarkFailed(id int, lastError string, nextAttemptAt time.Time) error
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"time"
+)
+
+type PricingService interface {
+	GetDeparture(flightCode string, flightClass enums.FlightClass) (*time.Time, error)
+	Calculate(booking models.Booking) (*models.PriceBreakdown, error)
+	Quote(booking models.Booking) (*models.Quote, error)
+	PriceFromQuote(quoteID string, booking models.Booking) (*models.PriceBreakdown, error)
+}
@@ -0,0 +1,30 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Opens the connections of the RabbitMQ client, so the reconnects can be tested without a broker
+type RabbitMQDialer interface {
+	Dial(url string) (RabbitMQConnection, error)
+}
+
+type RabbitMQConnection interface {
+	Channel() (RabbitMQChannel, error)
+	NotifyClose(receiver chan *amqp091.Error) chan *amqp091.Error
+	IsClosed() bool
+	Close() error
+}
+
+// The methods of *amqp091.Channel the service uses
+type RabbitMQChannel interface {
+	Confirm(noWait bool) error
+	QueueDeclare(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error)
+	Consume(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error)
+	Get(queue string, autoAck bool) (amqp091.Delivery, bool, error)
+	PublishWithDeferredConfirmWithContext(ctx context.Context, exchange string, key string, mandatory bool, immediate bool, msg amqp091.Publishing) (*amqp091.DeferredConfirmation, error)
+	NotifyClose(receiver chan *amqp091.Error) chan *amqp091.Error
+	Close() error
+}
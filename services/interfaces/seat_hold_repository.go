@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"time"
+)
+
+type SeatHoldRepository interface {
+	Create(holds []entities.SeatHoldEntity) error
+	GetActiveByHoldID(holdID string) ([]entities.SeatHoldEntity, error)
+	GetActiveByUser(userID int, flightCode string) ([]entities.SeatHoldEntity, error)
+	Extend(holdID string, expiresAt time.Time) error
+	Release(holdID string) error
+	DeleteExpired() (int64, error)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"flyhorizons-bookingservice/models"
+)
+
+type SeatHoldService interface {
+	Hold(userID int, request models.SeatHoldRequest) (*models.SeatHold, error)
+	Extend(userID int, holdID string) (*models.SeatHold, error)
+	Release(userID int, holdID string) error
+}
@@ -1,9 +1,11 @@
 package interfaces
 
 import (
+	"flyhorizons-bookingservice/models"
 	entities "flyhorizons-bookingservice/repositories/entity"
 )
 
 type SeatRepository interface {
-	GetByFlightCode(flightCode string) ([]entities.SeatOptionEntity, error)
+	GetConfigurationByFlightCode(flightCode string) (*entities.AircraftConfigurationEntity, error)
+	GetOccupiedSeats(flightCode string) ([]models.Seat, error)
 }
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"flyhorizons-bookingservice/config"
+	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/interfaces"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Headers added to messages that are retried or dead-lettered
+const (
+	attemptsHeader       = "x-delivery-attempts"
+	lastErrorHeader      = "x-last-error"
+	deadLetteredAtHeader = "x-dead-lettered-at"
+)
+
+type MessageHandler func(message amqp091.Delivery) error
+
+type MessageConsumer struct {
+	rabbitMQClient interfaces.MessageBroker
+}
+
+func NewMessageConsumer(client interfaces.MessageBroker) *MessageConsumer {
+	return &MessageConsumer{
+		rabbitMQClient: client,
+	}
+}
+
+// Consumes the queue with manual acknowledgements
+// A message is only acknowledged once the handler succeeded, or once it has been moved to the retry or dead-letter queue
+// The consumer is restarted by the RabbitMQ client whenever the connection is restored
+func (consumer *MessageConsumer) Consume(queue string, handler MessageHandler) error {
+	return consumer.rabbitMQClient.RegisterConsumer(func(channel interfaces.RabbitMQChannel) error {
+		messages, err := channel.Consume(
+			queue, // Queue name
+			"",    // Consumer tag
+			false, // Auto-ack
+			false, // Exclusive
+			false, // No-local
+			false, // No-wait
+			nil,   // Args
+		)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			for message := range messages {
+				consumer.Handle(queue, message, handler)
+			}
+			// Log when the consumer stops (e.g., if the channel closes)
+			log.Printf("Consumer for queue %s stopped", queue)
+		}()
+
+		return nil
+	})
+}
+
+// Runs the handler for a single message, a failed message is moved to the retry queue of the queue,
+// or to its dead-letter queue once it is unprocessable or has used up its attempts
+func (consumer *MessageConsumer) Handle(queue string, message amqp091.Delivery, handler MessageHandler) {
+	err := runHandler(queue, message, handler)
+	if err == nil {
+		if err := message.Ack(false); err != nil {
+			log.Printf("Error acknowledging message from %s: %v", queue, err)
+		}
+		return
+	}
+
+	attempts := deliveryAttempts(message) + 1
+	headers := amqp091.Table{
+		attemptsHeader:  int32(attempts),
+		lastErrorHeader: err.Error(),
+	}
+
+	target := queue + config.RetryQueueSuffix
+	_, unprocessable := err.(*errors.UnprocessableMessageError)
+	if unprocessable || attempts >= config.MaxDeliveryAttempts {
+		target = queue + config.DeadLetterQueueSuffix
+		headers[deadLetteredAtHeader] = time.Now().UTC().Format(time.RFC3339)
+	}
+	log.Printf("Error processing message from %s (attempt %d), moving it to %s: %v", queue, attempts, target, err)
+
+	if err := consumer.rabbitMQClient.PublishWithHeaders(target, message.Body, headers); err != nil {
+		// Put the message back on the queue, so it is not lost
+		log.Printf("Error moving message to %s: %v", target, err)
+		if err := message.Nack(false, true); err != nil {
+			log.Printf("Error requeueing message from %s: %v", queue, err)
+		}
+		return
+	}
+
+	if err := message.Ack(false); err != nil {
+		log.Printf("Error acknowledging message from %s: %v", queue, err)
+	}
+}
+
+// A panicking handler is treated as a failed attempt instead of stopping the consumer
+func runHandler(queue string, message amqp091.Delivery, handler MessageHandler) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("handler for %s panicked: %v", queue, recovered)
+		}
+	}()
+	return handler(message)
+}
+
+func deliveryAttempts(message amqp091.Delivery) int {
+	switch attempts := message.Headers[attemptsHeader].(type) {
+	case int32:
+		return int(attempts)
+	case int64:
+		return int(attempts)
+	case int:
+		return attempts
+	default:
+		return 0
+	}
+}
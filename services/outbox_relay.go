@@ -0,0 +1,76 @@
+package services
+
+import (
+	"flyhorizons-bookingservice/services/interfaces"
+	"log"
+	"time"
+)
+
+const maxOutboxRetryDelay = 5 * time.Minute
+
+type OutboxRelay struct {
+	outboxRepo   interfaces.OutboxRepository
+	publisher    interfaces.MessagePublisher
+	pollInterval time.Duration
+	batchSize    int
+}
+
+func NewOutboxRelay(repo interfaces.OutboxRepository, publisher interfaces.MessagePublisher, pollInterval time.Duration, batchSize int) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo:   repo,
+		publisher:    publisher,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+	}
+}
+
+// Polls the outbox for ever, run it in a goroutine
+func (relay *OutboxRelay) Start() {
+	log.Println("Outbox relay started")
+	for {
+		relay.DispatchPending()
+		time.Sleep(relay.pollInterval)
+	}
+}
+
+// Publishes the pending outbox messages and returns how many were dispatched
+// Messages are delivered at least once: if marking a message as dispatched fails it is published again
+func (relay *OutboxRelay) DispatchPending() int {
+	messages, err := relay.outboxRepo.GetPending(relay.batchSize)
+	if err != nil {
+		log.Printf("Error loading pending outbox messages: %v", err)
+		return 0
+	}
+
+	dispatched := 0
+	for _, message := range messages {
+		if err := relay.publisher.Publish(message.Queue, []byte(message.Payload)); err != nil {
+			log.Printf("Error publishing outbox message %d to '%s' (attempt %d): %v", message.ID, message.Queue, message.Attempts+1, err)
+			nextAttemptAt := time.Now().Add(outboxRetryDelay(message.Attempts + 1))
+			if err := relay.outboxRepo.MarkFailed(message.ID, err.Error(), nextAttemptAt); err != nil {
+				log.Printf("Error recording the failed outbox message %d: %v", message.ID, err)
+			}
+			continue
+		}
+
+		if err := relay.outboxRepo.MarkDispatched(message.ID); err != nil {
+			log.Printf("Error marking outbox message %d as dispatched: %v", message.ID, err)
+			continue
+		}
+		dispatched++
+	}
+
+	return dispatched
+}
+
+// Exponential backoff starting at one second, capped at maxOutboxRetryDelay
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := time.Second
+	for i := 1; i < attempts && delay < maxOutboxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxOutboxRetryDelay {
+		return maxOutboxRetryDelay
+	}
+	return delay
+}
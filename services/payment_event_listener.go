@@ -3,123 +3,88 @@ package services
 import (
 	"encoding/json"
 	"flyhorizons-bookingservice/config"
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/services/errors"
+	"fmt"
 	"log"
 
 	"github.com/rabbitmq/amqp091-go"
 )
 
 type PaymentEventListener struct {
-	rabbitMQClient *config.RabbitMQ
-	bookingService BookingService
+	rabbitMQClient  *config.RabbitMQ
+	bookingService  BookingService
+	messageConsumer *MessageConsumer
 }
 
 func NewPaymentEventListener(client *config.RabbitMQ, service BookingService) *PaymentEventListener {
 	return &PaymentEventListener{
-		rabbitMQClient: client,
-		bookingService: service,
+		rabbitMQClient:  client,
+		bookingService:  service,
+		messageConsumer: NewMessageConsumer(client),
 	}
 }
 
 func (p *PaymentEventListener) StartPaymentProcessedConsumers() {
-	channel := p.rabbitMQClient.Channel
-
 	// === Consumer for payment.success ===
-	successMessages, err := channel.Consume(
-		"payment.success", // Queue name
-		"",                // Consumer tag
-		true,              // Auto-ack
-		false,             // Exclusive
-		false,             // No-local
-		false,             // No-wait
-		nil,               // Args
-	)
-	if err != nil {
+	if err := p.messageConsumer.Consume("payment.success", p.handlePaymentSuccess); err != nil {
 		log.Fatalf("Error consuming payment.success: %v", err)
 	}
 
-	go func() {
-		for msg := range successMessages {
-			log.Printf("[payment.success] Received message: %s", string(msg.Body))
-
-			// Success: Post the booking.confirmed event to the RabbitMQ
-			// This is listened by the Email Service, therefore a confirmation email will be sent consecutively
-
-			// --- Publish to RabbitMQ ---
-			// Get the bookingID
-			var bookingID int
-			err := json.Unmarshal(msg.Body, &bookingID)
-			if err != nil {
-				log.Printf("Error unmarshaling booking ID: %v", err)
-				continue // skip this message and continue looping
-			}
-
-			log.Printf("BookingID: %v", bookingID)
-
-			// Update the booking status to 'Success'
-			p.bookingService.UpdateStatus(bookingID, "Success")
-
-			// Get the booking by bookingID
-			booking := p.bookingService.GetByID(bookingID)
-
-			log.Print(booking)
-
-			// Marshal the booking and send it using RabbitMQ
-			body, err := json.Marshal(booking)
-			if err != nil {
-				log.Printf("Error marshaling booking: %v", err)
-			} else {
-				err = channel.Publish(
-					"",
-					"booking.confirmed",
-					true,
-					false,
-					amqp091.Publishing{
-						ContentType: "application/json",
-						Body:        body,
-					},
-				)
-				if err != nil {
-					log.Printf("Error publishing booking created event to RabbitMQ: %v\n", err)
-				}
-				log.Print("Booking information event published to booking.confirmed")
-			}
-		}
-	}()
-
 	// === Consumer for payment.fail ===
-	failMessages, err := channel.Consume(
-		"payment.failed", // Queue name
-		"",               // Consumer tag
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
-	if err != nil {
+	if err := p.messageConsumer.Consume("payment.failed", p.handlePaymentFailed); err != nil {
 		log.Fatalf("Error consuming payment.fail: %v", err)
 	}
 
-	go func() {
-		for msg := range failMessages {
-			log.Printf("[payment.fail] Received message: %s", string(msg.Body))
+	log.Println("Payment event consumers started: payment.success and payment.failed")
+}
 
-			// Failed: Deletes the booking from the database
-			// The booking is deleted, as the payment was not processed successfully
+func (p *PaymentEventListener) handlePaymentSuccess(msg amqp091.Delivery) error {
+	log.Printf("[payment.success] Received message: %s", string(msg.Body))
 
-			// --- Delete Booking by ID ---
-			// Get the bookingID
-			var bookingID int
-			err := json.Unmarshal(msg.Body, &bookingID)
-			if err != nil {
-				log.Printf("Error unmarshaling booking ID: %v", err)
-				continue // skip this message and continue looping
-			}
+	// Success: Confirm the booking, which queues the booking.confirmed event
+	// This is listened by the Email Service, therefore a confirmation email will be sent consecutively
 
-			// Delete booking by bookingID
-			p.bookingService.DeleteByBookingID(bookingID)
-		}
-	}()
+	// Get the bookingID
+	var bookingID int
+	if err := json.Unmarshal(msg.Body, &bookingID); err != nil {
+		return errors.NewUnprocessableMessageError("payment.success", fmt.Errorf("error unmarshaling booking ID: %w", err))
+	}
 
-	log.Println("Payment event consumers started: payment.success and payment.failed")
+	log.Printf("BookingID: %v", bookingID)
+
+	// Update the booking status to 'Confirmed', a late payment of an expired or cancelled booking is refunded instead
+	if err := p.bookingService.ConfirmPayment(bookingID); err != nil {
+		return unprocessableIfPermanent("payment.success", err)
+	}
+	log.Print("Booking information event queued for booking.confirmed")
+	return nil
+}
+
+func (p *PaymentEventListener) handlePaymentFailed(msg amqp091.Delivery) error {
+	log.Printf("[payment.fail] Received message: %s", string(msg.Body))
+
+	// Failed: The booking is kept with the PaymentFailed status, so the customer can retry the payment
+	// with POST /bookings/:ID/payment and the failed attempt stays in the status history
+
+	// Get the bookingID
+	var bookingID int
+	if err := json.Unmarshal(msg.Body, &bookingID); err != nil {
+		return errors.NewUnprocessableMessageError("payment.failed", fmt.Errorf("error unmarshaling booking ID: %w", err))
+	}
+
+	if err := p.bookingService.UpdateStatus(bookingID, enums.PaymentFailed, "Payment failed"); err != nil {
+		return unprocessableIfPermanent("payment.failed", err)
+	}
+	return nil
+}
+
+// Errors caused by the message itself will not go away when retrying
+func unprocessableIfPermanent(queue string, err error) error {
+	switch err.(type) {
+	case *errors.BookingNotFoundError, *errors.InvalidStatusTransitionError:
+		return errors.NewUnprocessableMessageError(queue, err)
+	default:
+		return err
+	}
 }
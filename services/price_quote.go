@@ -0,0 +1,117 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/services/errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The quote ID carries everything needed to book the quote, so quotes do not have to be stored
+// It is made of the base64 encoded claims and their HMAC-SHA256 signature, separated by a "."
+type quoteClaims struct {
+	Price     models.PriceBreakdown `json:"price"`
+	Booking   string                `json:"booking"` // Fingerprint of the priced parts of the booking
+	ExpiresAt int64                 `json:"exp"`
+}
+
+// Prices the booking and signs the price, the quote can be booked until it expires
+func (s *PricingService) Quote(booking models.Booking) (*models.Quote, error) {
+	price, err := s.Calculate(booking)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.quoteTTL).Truncate(time.Second).UTC()
+	quoteID, err := s.signQuote(quoteClaims{Price: *price, Booking: bookingFingerprint(booking), ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return nil, err
+	}
+	return &models.Quote{QuoteID: quoteID, Price: *price, ExpiresAt: expiresAt}, nil
+}
+
+// Returns the price of the quote, when the quote is signed by this service, has not expired and was made for this booking
+// Returns an InvalidQuoteError or a QuoteExpiredError otherwise
+func (s *PricingService) PriceFromQuote(quoteID string, booking models.Booking) (*models.PriceBreakdown, error) {
+	claims, err := s.verifyQuote(quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Unix(claims.ExpiresAt, 0).UTC()
+	if !time.Now().Before(expiresAt) {
+		return nil, errors.NewQuoteExpiredError(expiresAt, 410)
+	}
+	// The passengers, seats or luggage may have changed since the quote was made
+	if !hmac.Equal([]byte(claims.Booking), []byte(bookingFingerprint(booking))) {
+		return nil, errors.NewInvalidQuoteError("the booking does not match the quoted booking", 400)
+	}
+	return &claims.Price, nil
+}
+
+func (s *PricingService) signQuote(claims quoteClaims) (string, error) {
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(body)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.quoteSignature(payload)), nil
+}
+
+func (s *PricingService) verifyQuote(quoteID string) (*quoteClaims, error) {
+	payload, signature, ok := strings.Cut(quoteID, ".")
+	if !ok {
+		return nil, errors.NewInvalidQuoteError("malformed quote ID", 400)
+	}
+	decodedSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decodedSignature, s.quoteSignature(payload)) {
+		return nil, errors.NewInvalidQuoteError("signature mismatch", 400)
+	}
+
+	// The claims are only read once the signature is known to be ours
+	body, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errors.NewInvalidQuoteError("malformed quote ID", 400)
+	}
+	var claims quoteClaims
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return nil, errors.NewInvalidQuoteError("malformed quote ID", 400)
+	}
+	return &claims, nil
+}
+
+func (s *PricingService) quoteSignature(payload string) []byte {
+	mac := hmac.New(sha256.New, s.quoteSecret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// Hashes everything the price depends on, and the user, so a quote cannot be used for another booking
+func bookingFingerprint(booking models.Booking) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "user=%d;flight=%s;class=%d;", booking.UserID, booking.FlightCode, booking.FlightClass)
+	if booking.DepartureAt != nil {
+		fmt.Fprintf(&builder, "departure=%s;", booking.DepartureAt.UTC().Format(time.RFC3339))
+	}
+	for _, passenger := range booking.Passengers {
+		fmt.Fprintf(&builder, "passenger=%s;", passenger.DateOfBirth.UTC().Format("2006-01-02"))
+		for _, item := range passenger.Luggage {
+			fmt.Fprintf(&builder, "luggage=%s:%d;", item.Type, item.Quantity)
+		}
+		for _, code := range passenger.Ancillaries {
+			fmt.Fprintf(&builder, "ancillary=%s;", code)
+		}
+	}
+	for _, seat := range booking.Seats {
+		fmt.Fprintf(&builder, "seat=%d%s;", seat.Row, seat.Column)
+	}
+
+	hash := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(hash[:])
+}
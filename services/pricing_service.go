@@ -0,0 +1,232 @@
+package services
+
+import (
+	"encoding/json"
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/interfaces"
+	"fmt"
+	"time"
+)
+
+// Percentage of the base fare every age band pays
+var ageBandFarePercentages = map[enums.AgeBand]int64{
+	enums.Adult:  100,
+	enums.Child:  75,
+	enums.Infant: 10,
+}
+
+// The order the fares of the age bands are listed in the price breakdown
+var ageBands = []enums.AgeBand{enums.Adult, enums.Child, enums.Infant}
+
+type PricingService struct {
+	fareRepo         interfaces.FareRepository
+	seatService      interfaces.SeatService
+	ancillaryService interfaces.AncillaryService
+	quoteSecret      []byte
+	quoteTTL         time.Duration
+}
+
+var _ interfaces.PricingService = (*PricingService)(nil)
+
+func NewPricingService(fareRepo interfaces.FareRepository, seatService interfaces.SeatService, ancillaryService interfaces.AncillaryService, quoteSecret []byte, quoteTTL time.Duration) *PricingService {
+	return &PricingService{
+		fareRepo:         fareRepo,
+		seatService:      seatService,
+		ancillaryService: ancillaryService,
+		quoteSecret:      quoteSecret,
+		quoteTTL:         quoteTTL,
+	}
+}
+
+// Returns the departure of the flight, as recorded with its fare
+// Returns a FareNotFoundError when the flight class has no fare, and a DepartureNotFoundError when the departure is unknown
+func (s *PricingService) GetDeparture(flightCode string, flightClass enums.FlightClass) (*time.Time, error) {
+	fare, err := s.fareRepo.GetByFlight(flightCode, flightClass)
+	if err != nil {
+		return nil, err
+	}
+	if fare.DepartureAt == nil {
+		return nil, errors.NewDepartureNotFoundError(flightCode, 404)
+	}
+	return fare.DepartureAt, nil
+}
+
+// Calculates the price of the booking from the fare of its flight class, the age bands of its passengers,
+// the surcharges of its seats, its luggage items and the ancillaries of its passengers
+// Returns a FareNotFoundError when no fare has been configured for the flight class of the flight,
+// and a DepartureNotFoundError when the departure of the flight is unknown
+func (s *PricingService) Calculate(booking models.Booking) (*models.PriceBreakdown, error) {
+	fare, err := s.fareRepo.GetByFlight(booking.FlightCode, booking.FlightClass)
+	if err != nil {
+		return nil, err
+	}
+	if fare.DepartureAt == nil {
+		return nil, errors.NewDepartureNotFoundError(booking.FlightCode, 404)
+	}
+
+	price := &models.PriceBreakdown{Currency: fare.Currency, Items: []models.PriceItem{}}
+	price.Items = append(price.Items, fareItems(booking, fare)...)
+
+	seatItems, err := s.seatItems(booking, fare)
+	if err != nil {
+		return nil, err
+	}
+	price.Items = append(price.Items, seatItems...)
+	price.Items = append(price.Items, luggageItems(booking, fare)...)
+
+	ancillaryItems, err := s.ancillaryItems(booking, fare)
+	if err != nil {
+		return nil, err
+	}
+	price.Items = append(price.Items, ancillaryItems...)
+
+	for _, item := range price.Items {
+		price.TotalCents += item.AmountCents
+	}
+	return price, nil
+}
+
+// The age of a passenger is taken on the departure date of the flight, the departure sent by the client is never used
+func fareItems(booking models.Booking, fare *entities.FlightFareEntity) []models.PriceItem {
+	travelDate := *fare.DepartureAt
+
+	passengers := map[enums.AgeBand]int{}
+	for _, passenger := range booking.Passengers {
+		passengers[enums.AgeBandAt(passenger.DateOfBirth, travelDate)]++
+	}
+
+	items := []models.PriceItem{}
+	for _, ageBand := range ageBands {
+		if passengers[ageBand] == 0 {
+			continue
+		}
+		unitPrice := fare.BaseFareCents * ageBandFarePercentages[ageBand] / 100
+		items = append(items, newPriceItem("fare", fmt.Sprintf("%s %s fare", booking.FlightClass, ageBand), passengers[ageBand], unitPrice))
+	}
+	return items
+}
+
+// Every attribute of a selected seat with a surcharge is listed separately, e.g. an exit row seat with extra legroom
+func (s *PricingService) seatItems(booking models.Booking, fare *entities.FlightFareEntity) ([]models.PriceItem, error) {
+	surcharges := pricesFromJSONString(fare.SeatSurcharges)
+	if len(booking.Seats) == 0 || len(surcharges) == 0 {
+		return []models.PriceItem{}, nil
+	}
+
+	seatMap, err := s.seatService.GetByFlightCode(booking.FlightCode)
+	if err != nil {
+		return nil, err
+	}
+	attributes := map[string][]enums.SeatAttribute{}
+	for _, seat := range seatMap {
+		attributes[fmt.Sprintf("%d%s", seat.Row, seat.Column)] = seat.Attributes
+	}
+
+	items := []models.PriceItem{}
+	for _, seat := range booking.Seats {
+		seatNumber := fmt.Sprintf("%d%s", seat.Row, seat.Column)
+		for _, attribute := range attributes[seatNumber] {
+			if surcharge, ok := surcharges[string(attribute)]; ok {
+				items = append(items, newPriceItem("seat", fmt.Sprintf("Seat %s %s", seatNumber, attribute), 1, surcharge))
+			}
+		}
+	}
+	return items, nil
+}
+
+// The pieces included in the allowance of the flight class and luggage items without a price are part of the fare,
+// the rest is charged per piece and summed over the passengers
+func luggageItems(booking models.Booking, fare *entities.FlightFareEntity) []models.PriceItem {
+	prices := pricesFromJSONString(fare.LuggagePrices)
+
+	var order []enums.Luggage
+	quantities := map[enums.Luggage]int{}
+	for _, passenger := range booking.Passengers {
+		// The included pieces are taken off the first items of a type
+		pieces := map[enums.Luggage]int{}
+		for _, item := range passenger.Luggage {
+			included := max(0, item.Type.AllowanceIn(booking.FlightClass).Included-pieces[item.Type])
+			pieces[item.Type] += item.Quantity
+
+			charged := item.Quantity - included
+			if charged <= 0 || prices[string(item.Type)] == 0 {
+				continue
+			}
+			if quantities[item.Type] == 0 {
+				order = append(order, item.Type)
+			}
+			quantities[item.Type] += charged
+		}
+	}
+
+	items := []models.PriceItem{}
+	for _, luggage := range order {
+		items = append(items, newPriceItem("luggage", string(luggage), quantities[luggage], prices[string(luggage)]))
+	}
+	return items
+}
+
+// Ancillaries are charged at their price in the flight class and summed over the passengers,
+// the ones included in the flight class are left out
+// An ancillary priced in another currency than the fare cannot be added to the price
+func (s *PricingService) ancillaryItems(booking models.Booking, fare *entities.FlightFareEntity) ([]models.PriceItem, error) {
+	var order []string
+	quantities := map[string]int{}
+	for _, passenger := range booking.Passengers {
+		for _, code := range passenger.Ancillaries {
+			if quantities[code] == 0 {
+				order = append(order, code)
+			}
+			quantities[code]++
+		}
+	}
+	if len(order) == 0 {
+		return []models.PriceItem{}, nil
+	}
+
+	available, err := s.ancillaryService.GetAvailable(booking.FlightClass)
+	if err != nil {
+		return nil, err
+	}
+	catalog := map[string]models.Ancillary{}
+	for _, ancillary := range available {
+		catalog[ancillary.Code] = ancillary
+	}
+
+	items := []models.PriceItem{}
+	for _, code := range order {
+		ancillary, ok := catalog[code]
+		if !ok || ancillary.PriceCents == 0 {
+			continue
+		}
+		if ancillary.Currency != fare.Currency {
+			return nil, errors.NewAncillaryCurrencyError(code, ancillary.Currency, fare.Currency, 500)
+		}
+		items = append(items, newPriceItem("ancillary", ancillary.Name, quantities[code], ancillary.PriceCents))
+	}
+	return items, nil
+}
+
+func newPriceItem(itemType string, description string, quantity int, unitPriceCents int64) models.PriceItem {
+	return models.PriceItem{
+		Type:           itemType,
+		Description:    description,
+		Quantity:       quantity,
+		UnitPriceCents: unitPriceCents,
+		AmountCents:    unitPriceCents * int64(quantity),
+	}
+}
+
+func pricesFromJSONString(jsonInput string) map[string]int64 {
+	prices := map[string]int64{}
+	if jsonInput == "" {
+		return prices
+	}
+	if err := json.Unmarshal([]byte(jsonInput), &prices); err != nil {
+		return map[string]int64{}
+	}
+	return prices
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"flyhorizons-bookingservice/models"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/converter"
+	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/interfaces"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Seats a single user can hold on a flight at the same time
+const MaxHeldSeatsPerFlight = 9
+
+type SeatHoldService struct {
+	seatHoldRepo  interfaces.SeatHoldRepository
+	seatService   interfaces.SeatService
+	seatConverter converter.SeatConverter
+	holdTTL       time.Duration
+	maxLifetime   time.Duration
+}
+
+var _ interfaces.SeatHoldService = (*SeatHoldService)(nil)
+
+func NewSeatHoldService(repo interfaces.SeatHoldRepository, seatService interfaces.SeatService, seatConverter converter.SeatConverter, holdTTL time.Duration, maxLifetime time.Duration) *SeatHoldService {
+	return &SeatHoldService{
+		seatHoldRepo:  repo,
+		seatService:   seatService,
+		seatConverter: seatConverter,
+		holdTTL:       holdTTL,
+		maxLifetime:   maxLifetime,
+	}
+}
+
+// Reserves the seats for the user until the hold expires
+// Only seats on the seat map that are not blocked can be held, up to MaxHeldSeatsPerFlight per user and flight
+func (s *SeatHoldService) Hold(userID int, request models.SeatHoldRequest) (*models.SeatHold, error) {
+	if err := s.validateSeats(request); err != nil {
+		return nil, err
+	}
+
+	activeHolds, err := s.seatHoldRepo.GetActiveByUser(userID, request.FlightCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Seats the user holds already are replaced by the new hold, so they are only counted once
+	// and holding them again does not restart their lifetime
+	now := time.Now()
+	createdAt := now
+	heldSince := map[string]time.Time{}
+	for _, hold := range activeHolds {
+		heldSince[fmt.Sprintf("%d%s", hold.Row, hold.Column)] = hold.CreatedAt
+	}
+	for _, seat := range request.Seats {
+		key := fmt.Sprintf("%d%s", seat.Row, seat.Column)
+		if since, ok := heldSince[key]; ok {
+			if since.Before(createdAt) {
+				createdAt = since
+			}
+			continue
+		}
+		heldSince[key] = now
+	}
+	if len(heldSince) > MaxHeldSeatsPerFlight {
+		return nil, errors.NewSeatHoldCountLimitError(request.FlightCode, MaxHeldSeatsPerFlight, 409)
+	}
+
+	expiresAt, err := s.expiresAt(request.FlightCode, createdAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	holdID, err := newHoldID()
+	if err != nil {
+		return nil, err
+	}
+
+	holdEntities := s.seatConverter.ConvertSeatsToSeatHoldEntities(request.Seats, holdID, userID, request.FlightCode, createdAt, expiresAt)
+	if err := s.seatHoldRepo.Create(holdEntities); err != nil {
+		return nil, err
+	}
+
+	seatHold := s.seatConverter.ConvertSeatHoldEntitiesToSeatHold(holdEntities)
+	return &seatHold, nil
+}
+
+// Restarts the expiry of an active hold, up to the maximum lifetime of the hold
+func (s *SeatHoldService) Extend(userID int, holdID string) (*models.SeatHold, error) {
+	holdEntities, err := s.getOwnHold(userID, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := s.expiresAt(holdEntities[0].FlightCode, holdEntities[0].CreatedAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.seatHoldRepo.Extend(holdID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	for i := range holdEntities {
+		holdEntities[i].ExpiresAt = expiresAt
+	}
+	seatHold := s.seatConverter.ConvertSeatHoldEntitiesToSeatHold(holdEntities)
+	return &seatHold, nil
+}
+
+func (s *SeatHoldService) Release(userID int, holdID string) error {
+	if _, err := s.getOwnHold(userID, holdID); err != nil {
+		return err
+	}
+	return s.seatHoldRepo.Release(holdID)
+}
+
+// Removes the expired holds every interval, run it in a goroutine
+func (s *SeatHoldService) StartSweeper(interval time.Duration) {
+	for {
+		deleted, err := s.seatHoldRepo.DeleteExpired()
+		if err != nil {
+			log.Printf("Error removing expired seat holds: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Removed %d expired seat holds", deleted)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Only seats on the seat map of the flight that are not blocked can be held
+func (s *SeatHoldService) validateSeats(request models.SeatHoldRequest) error {
+	seatMap, err := s.seatService.GetByFlightCode(request.FlightCode)
+	if err != nil {
+		return err
+	}
+	blocked := map[string]bool{}
+	for _, seat := range seatMap {
+		blocked[fmt.Sprintf("%d%s", seat.Row, seat.Column)] = seat.Blocked
+	}
+
+	invalidSeats := []models.Seat{}
+	for _, seat := range request.Seats {
+		isBlocked, ok := blocked[fmt.Sprintf("%d%s", seat.Row, seat.Column)]
+		if !ok || isBlocked {
+			invalidSeats = append(invalidSeats, models.Seat{Row: seat.Row, Column: seat.Column})
+		}
+	}
+	if len(invalidSeats) > 0 {
+		return errors.NewInvalidSeatHoldError(request.FlightCode, invalidSeats, 400)
+	}
+	return nil
+}
+
+// A hold expires after the TTL, but never later than its maximum lifetime after it was created
+func (s *SeatHoldService) expiresAt(flightCode string, createdAt time.Time, now time.Time) (time.Time, error) {
+	deadline := createdAt.Add(s.maxLifetime)
+	if !deadline.After(now) {
+		return time.Time{}, errors.NewSeatHoldLifetimeLimitError(flightCode, s.maxLifetime, 409)
+	}
+
+	expiresAt := now.Add(s.holdTTL)
+	if expiresAt.After(deadline) {
+		expiresAt = deadline
+	}
+	return expiresAt, nil
+}
+
+// Holds of other users are reported as not found, so their hold IDs cannot be probed
+func (s *SeatHoldService) getOwnHold(userID int, holdID string) ([]entities.SeatHoldEntity, error) {
+	holdEntities, err := s.seatHoldRepo.GetActiveByHoldID(holdID)
+	if err != nil {
+		return nil, err
+	}
+	if len(holdEntities) == 0 || holdEntities[0].UserID != userID {
+		return nil, errors.NewSeatHoldNotFoundError(holdID, 404)
+	}
+	return holdEntities, nil
+}
+
+func newHoldID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
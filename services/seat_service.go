@@ -3,6 +3,7 @@ package services
 import (
 	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/services/converter"
+	"flyhorizons-bookingservice/services/errors"
 	"flyhorizons-bookingservice/services/interfaces"
 )
 
@@ -11,6 +12,8 @@ type SeatService struct {
 	seatConverter converter.SeatConverter
 }
 
+var _ interfaces.SeatService = (*SeatService)(nil)
+
 func NewSeatService(repo interfaces.SeatRepository, seatConverter converter.SeatConverter) *SeatService {
 	return &SeatService{
 		seatRepo:      repo,
@@ -18,8 +21,21 @@ func NewSeatService(repo interfaces.SeatRepository, seatConverter converter.Seat
 	}
 }
 
+// Returns the seat map of the aircraft the flight is operated with, including the availability of every seat
 func (seatService *SeatService) GetByFlightCode(flightCode string) ([]models.Seat, error) {
-	seatOptionEntities, err := seatService.seatRepo.GetByFlightCode(flightCode)
-	seats := seatService.seatConverter.ConvertSeatOptionEntitiesToSeats(seatOptionEntities)
-	return seats, err
+	configuration, err := seatService.seatRepo.GetConfigurationByFlightCode(flightCode)
+	if err != nil {
+		return nil, err
+	}
+	if configuration == nil {
+		return nil, errors.NewSeatMapNotFoundError(flightCode, 404)
+	}
+
+	occupiedSeats, err := seatService.seatRepo.GetOccupiedSeats(flightCode)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := seatService.seatConverter.ConvertAircraftConfigurationEntityToSeats(*configuration, occupiedSeats)
+	return seats, nil
 }
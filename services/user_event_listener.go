@@ -4,34 +4,30 @@ import (
 	"encoding/json"
 	"flyhorizons-bookingservice/config"
 	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/services/errors"
+	"fmt"
 	"log"
+
+	"github.com/rabbitmq/amqp091-go"
 )
 
 type UserEventListener struct {
-	rabbitMQClient *config.RabbitMQ
-	bookingService BookingService
+	rabbitMQClient  *config.RabbitMQ
+	bookingService  BookingService
+	messageConsumer *MessageConsumer
 }
 
 func NewUserEventListener(client *config.RabbitMQ, service BookingService) *UserEventListener {
 	return &UserEventListener{
-		rabbitMQClient: client,
-		bookingService: service,
+		rabbitMQClient:  client,
+		bookingService:  service,
+		messageConsumer: NewMessageConsumer(client),
 	}
 }
 
 func (userEventListener *UserEventListener) StartUserDeletedConsumer() {
-	channel := userEventListener.rabbitMQClient.Channel
-
-	messages, err := channel.Consume(
-		"user_deleted", // Queue Name
-		"",             // Consumer tag
-		true,           // Auto acknowledge
-		false,          // Exclusive
-		false,          // No-local
-		false,          // No-wait
-		nil,            // Arguments
-	)
-	if err != nil {
+	if err := userEventListener.messageConsumer.Consume("user_deleted", userEventListener.handleUserDeleted); err != nil {
 		log.Fatalf("An error occurred while registering the consumer: %v", err)
 	}
 
@@ -39,29 +35,43 @@ func (userEventListener *UserEventListener) StartUserDeletedConsumer() {
 	log.Printf("Started consumer for RabbitMQ queue: %s", "user_deleted")
 	log.Printf("Channel: RabbitMQ channel is active")
 
-	// Start goroutine to process messages
-	go func() {
-		for message := range messages {
-			// Log the message body
-			log.Printf("Received user deletion message: %s", string(message.Body))
+	log.Println("User deletion listener started successfully")
+}
 
-			var event models.UserDeletedEvent
-			if err := json.Unmarshal(message.Body, &event); err != nil {
-				log.Printf("An error occurred while converting the JSON to UserDeletedEvent: %v", err)
-				continue
-			}
+func (userEventListener *UserEventListener) handleUserDeleted(message amqp091.Delivery) error {
+	// Log the message body
+	log.Printf("Received user deletion message: %s", string(message.Body))
+
+	var event models.UserDeletedEvent
+	if err := json.Unmarshal(message.Body, &event); err != nil {
+		return errors.NewUnprocessableMessageError("user_deleted", fmt.Errorf("an error occurred while converting the JSON to UserDeletedEvent: %w", err))
+	}
 
-			userID := event.UserID
-			// Delete user data
-			bookings := userEventListener.bookingService.GetByUserID(userID)
-			for _, booking := range bookings {
-				userEventListener.bookingService.DeleteByBookingID(booking.ID)
+	userID := event.UserID
+	// Delete user data
+	// Active bookings are cancelled first so their seats are released, after which the bookings are soft-deleted
+	// Bookings deleted by an earlier attempt are hidden, so a retry only deletes the remaining ones
+	criteria := models.BookingSearchCriteria{Limit: MaxSearchLimit}
+	for {
+		page, err := userEventListener.bookingService.GetByUserID(userID, criteria)
+		if err != nil {
+			return err
+		}
+		for _, booking := range page.Bookings {
+			if booking.Status.CanTransitionTo(enums.Cancelled) {
+				if err := userEventListener.bookingService.Cancel(booking.ID, "User account deleted"); err != nil {
+					return err
+				}
+			}
+			if _, err := userEventListener.bookingService.DeleteByBookingID(booking.ID); err != nil {
+				return err
 			}
-			log.Printf("Successfully deleted the user data for UserID: %d from the booking database", userID)
 		}
-		// Log when the consumer stops (e.g., if the channel closes)
-		log.Printf("Consumer for queue %s stopped", "user_deleted")
-	}()
-
-	log.Println("User deletion listener started successfully")
+		if page.NextCursor == "" {
+			break
+		}
+		criteria.Cursor = page.NextCursor
+	}
+	log.Printf("Successfully deleted the user data for UserID: %d from the booking database", userID)
+	return nil
 }
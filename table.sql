@@ -4,9 +4,11 @@ CREATE TABLE Booking (
     UserID INT NOT NULL,
     FlightCode NVARCHAR(10) NOT NULL,
     FlightClass INT NOT NULL,
-    Luggage NVARCHAR(150) NOT NULL,
-    Status NVARCHAR(10) NULL,
-    CreatedAt DATETIME NOT NULL
+    Status NVARCHAR(20) NULL,
+    CreatedAt DATETIME NOT NULL,
+    DepartureAt DATETIME NULL, -- Unknown for bookings made before it was recorded
+    Version INT NOT NULL DEFAULT 1, -- Incremented on every change, updates are only applied to the version the client read
+    DeletedAt DATETIME NULL -- Set when the booking is soft-deleted, the row is kept until it is purged
 )
 
 -- Passenger Table
@@ -17,6 +19,8 @@ CREATE TABLE Passenger (
     DateOfBirth DATETIME NOT NULL,
     PassportNumber NVARCHAR(50) NOT NULL,
     Email NVARCHAR(255) NOT NULL,
+    Luggage NVARCHAR(500) NOT NULL DEFAULT '[]', -- JSON list of the luggage items of the passenger, with their quantity
+    Ancillaries NVARCHAR(500) NOT NULL DEFAULT '[]', -- JSON list of the codes of the ancillaries of the passenger
     FOREIGN KEY (BookingID) REFERENCES Booking(ID)
 )
 
@@ -25,16 +29,136 @@ CREATE TABLE Passenger (
 CREATE TABLE Seat (
     ID INT PRIMARY KEY IDENTITY(1, 1) NOT NULL,
     BookingID INT NOT NULL,
+    FlightCode NVARCHAR(10) NOT NULL, -- Copied from the Booking
     Row INT NOT NULL,
     [Column] CHAR(1) NOT NULL,  
+    Released BIT NOT NULL DEFAULT 0, -- Set when the Booking is cancelled, refunded or expired
     FOREIGN KEY (BookingID) REFERENCES Booking(ID)
 )
 
--- Seat Option
--- Seat options to choose from for the Booking
-CREATE TABLE SeatOption (
+-- A seat can only be booked once per flight, as long as the Booking is active
+CREATE UNIQUE INDEX UX_Seat_FlightSeat ON Seat (FlightCode, Row, [Column]) WHERE Released = 0
+
+-- Aircraft Configuration Table
+-- Seat layouts of the aircraft, the rows and seats with an attribute are JSON lists
+CREATE TABLE AircraftConfiguration (
     ID INT PRIMARY KEY IDENTITY(1, 1) NOT NULL,
+    Name NVARCHAR(50) NOT NULL,
+    ExitRows NVARCHAR(150) NOT NULL DEFAULT '[]',
+    ExtraLegroomRows NVARCHAR(150) NOT NULL DEFAULT '[]',
+    RestrictedReclineRows NVARCHAR(150) NOT NULL DEFAULT '[]',
+    BassinetSeats NVARCHAR(300) NOT NULL DEFAULT '[]',
+    BlockedSeats NVARCHAR(300) NOT NULL DEFAULT '[]'
+)
+
+-- Aircraft Cabin Table
+-- The rows of a flight class within an AircraftConfiguration, Columns lists the seat letters with "-" for an aisle (e.g. "ABC-DEF")
+CREATE TABLE AircraftCabin (
+    ID INT PRIMARY KEY IDENTITY(1, 1) NOT NULL,
+    ConfigurationID INT NOT NULL,
+    FlightClass INT NOT NULL,
+    FirstRow INT NOT NULL,
+    LastRow INT NOT NULL,
+    Columns NVARCHAR(20) NOT NULL,
+    FOREIGN KEY (ConfigurationID) REFERENCES AircraftConfiguration(ID)
+)
+
+-- Flight Seat Configuration Table
+-- The AircraftConfiguration every flight is operated with
+CREATE TABLE FlightSeatConfiguration (
+    FlightCode NVARCHAR(10) PRIMARY KEY NOT NULL,
+    ConfigurationID INT NOT NULL,
+    FOREIGN KEY (ConfigurationID) REFERENCES AircraftConfiguration(ID)
+)
+
+-- Flight Fare Table
+-- The fare of every flight class of a flight, all prices are in cents of the Currency
+-- SeatSurcharges maps a seat attribute to its surcharge, LuggagePrices a luggage item to its price, as JSON objects
+CREATE TABLE FlightFare (
+    FlightCode NVARCHAR(10) NOT NULL,
+    FlightClass INT NOT NULL,
+    DepartureAt DATETIME NULL, -- Same for every flight class of the flight, the flight cannot be booked while it is unknown
+    Currency NCHAR(3) NOT NULL,
+    BaseFareCents BIGINT NOT NULL,
+    SeatSurcharges NVARCHAR(300) NOT NULL DEFAULT '{}',
+    LuggagePrices NVARCHAR(300) NOT NULL DEFAULT '{}',
+    PRIMARY KEY (FlightCode, FlightClass)
+)
+
+-- Ancillary Table
+-- The catalog of extras that can be added to a passenger, e.g. a meal or priority boarding
+-- Prices maps a flight class to the price in cents of the Currency as a JSON object, e.g. {"Economy":1200,"Business":0}
+-- An ancillary is not available in a flight class without a price
+CREATE TABLE Ancillary (
+    Code NVARCHAR(50) PRIMARY KEY NOT NULL,
+    Category NVARCHAR(30) NOT NULL, -- Meal, PriorityBoarding, Lounge, TravelInsurance or PetInCabin
+    Name NVARCHAR(100) NOT NULL,
+    Description NVARCHAR(300) NULL,
+    Currency NCHAR(3) NOT NULL,
+    Prices NVARCHAR(300) NOT NULL DEFAULT '{}'
+)
+
+-- Booking Status History Table
+-- Every status change of a Booking, in the order it happened
+CREATE TABLE BookingStatusHistory (
+    ID INT PRIMARY KEY IDENTITY(1, 1) NOT NULL,
+    BookingID INT NOT NULL,
+    FromStatus NVARCHAR(20) NULL,
+    ToStatus NVARCHAR(20) NOT NULL,
+    Reason NVARCHAR(255) NULL,
+    ChangedAt DATETIME NOT NULL,
+    FOREIGN KEY (BookingID) REFERENCES Booking(ID)
+)
+
+-- Outbox Message Table
+-- Events written together with the Booking changes, published to RabbitMQ by the outbox relay
+CREATE TABLE OutboxMessage (
+    ID INT PRIMARY KEY IDENTITY(1, 1) NOT NULL,
+    Queue NVARCHAR(100) NOT NULL,
+    Payload NVARCHAR(MAX) NOT NULL,
+    Attempts INT NOT NULL DEFAULT 0,
+    LastError NVARCHAR(MAX) NULL,
+    CreatedAt DATETIME NOT NULL,
+    NextAttemptAt DATETIME NOT NULL,
+    DispatchedAt DATETIME NULL
+)
+
+CREATE INDEX IX_OutboxMessage_Pending ON OutboxMessage (DispatchedAt, NextAttemptAt)
+
+-- Seat Hold Table
+-- Seats reserved for a user during checkout, a seat can only be held once per flight
+CREATE TABLE SeatHold (
+    ID INT PRIMARY KEY IDENTITY(1, 1) NOT NULL,
+    HoldID NVARCHAR(32) NOT NULL,
+    UserID INT NOT NULL,
+    FlightCode NVARCHAR(10) NOT NULL,
     Row INT NOT NULL,
     [Column] CHAR(1) NOT NULL,
-    UNIQUE (Row, [Column]) -- Ensure no duplicate seats
-)
\ No newline at end of file
+    CreatedAt DATETIME NOT NULL,
+    ExpiresAt DATETIME NOT NULL,
+    CONSTRAINT UX_SeatHold_Seat UNIQUE (FlightCode, Row, [Column])
+)
+
+CREATE INDEX IX_SeatHold_HoldID ON SeatHold (HoldID)
+CREATE INDEX IX_SeatHold_ExpiresAt ON SeatHold (ExpiresAt)
+
+-- Idempotency Key Table
+-- The first response of a request with an Idempotency-Key, replayed when the request is retried
+CREATE TABLE IdempotencyKey (
+    [Key] NVARCHAR(255) PRIMARY KEY NOT NULL,
+    RequestHash CHAR(64) NOT NULL,
+    StatusCode INT NOT NULL DEFAULT 0, -- 0 while the first request is still being processed
+    ResponseBody NVARCHAR(MAX) NULL,
+    CreatedAt DATETIME NOT NULL,
+    ExpiresAt DATETIME NOT NULL
+)
+
+CREATE INDEX IX_IdempotencyKey_ExpiresAt ON IdempotencyKey (ExpiresAt)
+
+-- Indexes of the booking searches
+CREATE INDEX IX_Booking_CreatedAt ON Booking (CreatedAt, ID)
+CREATE INDEX IX_Booking_FlightCode ON Booking (FlightCode, CreatedAt)
+CREATE INDEX IX_Booking_UserID ON Booking (UserID, CreatedAt)
+CREATE INDEX IX_Booking_UserDeparture ON Booking (UserID, DepartureAt)
+CREATE INDEX IX_Passenger_PassportNumber ON Passenger (PassportNumber)
+CREATE INDEX IX_Booking_DeletedAt ON Booking (DeletedAt)
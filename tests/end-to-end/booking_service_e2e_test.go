@@ -10,6 +10,7 @@ import (
 	"flyhorizons-bookingservice/routes"
 	"flyhorizons-bookingservice/services"
 	"flyhorizons-bookingservice/services/converter"
+	"flyhorizons-bookingservice/services/idempotency"
 	mock_repositories "flyhorizons-bookingservice/tests/mocks"
 	"fmt"
 	"log"
@@ -46,7 +47,7 @@ func (repo *BookingServiceEndToEndTests) CreateConnection() (*gorm.DB, error) {
 	db.Exec("PRAGMA foreign_keys = ON")
 	db.Exec("PRAGMA journal_mode = WAL")
 
-	if err := db.AutoMigrate(&entities.BookingEntity{}, &entities.PassengerEntity{}, &entities.SeatEntity{}); err != nil {
+	if err := db.AutoMigrate(&entities.BookingEntity{}, &entities.PassengerEntity{}, &entities.SeatEntity{}, &entities.BookingStatusHistoryEntity{}, &entities.OutboxMessageEntity{}, &entities.SeatHoldEntity{}, &entities.AircraftConfigurationEntity{}, &entities.AircraftCabinEntity{}, &entities.FlightSeatConfigurationEntity{}, &entities.FlightFareEntity{}, &entities.IdempotencyKeyEntity{}, &entities.AncillaryEntity{}); err != nil {
 		log.Printf("Failed to auto-migrate schema: %v", err)
 		return nil, err
 	}
@@ -69,12 +70,14 @@ func cleanDatabase(repo *repositories.BookingRepository) {
 	repo.DB.Exec("PRAGMA foreign_keys = OFF")
 
 	// Delete in correct order to respect foreign key constraints
+	repo.DB.Exec("DELETE FROM BookingStatusHistory")
 	repo.DB.Exec("DELETE FROM Seat")
 	repo.DB.Exec("DELETE FROM Passenger")
 	repo.DB.Exec("DELETE FROM Booking")
+	repo.DB.Exec("DELETE FROM OutboxMessage")
 
 	// Reset auto-increment counters
-	repo.DB.Exec("DELETE FROM sqlite_sequence WHERE name IN ('Seat', 'Passenger', 'Booking')")
+	repo.DB.Exec("DELETE FROM sqlite_sequence WHERE name IN ('BookingStatusHistory', 'Seat', 'Passenger', 'Booking')")
 
 	// Re-enable foreign key constraints
 	repo.DB.Exec("PRAGMA foreign_keys = ON")
@@ -87,12 +90,16 @@ func getPassengerEntities() []entities.PassengerEntity {
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			Email:          "john@doe.com",
 			PassportNumber: "1234",
+			Luggage:        getLuggageString(),
+			Ancillaries:    "[]",
 		},
 		{
 			FullName:       "Jane Doe",
 			DateOfBirth:    time.Date(1986, 8, 8, 2, 30, 0, 0, time.UTC),
 			Email:          "jane@doe.com",
 			PassportNumber: "4321",
+			Luggage:        "[]",
+			Ancillaries:    "[]",
 		},
 	}
 }
@@ -105,13 +112,21 @@ func getSeatEntities() []entities.SeatEntity {
 }
 
 func getLuggageString() string {
-	return `["SmallBag","Cargo20kg"]`
+	return `[{"type":"SmallBag","quantity":1},{"type":"Cargo20kg","quantity":1}]`
+}
+
+func getPayment() models.Payment {
+	return models.Payment{Token: "pm_1PqR2sT3uV4wX5yZ", FirstName: "John", LastName: "Doe", Amount: 149.99, Currency: "EUR"}
 }
 
 func getDate() time.Time {
 	return time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC)
 }
 
+func getFlightDeparture() time.Time {
+	return time.Now().AddDate(0, 1, 0).UTC().Truncate(time.Second)
+}
+
 func setupBookings(repo *repositories.BookingRepository) []entities.BookingEntity {
 	// Clean database first
 	cleanDatabase(repo)
@@ -124,7 +139,6 @@ func setupBookings(repo *repositories.BookingRepository) []entities.BookingEntit
 			CreatedAt:   getDate(),
 			Passengers:  getPassengerEntities(),
 			Seats:       getSeatEntities(),
-			Luggage:     getLuggageString(),
 		},
 		{
 			UserID:      1,
@@ -133,10 +147,46 @@ func setupBookings(repo *repositories.BookingRepository) []entities.BookingEntit
 			CreatedAt:   getDate(),
 			Passengers:  getPassengerEntities(),
 			Seats:       getSeatEntities(),
-			Luggage:     getLuggageString(),
 		},
 	}
 
+	// FR788 has a seat map, so the seats of its booking can be changed
+	repo.DB.Exec("DELETE FROM FlightSeatConfiguration")
+	repo.DB.Exec("DELETE FROM AircraftCabin")
+	repo.DB.Exec("DELETE FROM AircraftConfiguration")
+	configuration := entities.AircraftConfigurationEntity{
+		Name: "E190 8J",
+		Cabins: []entities.AircraftCabinEntity{
+			{FlightClass: 1, FirstRow: 1, LastRow: 2, Columns: "AB-CD"},
+		},
+		BlockedSeats: `["2D"]`,
+	}
+	if err := repo.DB.Create(&configuration).Error; err != nil {
+		log.Fatalf("Failed to create aircraft configuration: %v", err)
+	}
+	if err := repo.DB.Create(&entities.FlightSeatConfigurationEntity{FlightCode: "FR788", ConfigurationID: configuration.ID}).Error; err != nil {
+		log.Fatalf("Failed to create flight seat configuration: %v", err)
+	}
+
+	// New bookings are made on FR790, which has no seat map
+	// FR788 has a fare as well, so the price of its booking can be checked when it is changed
+	repo.DB.Exec("DELETE FROM FlightFare")
+	departureAt := getFlightDeparture()
+	if err := repo.DB.Create(&[]entities.FlightFareEntity{
+		{FlightCode: "FR790", FlightClass: 1, DepartureAt: &departureAt, Currency: "EUR", BaseFareCents: 24900, LuggagePrices: `{"Cargo20kg":3500}`},
+		{FlightCode: "FR788", FlightClass: 1, DepartureAt: &departureAt, Currency: "EUR", BaseFareCents: 24900, LuggagePrices: `{"Cargo20kg":3500}`},
+	}).Error; err != nil {
+		log.Fatalf("Failed to create flight fares: %v", err)
+	}
+	repo.DB.Exec("DELETE FROM Ancillary")
+	if err := repo.DB.Create(&[]entities.AncillaryEntity{
+		{Code: "VegetarianMeal", Category: string(enums.Meal), Name: "Vegetarian meal", Currency: "EUR", Prices: `{"Economy":1200,"Business":0}`},
+		{Code: "PriorityBoarding", Category: string(enums.PriorityBoarding), Name: "Priority boarding", Currency: "EUR", Prices: `{"Economy":900,"Business":900}`},
+		{Code: "LoungeAccess", Category: string(enums.Lounge), Name: "Lounge access", Currency: "EUR", Prices: `{"Business":0}`},
+	}).Error; err != nil {
+		log.Fatalf("Failed to create ancillaries: %v", err)
+	}
+
 	// Save bookings with associations
 	for i := range testBookings {
 		if err := repo.DB.Create(&testBookings[i]).Error; err != nil {
@@ -156,13 +206,16 @@ func setupBookingService(repo *repositories.BookingRepository) *services.Booking
 	bookingConverter := converter.BookingConverter{}
 	passengerConverter := converter.PassengerConverter{}
 	seatConverter := converter.SeatConverter{}
-	return services.NewBookingService(repo, bookingConverter, passengerConverter, seatConverter)
+	seatService := services.NewSeatService(repositories.NewSeatRepository(repo.BaseRepository), seatConverter)
+	ancillaryService := services.NewAncillaryService(repositories.NewAncillaryRepository(repo.BaseRepository))
+	pricingService := services.NewPricingService(repositories.NewFareRepository(repo.BaseRepository), seatService, ancillaryService, []byte("e2e-quote-secret"), 15*time.Minute)
+	return services.NewBookingService(repo, seatService, pricingService, ancillaryService, bookingConverter, passengerConverter, seatConverter)
 }
 
-func setupBookingRouter(service services.BookingService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+func setupBookingRouter(service services.BookingService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware, idempotencyMiddleware *idempotency.IdempotencyMiddlewareHandler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
-	routes.RegisterBookingRoutes(router, &service, gatewayAuthMiddleware)
+	routes.RegisterBookingRoutes(router, &service, gatewayAuthMiddleware, idempotencyMiddleware)
 	return router
 }
 
@@ -174,6 +227,8 @@ func getFirstPassengers() []models.Passenger {
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			Email:          "john@doe.com",
 			PassportNumber: "1234",
+			Luggage:        getLuggages(),
+			Ancillaries:    []string{},
 		},
 		{
 			ID:             2,
@@ -181,6 +236,8 @@ func getFirstPassengers() []models.Passenger {
 			DateOfBirth:    time.Date(1986, 8, 8, 2, 30, 0, 0, time.UTC),
 			Email:          "jane@doe.com",
 			PassportNumber: "4321",
+			Luggage:        []models.LuggageItem{},
+			Ancillaries:    []string{},
 		},
 	}
 }
@@ -193,6 +250,8 @@ func getSecondPassengers() []models.Passenger {
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			Email:          "john@doe.com",
 			PassportNumber: "1234",
+			Luggage:        getLuggages(),
+			Ancillaries:    []string{},
 		},
 		{
 			ID:             4,
@@ -200,6 +259,8 @@ func getSecondPassengers() []models.Passenger {
 			DateOfBirth:    time.Date(1986, 8, 8, 2, 30, 0, 0, time.UTC),
 			Email:          "jane@doe.com",
 			PassportNumber: "4321",
+			Luggage:        []models.LuggageItem{},
+			Ancillaries:    []string{},
 		},
 	}
 }
@@ -219,10 +280,10 @@ func getSeats() []models.Seat {
 	}
 }
 
-func getLuggages() []enums.Luggage {
-	return []enums.Luggage{
-		enums.SmallBag,
-		enums.Cargo20kg,
+func getLuggages() []models.LuggageItem {
+	return []models.LuggageItem{
+		{Type: enums.SmallBag, Quantity: 1},
+		{Type: enums.Cargo20kg, Quantity: 1},
 	}
 }
 
@@ -235,7 +296,7 @@ func getBookings() []models.Booking {
 			FlightClass: 1,
 			Passengers:  getFirstPassengers(),
 			Seats:       getSeats(),
-			Luggage:     getLuggages(),
+			Version:     1,
 		},
 		{
 			ID:          2,
@@ -244,27 +305,41 @@ func getBookings() []models.Booking {
 			FlightClass: 0,
 			Passengers:  getSecondPassengers(),
 			Seats:       getSeats(),
-			Luggage:     getLuggages(),
+			Version:     1,
 		},
 	}
 }
 
 func setupTestEnvironment(userID int) (*repositories.BookingRepository, *services.BookingService, *gin.Engine) {
+	return setupTestEnvironmentWithRole("user", userID)
+}
+
+func setupTestEnvironmentWithRole(role string, userID int) (*repositories.BookingRepository, *services.BookingService, *gin.Engine) {
 	repo := NewTestBookingRepository()
 	setupBookings(repo)
 	service := setupBookingService(repo)
-	mockMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", userID)
-	router := setupBookingRouter(*service, mockMiddleware)
+	mockMiddleware := mock_repositories.NewMockGatewayAuthMiddleware(role, userID)
+	repo.DB.Exec("DELETE FROM IdempotencyKey")
+	idempotencyMiddleware := idempotency.NewIdempotencyMiddleware(repositories.NewIdempotencyRepository(repo.BaseRepository), time.Hour)
+	router := setupBookingRouter(*service, mockMiddleware, idempotencyMiddleware)
 	return repo, service, router
 }
 
+// Reads the booking like a client does before updating it, and returns its ETag
+func getBookingETag(router *gin.Engine, bookingID int) string {
+	httpRequest, _ := http.NewRequest("GET", fmt.Sprintf("/bookings/%d", bookingID), nil)
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httpRequest)
+	return responseRecorder.Header().Get("ETag")
+}
+
 // End-to-End Tests
 func TestEndToEndGetAllBookingsByMatchingUserIDReturnsBookings(t *testing.T) {
 	// Arrange
 	_, _, router := setupTestEnvironment(1)
 	mockBookings := getBookings()
 
-	url := "/bookings/"
+	url := "/bookings/?sort=id&order=asc"
 	httpRequest, _ := http.NewRequest("GET", url, nil)
 	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
 
@@ -284,10 +359,399 @@ func TestEndToEndGetAllBookingsByMatchingUserIDReturnsBookings(t *testing.T) {
 	assert.Equal(t, mockBookings, bookings)
 }
 
+func TestEndToEndGetBookingsPageReturnsPagingHeaders(t *testing.T) {
+	// Arrange
+	_, _, router := setupTestEnvironment(1)
+
+	firstRequest, _ := http.NewRequest("GET", "/bookings/?sort=id&order=asc&limit=1", nil)
+	firstRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	firstRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(firstRecorder, firstRequest)
+	nextCursor := firstRecorder.Header().Get("X-Next-Cursor")
+	secondRequest, _ := http.NewRequest("GET", "/bookings/?sort=id&order=asc&limit=1&cursor="+nextCursor, nil)
+	secondRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	secondRecorder := httptest.NewRecorder()
+	router.ServeHTTP(secondRecorder, secondRequest)
+
+	// Assert
+	var firstPage, secondPage []models.Booking
+	assert.NoError(t, json.Unmarshal(firstRecorder.Body.Bytes(), &firstPage))
+	assert.NoError(t, json.Unmarshal(secondRecorder.Body.Bytes(), &secondPage))
+	assert.Equal(t, "2", firstRecorder.Header().Get("X-Total-Count"))
+	assert.NotEmpty(t, nextCursor)
+	assert.Equal(t, []models.Booking{getBookings()[0]}, firstPage)
+	assert.Equal(t, []models.Booking{getBookings()[1]}, secondPage)
+	assert.Empty(t, secondRecorder.Header().Get("X-Next-Cursor"))
+}
+
+func TestEndToEndGetUpcomingBookingsReturnsOnlyFutureDepartures(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	repo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", 1).Update("DepartureAt", tomorrow)
+	repo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", 2).Update("DepartureAt", yesterday)
+
+	httpRequest, _ := http.NewRequest("GET", "/bookings/?timeframe=upcoming", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var bookings []models.Booking
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &bookings))
+	assert.Len(t, bookings, 1)
+	assert.Equal(t, 1, bookings[0].ID)
+	assert.Equal(t, "1", responseRecorder.Header().Get("X-Total-Count"))
+}
+
+func TestEndToEndGetBookingsWithUnknownTimeframeReturnsBadRequest(t *testing.T) {
+	// Arrange
+	_, _, router := setupTestEnvironment(1)
+
+	httpRequest, _ := http.NewRequest("GET", "/bookings/?timeframe=someday", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+}
+
+func TestEndToEndCreateBookingPublishesOnlyPaymentToken(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var outboxMessage entities.OutboxMessageEntity
+	assert.NoError(t, repo.DB.Where("Queue = ?", "booking.created").Order("ID desc").First(&outboxMessage).Error)
+	var paymentRequest models.PaymentRequest
+	assert.NoError(t, json.Unmarshal([]byte(outboxMessage.Payload), &paymentRequest))
+	assert.Equal(t, getPayment().Token, paymentRequest.Payment.Token)
+	assert.NotContains(t, outboxMessage.Payload, "iban")
+	assert.NotContains(t, outboxMessage.Payload, "cvv")
+}
+
+func TestEndToEndCreateBookingChargesCalculatedPrice(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	// A client trying to pay less than the fare
+	mockBooking.Payment.Amount = 0.01
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	// Every passenger pays the Business fare, the small bag is included and the 20kg bag is charged once
+	expectedTotal := int64(len(mockBooking.Passengers))*24900 + 3500
+	var createdBooking models.Booking
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &createdBooking))
+	assert.Equal(t, expectedTotal, createdBooking.Price.TotalCents)
+	assert.Equal(t, "EUR", createdBooking.Price.Currency)
+
+	var outboxMessage entities.OutboxMessageEntity
+	assert.NoError(t, repo.DB.Where("Queue = ?", "booking.created").Order("ID desc").First(&outboxMessage).Error)
+	var paymentRequest models.PaymentRequest
+	assert.NoError(t, json.Unmarshal([]byte(outboxMessage.Payload), &paymentRequest))
+	assert.Equal(t, float64(expectedTotal)/100, paymentRequest.Payment.Amount)
+	assert.Equal(t, *createdBooking.Price, paymentRequest.Price)
+}
+
+func TestEndToEndCreateBookingStoresLuggagePerPassenger(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	mockBooking.Passengers[1].Luggage = []models.LuggageItem{{Type: enums.Cargo30kg, Quantity: 2, WeightKg: 25}}
+	// New passengers, so the stored rows are the ones of this booking
+	mockBooking.Passengers[0].ID = 0
+	mockBooking.Passengers[1].ID = 0
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var createdBooking models.Booking
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &createdBooking))
+	var passengers []entities.PassengerEntity
+	assert.NoError(t, repo.DB.Where("BookingID = ?", createdBooking.ID).Order("ID").Find(&passengers).Error)
+	assert.Equal(t, getLuggageString(), passengers[0].Luggage)
+	assert.Equal(t, `[{"type":"Cargo30kg","quantity":2,"weight_kg":25}]`, passengers[1].Luggage)
+}
+
+func TestEndToEndCreateBookingAboveLuggageAllowanceReturnsBadRequest(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	mockBooking.Passengers[1].Luggage = []models.LuggageItem{{Type: enums.SportsEquipment, Quantity: 3}}
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "Jane Doe: 3 x SportsEquipment exceeds the maximum of 2 in Business")
+
+	var bookings int64
+	repo.DB.Model(&entities.BookingEntity{}).Where("FlightCode = ?", "FR790").Count(&bookings)
+	assert.Equal(t, int64(0), bookings)
+}
+
+func TestEndToEndCreateBookingWithAncillariesChargesAndPublishesThem(t *testing.T) {
+	// Arrange
+	repo, service, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	mockBooking.Passengers[0].ID = 0
+	mockBooking.Passengers[1].ID = 0
+	mockBooking.Passengers[0].Ancillaries = []string{"VegetarianMeal", "PriorityBoarding"}
+	mockBooking.Passengers[1].Ancillaries = []string{"LoungeAccess", "PriorityBoarding"}
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+	var createdBooking models.Booking
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &createdBooking))
+	confirmErr := service.UpdateStatus(createdBooking.ID, enums.Confirmed, "Payment succeeded")
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	assert.NoError(t, confirmErr)
+
+	// The meal and the lounge are included in Business, only the priority boarding is charged
+	assert.Contains(t, createdBooking.Price.Items, models.PriceItem{Type: "ancillary", Description: "Priority boarding", Quantity: 2, UnitPriceCents: 900, AmountCents: 1800})
+	assert.Equal(t, int64(2*24900+3500+1800), createdBooking.Price.TotalCents)
+
+	var outboxMessage entities.OutboxMessageEntity
+	assert.NoError(t, repo.DB.Where("Queue = ?", "booking.confirmed").Order("ID desc").First(&outboxMessage).Error)
+	var confirmedBooking models.Booking
+	assert.NoError(t, json.Unmarshal([]byte(outboxMessage.Payload), &confirmedBooking))
+	assert.Equal(t, []string{"VegetarianMeal", "PriorityBoarding"}, confirmedBooking.Passengers[0].Ancillaries)
+	assert.Equal(t, []string{"LoungeAccess", "PriorityBoarding"}, confirmedBooking.Passengers[1].Ancillaries)
+}
+
+func TestEndToEndCreateBookingWithUnavailableAncillaryReturnsBadRequest(t *testing.T) {
+	// Arrange
+	_, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 0, Passengers: getFirstPassengers(), Payment: getPayment()}
+	mockBooking.Passengers[0].Luggage = nil
+	mockBooking.Passengers[0].Ancillaries = []string{"LoungeAccess"}
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "John Doe: LoungeAccess is not available in Economy")
+}
+
+func TestEndToEndCreateBookingWithQuoteChargesQuotedPriceAfterFareChange(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	quoteBody, _ := json.Marshal(mockBooking)
+	quoteRequest, _ := http.NewRequest("POST", "/bookings/quote", bytes.NewBuffer(quoteBody))
+	quoteRequest.Header.Set("Content-Type", "application/json")
+	quoteRecorder := httptest.NewRecorder()
+	router.ServeHTTP(quoteRecorder, quoteRequest)
+	var quote models.Quote
+	assert.NoError(t, json.Unmarshal(quoteRecorder.Body.Bytes(), &quote))
+
+	// The fare goes up while the user looks at the quote
+	repo.DB.Model(&entities.FlightFareEntity{}).Where("FlightCode = ?", "FR790").Update("BaseFareCents", 99900)
+
+	mockBooking.QuoteID = quote.QuoteID
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, quoteRecorder.Code)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var createdBooking models.Booking
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &createdBooking))
+	assert.Equal(t, quote.Price, *createdBooking.Price)
+
+	var outboxMessage entities.OutboxMessageEntity
+	assert.NoError(t, repo.DB.Where("Queue = ?", "booking.created").Order("ID desc").First(&outboxMessage).Error)
+	var paymentRequest models.PaymentRequest
+	assert.NoError(t, json.Unmarshal([]byte(outboxMessage.Payload), &paymentRequest))
+	assert.Equal(t, quote.Price.Total(), paymentRequest.Payment.Amount)
+}
+
+func TestEndToEndCreateBookingWithQuoteOfOtherBookingReturnsBadRequest(t *testing.T) {
+	// Arrange
+	_, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	quoteBody, _ := json.Marshal(mockBooking)
+	quoteRequest, _ := http.NewRequest("POST", "/bookings/quote", bytes.NewBuffer(quoteBody))
+	quoteRequest.Header.Set("Content-Type", "application/json")
+	quoteRecorder := httptest.NewRecorder()
+	router.ServeHTTP(quoteRecorder, quoteRequest)
+	var quote models.Quote
+	assert.NoError(t, json.Unmarshal(quoteRecorder.Body.Bytes(), &quote))
+
+	// Luggage is added after the quote was made
+	mockBooking.QuoteID = quote.QuoteID
+	mockBooking.Passengers[1].Luggage = []models.LuggageItem{{Type: enums.Cargo20kg, Quantity: 1}}
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+}
+
+func TestEndToEndCreateBookingWithoutFareReturnsNotFound(t *testing.T) {
+	// Arrange
+	_, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 0, Passengers: getFirstPassengers(), Payment: getPayment()}
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func TestEndToEndCreateBookingStoresDepartureOfFlight(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	var fare entities.FlightFareEntity
+	repo.DB.Where("FlightCode = ?", "FR790").First(&fare)
+	// A departure in the past would make the passengers younger and their fares cheaper
+	clientDepartureAt := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, DepartureAt: &clientDepartureAt, Passengers: getFirstPassengers(), Payment: getPayment()}
+	mockBooking.Passengers[0].ID = 0
+	mockBooking.Passengers[1].ID = 0
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	var createdBooking models.Booking
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &createdBooking))
+	var storedBooking entities.BookingEntity
+	assert.NoError(t, repo.DB.First(&storedBooking, createdBooking.ID).Error)
+	assert.True(t, fare.DepartureAt.Equal(*storedBooking.DepartureAt))
+}
+
+func TestEndToEndCreateBookingOfFlightWithUnknownDepartureReturnsNotFound(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	repo.DB.Model(&entities.FlightFareEntity{}).Where("FlightCode = ?", "FR790").Update("DepartureAt", nil)
+	mockBooking := models.Booking{FlightCode: "FR790", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func TestEndToEndCreateBookingWithSameSeatTwiceReturnsBadRequest(t *testing.T) {
+	// Arrange
+	_, _, router := setupTestEnvironment(1)
+	mockBooking := models.Booking{FlightCode: "FR788", FlightClass: 1, Passengers: getFirstPassengers(), Payment: getPayment()}
+	mockBooking.Seats = []models.Seat{{Row: 1, Column: "A"}, {Row: 1, Column: "A"}}
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "seat 1A was selected more than once")
+}
+
+func TestEndToEndCreateBookingWithRawCardDataReturnsBadRequest(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	requestBody := `{"flight_code": "FR790", "flight_class": 1, "payment": {"iban": "NL91ABNA0417164300", "cvv": "123", "amount": 149.99}}`
+	httpRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBufferString(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	var outboxMessages int64
+	repo.DB.Model(&entities.OutboxMessageEntity{}).Count(&outboxMessages)
+	assert.Equal(t, int64(0), outboxMessages)
+}
+
 func TestEndToEndCreateExistingBookingReturnsConflictError(t *testing.T) {
 	// Arrange
 	_, _, router := setupTestEnvironment(1)
 	mockBooking := getBookings()[0]
+	mockBooking.Payment = getPayment()
 
 	// Make the JSON to create the booking
 	requestBody, _ := json.Marshal(mockBooking)
@@ -302,15 +766,99 @@ func TestEndToEndCreateExistingBookingReturnsConflictError(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
 }
 
-func TestEndToEndDeleteExistingBookingReturnsDeletedSuccessfully(t *testing.T) {
+func TestEndToEndCreateBookingRetriedWithSameIdempotencyKeyReplaysResponse(t *testing.T) {
 	// Arrange
 	_, _, router := setupTestEnvironment(1)
 	mockBooking := getBookings()[0]
+	mockBooking.Payment = getPayment()
+	requestBody, _ := json.Marshal(mockBooking)
+
+	firstRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	firstRequest.Header.Set("Content-Type", "application/json")
+	firstRequest.Header.Set("Idempotency-Key", "checkout-1")
+	firstResponse := httptest.NewRecorder()
+
+	retryRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(requestBody))
+	retryRequest.Header.Set("Content-Type", "application/json")
+	retryRequest.Header.Set("Idempotency-Key", "checkout-1")
+	retryResponse := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(firstResponse, firstRequest)
+	router.ServeHTTP(retryResponse, retryRequest)
+
+	// Assert
+	assert.Equal(t, firstResponse.Code, retryResponse.Code)
+	assert.Equal(t, firstResponse.Body.String(), retryResponse.Body.String())
+	assert.Equal(t, "true", retryResponse.Header().Get("Idempotent-Replayed"))
+}
+
+func TestEndToEndCreateBookingWithReusedIdempotencyKeyAndDifferentPayloadReturnsUnprocessableEntity(t *testing.T) {
+	// Arrange
+	_, _, router := setupTestEnvironment(1)
+	firstBody, _ := json.Marshal(getBookings()[0])
+	secondBody, _ := json.Marshal(getBookings()[1])
+
+	firstRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(firstBody))
+	firstRequest.Header.Set("Content-Type", "application/json")
+	firstRequest.Header.Set("Idempotency-Key", "checkout-2")
+
+	secondRequest, _ := http.NewRequest("POST", "/bookings", bytes.NewBuffer(secondBody))
+	secondRequest.Header.Set("Content-Type", "application/json")
+	secondRequest.Header.Set("Idempotency-Key", "checkout-2")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), firstRequest)
+	router.ServeHTTP(responseRecorder, secondRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+}
+
+func TestEndToEndRetryPaymentOfFailedPaymentRequestsPaymentAgain(t *testing.T) {
+	// Arrange
+	repo, service, router := setupTestEnvironment(1)
+	mockBookingID := getBookings()[0].ID
+	repo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", mockBookingID).Update("Status", string(enums.PaymentFailed))
+	requestBody, _ := json.Marshal(getPayment())
+	httpRequest, _ := http.NewRequest("POST", fmt.Sprintf("/bookings/%v/payment", mockBookingID), bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	storedBooking, err := service.GetByID(mockBookingID)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.AwaitingPayment, storedBooking.Status)
+	history, err := service.GetStatusHistory(mockBookingID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Payment retried", history[len(history)-1].Reason)
+
+	// The payment service is asked to charge the new payment token
+	var outboxMessage entities.OutboxMessageEntity
+	assert.NoError(t, repo.DB.Where("Queue = ?", "booking.created").Order("ID desc").First(&outboxMessage).Error)
+	var paymentRequest models.PaymentRequest
+	assert.NoError(t, json.Unmarshal([]byte(outboxMessage.Payload), &paymentRequest))
+	assert.Equal(t, mockBookingID, paymentRequest.BookingID)
+	assert.Equal(t, getPayment().Token, paymentRequest.Payment.Token)
+}
+
+func TestEndToEndDeleteExistingBookingCancelsBookingAndReleasesSeats(t *testing.T) {
+	// Arrange
+	repo, service, router := setupTestEnvironment(1)
+	mockBooking := getBookings()[0]
 	mockBookingID := mockBooking.ID
+	repo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", mockBookingID).Update("Status", string(enums.Confirmed))
 
 	url := fmt.Sprintf("/bookings/%v", mockBookingID)
-	httpRequest, _ := http.NewRequest("DELETE", url, nil)
+	httpRequest, _ := http.NewRequest("DELETE", url, bytes.NewBufferString(`{"reason": "Change of plans"}`))
 	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("Content-Type", "application/json")
 
 	responseRecorder := httptest.NewRecorder()
 
@@ -319,6 +867,38 @@ func TestEndToEndDeleteExistingBookingReturnsDeletedSuccessfully(t *testing.T) {
 
 	// Assert
 	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	// The booking is kept with the cancellation in its history
+	storedBooking, err := service.GetByID(mockBookingID)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.Cancelled, storedBooking.Status)
+	history, err := service.GetStatusHistory(mockBookingID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Change of plans", history[len(history)-1].Reason)
+
+	// The seats are released for other bookings
+	var activeSeats int64
+	repo.DB.Model(&entities.SeatEntity{}).Where("BookingID = ? AND Released = ?", mockBookingID, false).Count(&activeSeats)
+	assert.Equal(t, int64(0), activeSeats)
+}
+
+func TestEndToEndDeleteCancelledBookingReturnsConflict(t *testing.T) {
+	// Arrange
+	repo, _, router := setupTestEnvironment(1)
+	mockBookingID := getBookings()[0].ID
+	repo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", mockBookingID).Update("Status", string(enums.Cancelled))
+
+	url := fmt.Sprintf("/bookings/%v", mockBookingID)
+	httpRequest, _ := http.NewRequest("DELETE", url, nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
 }
 
 func TestEndToEndDeleteNonExistingBookingReturnsNotFoundError(t *testing.T) {
@@ -343,11 +923,13 @@ func TestEndToEndUpdateExistingBookingUsingMatchingUserIDReturnsUpdatedBooking(t
 	// Arrange
 	_, _, router := setupTestEnvironment(1)
 	mockBooking := getBookings()[0]
+	etag := getBookingETag(router, mockBooking.ID)
 
 	// Make the JSON to update the booking
 	requestBody, _ := json.Marshal(mockBooking)
 	httpRequest, _ := http.NewRequest("PUT", "/bookings/", bytes.NewBuffer(requestBody))
 	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("If-Match", etag)
 	responseRecorder := httptest.NewRecorder()
 
 	// Act
@@ -360,8 +942,87 @@ func TestEndToEndUpdateExistingBookingUsingMatchingUserIDReturnsUpdatedBooking(t
 	var booking models.Booking
 	err := json.Unmarshal(responseRecorder.Body.Bytes(), &booking)
 
+	// Every update moves the booking to the next version
+	mockBooking.Version = 2
 	assert.NoError(t, err)
 	assert.Equal(t, mockBooking, booking)
+	assert.Equal(t, getBookingETag(router, mockBooking.ID), responseRecorder.Header().Get("ETag"))
+}
+
+func TestEndToEndUpdateBookingAsSupportAgentWithoutUserIDKeepsOwner(t *testing.T) {
+	// Arrange
+	_, service, router := setupTestEnvironmentWithRole("support-agent", 999)
+	mockBooking := getBookings()[0]
+	etag := getBookingETag(router, mockBooking.ID)
+	// The agent leaves out user_id, the booking has to stay with its owner
+	mockBooking.UserID = 0
+	mockBooking.Passengers[0].Email = "john.doe@doe.com"
+
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("PUT", "/bookings/", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("If-Match", etag)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+	storedBooking, err := service.GetByID(mockBooking.ID)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, storedBooking.UserID)
+	assert.Equal(t, "john.doe@doe.com", storedBooking.Passengers[0].Email)
+}
+
+func TestEndToEndUpdateBookingWithExtraLuggageReturnsBadRequest(t *testing.T) {
+	// Arrange
+	_, _, router := setupTestEnvironment(1)
+	mockBooking := getBookings()[0]
+	// The second checked bag would have to be paid for
+	mockBooking.Passengers[0].Luggage = append(mockBooking.Passengers[0].Luggage, models.LuggageItem{Type: enums.Cargo20kg, Quantity: 1})
+	etag := getBookingETag(router, mockBooking.ID)
+
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("PUT", "/bookings/", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("If-Match", etag)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "the changes would change the price of the booking from 533.00 to 568.00 EUR")
+	assert.Equal(t, etag, getBookingETag(router, mockBooking.ID))
+}
+
+func TestEndToEndUpdateBookingChangedByStatusUpdateReturnsPreconditionFailed(t *testing.T) {
+	// Arrange
+	repo, service, router := setupTestEnvironment(1)
+	mockBooking := getBookings()[0]
+	etag := getBookingETag(router, mockBooking.ID)
+	// A payment event changes the status while the user is editing the passengers
+	repo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", mockBooking.ID).Update("Status", string(enums.AwaitingPayment))
+	statusErr := service.UpdateStatus(mockBooking.ID, enums.Confirmed, "Payment succeeded")
+	mockBooking.Passengers = mockBooking.Passengers[:1]
+
+	requestBody, _ := json.Marshal(mockBooking)
+	httpRequest, _ := http.NewRequest("PUT", "/bookings/", bytes.NewBuffer(requestBody))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("If-Match", etag)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+	storedBooking, _ := service.GetByID(mockBooking.ID)
+
+	// Assert
+	assert.NoError(t, statusErr)
+	assert.Equal(t, http.StatusPreconditionFailed, responseRecorder.Code)
+	assert.Equal(t, enums.Confirmed, storedBooking.Status)
+	assert.Len(t, storedBooking.Passengers, 2)
 }
 
 func TestEndToEndUpdateNonExistingBookingReturnsNotFoundError(t *testing.T) {
@@ -374,6 +1035,7 @@ func TestEndToEndUpdateNonExistingBookingReturnsNotFoundError(t *testing.T) {
 	requestBody, _ := json.Marshal(mockBooking)
 	httpRequest, _ := http.NewRequest("PUT", "/bookings/", bytes.NewBuffer(requestBody))
 	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("If-Match", `"0123456789abcdef0123456789abcdef"`)
 	responseRecorder := httptest.NewRecorder()
 
 	// Act
@@ -392,6 +1054,7 @@ func TestEndToEndUpdateBookingUsingNonMatchingUserIDReturnsAccessDenied(t *testi
 	requestBody, _ := json.Marshal(mockBooking)
 	httpRequest, _ := http.NewRequest("PUT", "/bookings/", bytes.NewBuffer(requestBody))
 	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("If-Match", `"0123456789abcdef0123456789abcdef"`)
 	responseRecorder := httptest.NewRecorder()
 
 	// Act
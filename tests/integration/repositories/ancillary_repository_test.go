@@ -0,0 +1,48 @@
+package repositories_test
+
+import (
+	"flyhorizons-bookingservice/repositories"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTestAncillaryRepository() *repositories.AncillaryRepository {
+	baseRepo := &TestBookingRepository{}
+	_, err := baseRepo.CreateConnection()
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+	return repositories.NewAncillaryRepository(&baseRepo.BaseRepository)
+}
+
+func getAncillaries(repo *repositories.AncillaryRepository) []entities.AncillaryEntity {
+	ancillaries := []entities.AncillaryEntity{
+		{Code: "VegetarianMeal", Category: "Meal", Name: "Vegetarian meal", Currency: "EUR", Prices: `{"Economy":1200,"Business":0}`},
+		{Code: "ChildMeal", Category: "Meal", Name: "Child meal", Currency: "EUR", Prices: `{"Economy":900,"Business":0}`},
+		{Code: "LoungeAccess", Category: "Lounge", Name: "Lounge access", Description: "Access to the airport lounge", Currency: "EUR", Prices: `{"Business":0}`},
+	}
+
+	// Clear any existing data
+	repo.DB.Exec("DELETE FROM Ancillary")
+
+	if err := repo.DB.Create(&ancillaries).Error; err != nil {
+		log.Fatalf("Failed to create ancillaries: %v", err)
+	}
+	return ancillaries
+}
+
+func TestAncillaryRepositoryGetAllReturnsCatalogOrderedByCategoryAndCode(t *testing.T) {
+	// Arrange
+	ancillaryRepo := NewTestAncillaryRepository()
+	ancillaries := getAncillaries(ancillaryRepo)
+
+	// Act
+	catalog, err := ancillaryRepo.GetAll()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.AncillaryEntity{ancillaries[2], ancillaries[1], ancillaries[0]}, catalog)
+}
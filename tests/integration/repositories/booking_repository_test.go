@@ -1,8 +1,14 @@
 package repositories_test
 
 import (
+	"context"
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
 	"flyhorizons-bookingservice/repositories"
 	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
+	"flyhorizons-bookingservice/services/interfaces"
+	"fmt"
 	"log"
 	"testing"
 	"time"
@@ -31,7 +37,7 @@ func (repo *TestBookingRepository) CreateConnection() (*gorm.DB, error) {
 	// Enable foreign key support
 	db.Exec("PRAGMA foreign_keys = ON")
 
-	if err := db.AutoMigrate(&entities.BookingEntity{}, &entities.PassengerEntity{}, &entities.SeatEntity{}); err != nil {
+	if err := db.AutoMigrate(&entities.BookingEntity{}, &entities.PassengerEntity{}, &entities.SeatEntity{}, &entities.BookingStatusHistoryEntity{}, &entities.OutboxMessageEntity{}, &entities.SeatHoldEntity{}, &entities.AircraftConfigurationEntity{}, &entities.AircraftCabinEntity{}, &entities.FlightSeatConfigurationEntity{}, &entities.FlightFareEntity{}, &entities.IdempotencyKeyEntity{}, &entities.AncillaryEntity{}); err != nil {
 		log.Printf("Failed to auto-migrate schema: %v", err)
 		return nil, err
 	}
@@ -55,6 +61,7 @@ func getPassengerEntities() []entities.PassengerEntity {
 			FullName:       "John Doe",
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			PassportNumber: "1234",
+			Luggage:        getLuggageString(),
 		},
 		{
 			FullName:       "Jane Doe",
@@ -72,7 +79,7 @@ func getSeatEntities() []entities.SeatEntity {
 }
 
 func getLuggageString() string {
-	return `["SmallBag","Cargo20kg"]`
+	return `[{"type":"SmallBag","quantity":1},{"type":"Cargo20kg","quantity":1,"weight_kg":18}]`
 }
 
 func getDate() time.Time {
@@ -88,7 +95,6 @@ func getBookings(repo *repositories.BookingRepository) []entities.BookingEntity
 			CreatedAt:   getDate(),
 			Passengers:  getPassengerEntities(),
 			Seats:       getSeatEntities(),
-			Luggage:     getLuggageString(),
 		},
 		{
 			UserID:      4,
@@ -97,11 +103,11 @@ func getBookings(repo *repositories.BookingRepository) []entities.BookingEntity
 			CreatedAt:   getDate(),
 			Passengers:  getPassengerEntities(),
 			Seats:       getSeatEntities(),
-			Luggage:     getLuggageString(),
 		},
 	}
 
 	// Clear any existing data
+	repo.DB.Exec("DELETE FROM BookingStatusHistory")
 	repo.DB.Exec("DELETE FROM Seat")
 	repo.DB.Exec("DELETE FROM Passenger")
 	repo.DB.Exec("DELETE FROM Booking")
@@ -126,9 +132,10 @@ func TestBookingRepositoryGetAllReturnsBookings(t *testing.T) {
 	testBookings := getBookings(bookingRepo)
 
 	// Act
-	bookings := bookingRepo.GetAll()
+	bookings, err := bookingRepo.GetAll()
 
 	// Assert
+	assert.NoError(t, err)
 	assert.Equal(t, testBookings, bookings)
 }
 
@@ -139,10 +146,13 @@ func TestBookingRepositoryGetByValidUserIDReturnsBookings(t *testing.T) {
 	userID := 2
 
 	// Act
-	bookings := bookingRepo.GetByUserID(userID)
+	bookings, totalCount, nextCursor, err := bookingRepo.GetByUserID(userID, getSearchCriteria())
 
 	// Assert
+	assert.NoError(t, err)
 	assert.Equal(t, userBookings, bookings)
+	assert.Equal(t, int64(1), totalCount)
+	assert.Empty(t, nextCursor)
 }
 
 func TestBookingRepositoryGetByInvalidUserIDReturnsNoBookings(t *testing.T) {
@@ -151,10 +161,12 @@ func TestBookingRepositoryGetByInvalidUserIDReturnsNoBookings(t *testing.T) {
 	invalidUserID := 999
 
 	// Act
-	bookings := bookingRepo.GetByUserID(invalidUserID)
+	bookings, totalCount, _, err := bookingRepo.GetByUserID(invalidUserID, getSearchCriteria())
 
 	// Assert
+	assert.NoError(t, err)
 	assert.Equal(t, []entities.BookingEntity{}, bookings)
+	assert.Equal(t, int64(0), totalCount)
 }
 
 func TestBookingRepositoryCreateBookingReturnsNewBooking(t *testing.T) {
@@ -169,68 +181,793 @@ func TestBookingRepositoryCreateBookingReturnsNewBooking(t *testing.T) {
 		CreatedAt:   getDate(),
 		Passengers:  getPassengerEntities(),
 		Seats:       getSeatEntities(),
-		Luggage:     getLuggageString(),
 	}
 
 	// Act
-	booking := bookingRepo.Create(bookingEntity)
-	bookings := bookingRepo.GetAll()
+	booking, err := bookingRepo.Create(bookingEntity)
+	bookings, _ := bookingRepo.GetAll()
 
 	// Assert
+	// New bookings start at the first version
+	bookingEntity.Version = 1
+	assert.NoError(t, err)
 	assert.Len(t, bookings, len(testBookings)+1)
 	assert.Equal(t, bookingEntity, *booking)
 }
 
-func TestBookingRepositoryDeleteByValidIDReturnsTrue(t *testing.T) {
+func TestBookingRepositoryDeleteByValidIDSoftDeletesBooking(t *testing.T) {
 	// Arrange
 	bookingRepo := NewTestBookingRepository()
 	testBookings := getBookings(bookingRepo)
 	bookingID := testBookings[0].ID
 
 	// Act
-	isDeleted := bookingRepo.DeleteByBookingID(bookingID)
-	bookings := bookingRepo.GetAll()
+	err := bookingRepo.DeleteByBookingID(bookingID)
+	bookings, _ := bookingRepo.GetAll()
+	_, getErr := bookingRepo.GetByID(bookingID)
+
+	var storedBookings, passengers, seats int64
+	bookingRepo.DB.Unscoped().Model(&entities.BookingEntity{}).Where("ID = ?", bookingID).Count(&storedBookings)
+	bookingRepo.DB.Model(&entities.PassengerEntity{}).Where("BookingID = ?", bookingID).Count(&passengers)
+	bookingRepo.DB.Model(&entities.SeatEntity{}).Where("BookingID = ?", bookingID).Count(&seats)
 
 	// Assert
+	assert.NoError(t, err)
 	assert.Len(t, bookings, len(testBookings)-1)
-	assert.True(t, isDeleted)
+	assert.Equal(t, errors.NewBookingNotFoundError(bookingID, 404), getErr)
+	// The row and its children are kept until the booking is purged
+	assert.Equal(t, int64(1), storedBookings)
+	assert.Equal(t, int64(len(testBookings[0].Passengers)), passengers)
+	assert.Equal(t, int64(len(testBookings[0].Seats)), seats)
+}
+
+func TestBookingRepositoryDeleteSoftDeletedBookingReturnsNotFoundError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingID := testBookings[0].ID
+	bookingRepo.DeleteByBookingID(bookingID)
+
+	// Act
+	err := bookingRepo.DeleteByBookingID(bookingID)
+	exists, _ := bookingRepo.Exists(bookingID)
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotFoundError(bookingID, 404), err)
+	assert.False(t, exists)
 }
 
-func TestBookingRepositoryDeleteByInvalidIDReturnsFalse(t *testing.T) {
+func TestBookingRepositoryPurgeDeletesBookingsSoftDeletedBeforeDate(t *testing.T) {
 	// Arrange
 	bookingRepo := NewTestBookingRepository()
 	testBookings := getBookings(bookingRepo)
-	invalidFlightCode := 999
+	purgedID := testBookings[0].ID
+	retainedID := testBookings[1].ID
+	bookingRepo.DeleteByBookingID(purgedID)
+	bookingRepo.DeleteByBookingID(retainedID)
+	// Only the first booking was deleted before the retention period
+	bookingRepo.DB.Unscoped().Model(&entities.BookingEntity{}).Where("ID = ?", purgedID).Update("DeletedAt", time.Now().AddDate(-1, 0, 0))
 
 	// Act
-	isDeleted := bookingRepo.DeleteByBookingID(invalidFlightCode)
-	bookings := bookingRepo.GetAll()
+	purged, err := bookingRepo.Purge(time.Now().AddDate(0, -1, 0))
+
+	var purgedBookings, retainedBookings, passengers, seats int64
+	bookingRepo.DB.Unscoped().Model(&entities.BookingEntity{}).Where("ID = ?", purgedID).Count(&purgedBookings)
+	bookingRepo.DB.Unscoped().Model(&entities.BookingEntity{}).Where("ID = ?", retainedID).Count(&retainedBookings)
+	bookingRepo.DB.Model(&entities.PassengerEntity{}).Where("BookingID = ?", purgedID).Count(&passengers)
+	bookingRepo.DB.Model(&entities.SeatEntity{}).Where("BookingID = ?", purgedID).Count(&seats)
 
 	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Equal(t, int64(0), purgedBookings)
+	assert.Equal(t, int64(1), retainedBookings)
+	assert.Equal(t, int64(0), passengers)
+	assert.Equal(t, int64(0), seats)
+}
+
+func TestBookingRepositoryPurgeKeepsBookingsThatAreNotDeleted(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+
+	// Act
+	purged, err := bookingRepo.Purge(time.Now().Add(time.Hour))
+	bookings, _ := bookingRepo.GetAll()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+	assert.Len(t, bookings, len(testBookings))
+}
+
+func TestBookingRepositoryPurgeDeletesBookingsCancelledBeforeDate(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	purgedID := testBookings[0].ID
+	retainedID := testBookings[1].ID
+	bookingRepo.DB.Model(&entities.BookingEntity{}).Where("ID IN ?", []int{purgedID, retainedID}).Update("Status", "Confirmed")
+	bookingRepo.UpdateStatus(purgedID, enums.Confirmed, enums.Cancelled, "Change of plans")
+	bookingRepo.UpdateStatus(retainedID, enums.Confirmed, enums.Cancelled, "Change of plans")
+	// Only the first booking was cancelled before the retention period
+	bookingRepo.DB.Model(&entities.BookingStatusHistoryEntity{}).Where("BookingID = ?", purgedID).Update("ChangedAt", getDate())
+
+	// Act
+	purged, err := bookingRepo.Purge(time.Now().AddDate(0, -1, 0))
+
+	var purgedBookings, retainedBookings, history int64
+	bookingRepo.DB.Unscoped().Model(&entities.BookingEntity{}).Where("ID = ?", purgedID).Count(&purgedBookings)
+	bookingRepo.DB.Unscoped().Model(&entities.BookingEntity{}).Where("ID = ?", retainedID).Count(&retainedBookings)
+	bookingRepo.DB.Model(&entities.BookingStatusHistoryEntity{}).Where("BookingID = ?", purgedID).Count(&history)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Equal(t, int64(0), purgedBookings)
+	assert.Equal(t, int64(1), retainedBookings)
+	assert.Equal(t, int64(0), history)
+}
+
+func TestBookingRepositoryPurgeWithFailingBookingDeleteKeepsPassengersAndSeats(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingID := testBookings[0].ID
+	bookingRepo.DeleteByBookingID(bookingID)
+	// The connection drops before the booking itself is deleted
+	bookingRepo.DB.Callback().Delete().Before("gorm:delete").Register("test:fail_booking_delete", func(tx *gorm.DB) {
+		if tx.Statement.Table == "Booking" {
+			tx.AddError(fmt.Errorf("connection lost"))
+		}
+	})
+
+	// Act
+	_, err := bookingRepo.Purge(time.Now().Add(time.Hour))
+
+	var storedBookings, passengers, seats int64
+	bookingRepo.DB.Unscoped().Model(&entities.BookingEntity{}).Where("ID = ?", bookingID).Count(&storedBookings)
+	bookingRepo.DB.Model(&entities.PassengerEntity{}).Where("BookingID = ?", bookingID).Count(&passengers)
+	bookingRepo.DB.Model(&entities.SeatEntity{}).Where("BookingID = ?", bookingID).Count(&seats)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), storedBookings)
+	assert.Equal(t, int64(len(testBookings[0].Passengers)), passengers)
+	assert.Equal(t, int64(len(testBookings[0].Seats)), seats)
+}
+
+func TestBookingRepositoryDeleteByInvalidIDReturnsNotFoundError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	invalidBookingID := 999
+
+	// Act
+	err := bookingRepo.DeleteByBookingID(invalidBookingID)
+	bookings, _ := bookingRepo.GetAll()
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotFoundError(invalidBookingID, 404), err)
 	assert.Len(t, bookings, len(testBookings))
-	assert.False(t, isDeleted)
 }
 
 func TestBookingRepositoryUpdateValidBookingReturnsUpdatedBooking(t *testing.T) {
 	// Arrange
 	bookingRepo := NewTestBookingRepository()
 	testBookings := getBookings(bookingRepo)
-	// Update all booking fields
+	// Update all booking fields, the owner is kept
 	updatedBooking := entities.BookingEntity{
-		ID:          7,
+		ID:          testBookings[0].ID,
 		UserID:      1,
 		FlightCode:  "FR787",
 		FlightClass: 0,
 		CreatedAt:   getDate(),
 		Passengers:  []entities.PassengerEntity{getPassengerEntities()[0]},
 		Seats:       []entities.SeatEntity{getSeatEntities()[0]},
-		Luggage:     getLuggageString(),
+		Version:     testBookings[0].Version,
+	}
+
+	// Act
+	booking, err := bookingRepo.Update(updatedBooking)
+	storedBooking, _ := bookingRepo.GetByID(testBookings[0].ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, storedBooking, booking)
+	assert.Equal(t, testBookings[0].UserID, booking.UserID)
+	assert.Equal(t, "FR787", booking.FlightCode)
+	assert.Equal(t, 0, booking.FlightClass)
+	assert.Len(t, booking.Passengers, 1)
+	assert.Equal(t, "John Doe", booking.Passengers[0].FullName)
+	assert.Len(t, booking.Seats, 1)
+	assert.Equal(t, "FR787", booking.Seats[0].FlightCode)
+	assert.Equal(t, testBookings[0].Version+1, booking.Version)
+}
+
+func TestBookingRepositoryUpdateWithOutdatedVersionReturnsVersionConflictError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	// A payment event changes the status after the client read the booking
+	bookingRepo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", testBookings[0].ID).Update("Status", "Pending")
+	statusErr := bookingRepo.UpdateStatus(testBookings[0].ID, enums.Pending, enums.AwaitingPayment, "Payment requested")
+	updatedBooking := testBookings[0]
+	updatedBooking.Passengers = updatedBooking.Passengers[:1]
+
+	// Act
+	booking, err := bookingRepo.Update(updatedBooking)
+	storedBooking, _ := bookingRepo.GetByID(testBookings[0].ID)
+
+	// Assert
+	assert.NoError(t, statusErr)
+	assert.Equal(t, errors.NewBookingVersionConflictError(testBookings[0].ID, 412), err)
+	assert.Nil(t, booking)
+	assert.Equal(t, string(enums.AwaitingPayment), storedBooking.Status)
+	assert.Len(t, storedBooking.Passengers, 2)
+}
+
+func TestBookingRepositoryUpdateReconcilesPassengers(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	updatedBooking := testBookings[0]
+	// Keep and rename the first passenger, remove the second and add a third
+	keptPassenger := updatedBooking.Passengers[0]
+	keptPassenger.FullName = "Johnny Doe"
+	keptPassenger.Luggage = `[{"type":"CabinBag","quantity":1}]`
+	removedPassengerID := updatedBooking.Passengers[1].ID
+	updatedBooking.Passengers = []entities.PassengerEntity{
+		keptPassenger,
+		{FullName: "Baby Doe", DateOfBirth: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), PassportNumber: "5678"},
+	}
+
+	// Act
+	booking, err := bookingRepo.Update(updatedBooking)
+
+	var removedPassengers int64
+	bookingRepo.DB.Model(&entities.PassengerEntity{}).Where("ID = ?", removedPassengerID).Count(&removedPassengers)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, booking.Passengers, 2)
+	assert.Equal(t, keptPassenger.ID, booking.Passengers[0].ID)
+	assert.Equal(t, "Johnny Doe", booking.Passengers[0].FullName)
+	assert.Equal(t, `[{"type":"CabinBag","quantity":1}]`, booking.Passengers[0].Luggage)
+	assert.Equal(t, "Baby Doe", booking.Passengers[1].FullName)
+	assert.NotZero(t, booking.Passengers[1].ID)
+	assert.Equal(t, int64(0), removedPassengers)
+}
+
+func TestBookingRepositoryUpdateWithPassengerOfOtherBookingAddsNewPassenger(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	updatedBooking := testBookings[0]
+	otherPassenger := testBookings[1].Passengers[0]
+	otherPassenger.FullName = "Stolen Passenger"
+	updatedBooking.Passengers = []entities.PassengerEntity{otherPassenger}
+
+	// Act
+	booking, err := bookingRepo.Update(updatedBooking)
+	otherBooking, _ := bookingRepo.GetByID(testBookings[1].ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, booking.Passengers, 1)
+	assert.NotEqual(t, otherPassenger.ID, booking.Passengers[0].ID)
+	assert.Equal(t, testBookings[1].Passengers, otherBooking.Passengers)
+}
+
+func TestBookingRepositoryUpdateReconcilesSeats(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	updatedBooking := testBookings[0]
+	keptSeatID := updatedBooking.Seats[0].ID
+	// Keep seat 1A, swap seat 1B for 2C
+	updatedBooking.Seats = []entities.SeatEntity{{Row: 1, Column: "A"}, {Row: 2, Column: "C"}}
+
+	// Act
+	booking, err := bookingRepo.Update(updatedBooking)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, booking.Seats, 2)
+	assert.Equal(t, keptSeatID, booking.Seats[0].ID)
+	assert.Equal(t, 2, booking.Seats[1].Row)
+	assert.Equal(t, "C", booking.Seats[1].Column)
+}
+
+func TestBookingRepositoryUpdateWithAlreadyBookedSeatRollsBackUpdate(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	// Move the second booking onto the flight of the first one, where 1A is already taken
+	updatedBooking := testBookings[1]
+	updatedBooking.FlightCode = testBookings[0].FlightCode
+	updatedBooking.Passengers = nil
+
+	// Act
+	booking, err := bookingRepo.Update(updatedBooking)
+	storedBooking, _ := bookingRepo.GetByID(testBookings[1].ID)
+
+	// Assert
+	assert.Equal(t, errors.NewSeatAlreadyBookedError(testBookings[0].FlightCode, []models.Seat{{Row: 1, Column: "A"}, {Row: 1, Column: "B"}}, 409), err)
+	assert.Nil(t, booking)
+	assert.Equal(t, testBookings[1], *storedBooking)
+}
+
+func TestBookingRepositoryUpdateKeepsStatus(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingRepo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", testBookings[0].ID).Update("Status", "Confirmed")
+	updatedBooking := testBookings[0]
+	updatedBooking.Status = string(enums.Cancelled)
+
+	// Act
+	booking, err := bookingRepo.Update(updatedBooking)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Confirmed", booking.Status)
+}
+
+func TestBookingRepositoryUpdateNonExistingBookingReturnsNotFoundError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	updatedBooking := testBookings[0]
+	updatedBooking.ID = 999
+
+	// Act
+	booking, err := bookingRepo.Update(updatedBooking)
+	bookings, _ := bookingRepo.GetAll()
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotFoundError(999, 404), err)
+	assert.Nil(t, booking)
+	assert.Len(t, bookings, len(testBookings))
+}
+
+func TestBookingRepositoryGetByValidIDReturnsBooking(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+
+	// Act
+	booking, err := bookingRepo.GetByID(testBookings[1].ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, testBookings[1], *booking)
+}
+
+func TestBookingRepositoryGetByInvalidIDReturnsNotFoundError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	getBookings(bookingRepo)
+
+	// Act
+	booking, err := bookingRepo.GetByID(999)
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotFoundError(999, 404), err)
+	assert.Nil(t, booking)
+}
+
+func TestBookingRepositoryExistsWithValidIDReturnsTrue(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+
+	// Act
+	exists, err := bookingRepo.Exists(testBookings[0].ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestBookingRepositoryExistsWithInvalidIDReturnsFalse(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	getBookings(bookingRepo)
+
+	// Act
+	exists, err := bookingRepo.Exists(999)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestBookingRepositoryGetByIDWithUnreachableDatabaseReturnsDatabaseUnavailableError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	// Every query of an expired context fails before it reaches the database
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	unreachableRepo := repositories.NewBookingRepository(&repositories.BaseRepository{DB: bookingRepo.DB.WithContext(ctx)})
+
+	// Act
+	booking, err := unreachableRepo.GetByID(testBookings[0].ID)
+
+	// Assert
+	assert.IsType(t, &errors.DatabaseUnavailableError{}, err)
+	assert.Nil(t, booking)
+}
+
+func TestBookingRepositoryUpdateStatusOfNonExistingBookingReturnsNotFoundError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	getBookings(bookingRepo)
+
+	// Act
+	err := bookingRepo.UpdateStatus(999, enums.Pending, enums.AwaitingPayment, "Payment requested")
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotFoundError(999, 404), err)
+}
+
+func TestBookingRepositoryUpdateStatusFromCurrentStatusRecordsHistory(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingID := testBookings[0].ID
+	bookingRepo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", bookingID).Update("Status", "Pending")
+
+	// Act
+	err := bookingRepo.UpdateStatus(bookingID, enums.Pending, enums.AwaitingPayment, "Payment requested")
+	booking, _ := bookingRepo.GetByID(bookingID)
+	history, _ := bookingRepo.GetStatusHistory(bookingID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, string(enums.AwaitingPayment), booking.Status)
+	assert.Equal(t, testBookings[0].Version+1, booking.Version)
+	assert.Len(t, history, 1)
+	assert.Equal(t, string(enums.Pending), history[0].FromStatus)
+	assert.Equal(t, string(enums.AwaitingPayment), history[0].ToStatus)
+	assert.Equal(t, "Payment requested", history[0].Reason)
+}
+
+func TestBookingRepositoryUpdateStatusFromStaleStatusReturnsError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingID := testBookings[0].ID
+	bookingRepo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", bookingID).Update("Status", "Cancelled")
+
+	// Act
+	err := bookingRepo.UpdateStatus(bookingID, enums.Pending, enums.AwaitingPayment, "Payment requested")
+	history, _ := bookingRepo.GetStatusHistory(bookingID)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidStatusTransitionError(bookingID, enums.Pending, enums.AwaitingPayment, 409), err)
+	assert.Empty(t, history)
+}
+
+func TestBookingRepositoryTransactionWithErrorRollsBackBookingAndOutboxMessage(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingRepo.DB.Exec("DELETE FROM OutboxMessage")
+	bookingEntity := entities.BookingEntity{
+		UserID:      1,
+		FlightCode:  "FR787",
+		FlightClass: 1,
+		CreatedAt:   getDate(),
+		Status:      string(enums.Pending),
+	}
+
+	// Act
+	err := bookingRepo.Transaction(func(repo interfaces.BookingRepository) error {
+		repo.Create(bookingEntity)
+		repo.AddOutboxMessage("booking.created", []byte(`{"booking_id":1}`))
+		return fmt.Errorf("publishing the payment request failed")
+	})
+	bookings, _ := bookingRepo.GetAll()
+
+	var outboxMessages int64
+	bookingRepo.DB.Model(&entities.OutboxMessageEntity{}).Count(&outboxMessages)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Len(t, bookings, len(testBookings))
+	assert.Equal(t, int64(0), outboxMessages)
+}
+
+func TestBookingRepositoryCreateBookingWithSeatsHeldByOtherUserReturnsError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingRepo.DB.Exec("DELETE FROM SeatHold")
+	bookingRepo.DB.Create(&entities.SeatHoldEntity{HoldID: "hold1", UserID: 2, FlightCode: "FR787", Row: 1, Column: "A", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)})
+	bookingEntity := entities.BookingEntity{
+		UserID:      1,
+		FlightCode:  "FR787",
+		FlightClass: 1,
+		CreatedAt:   getDate(),
+		Seats:       getSeatEntities(),
+	}
+
+	// Act
+	booking, err := bookingRepo.Create(bookingEntity)
+	bookings, _ := bookingRepo.GetAll()
+
+	// Assert
+	assert.Equal(t, errors.NewSeatHeldError("FR787", []models.Seat{{Row: 1, Column: "A"}}, 409), err)
+	assert.Nil(t, booking)
+	assert.Len(t, bookings, len(testBookings))
+}
+
+func TestBookingRepositoryCreateBookingWithOwnHeldSeatsReleasesHolds(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	getBookings(bookingRepo)
+	bookingRepo.DB.Exec("DELETE FROM SeatHold")
+	bookingRepo.DB.Create(&entities.SeatHoldEntity{HoldID: "hold1", UserID: 1, FlightCode: "FR787", Row: 1, Column: "A", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)})
+	bookingEntity := entities.BookingEntity{
+		UserID:      1,
+		FlightCode:  "FR787",
+		FlightClass: 1,
+		CreatedAt:   getDate(),
+		Seats:       getSeatEntities(),
+	}
+
+	// Act
+	_, err := bookingRepo.Create(bookingEntity)
+
+	var holds int64
+	bookingRepo.DB.Model(&entities.SeatHoldEntity{}).Count(&holds)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), holds)
+}
+
+func TestBookingRepositoryCreateBookingWithAlreadyBookedSeatsReturnsError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingEntity := entities.BookingEntity{
+		UserID:      1,
+		FlightCode:  testBookings[0].FlightCode,
+		FlightClass: 1,
+		CreatedAt:   getDate(),
+		Seats:       []entities.SeatEntity{{Row: 1, Column: "B"}, {Row: 2, Column: "A"}},
+	}
+
+	// Act
+	booking, err := bookingRepo.Create(bookingEntity)
+	bookings, _ := bookingRepo.GetAll()
+
+	// Assert
+	assert.Equal(t, errors.NewSeatAlreadyBookedError(bookingEntity.FlightCode, []models.Seat{{Row: 1, Column: "B"}}, 409), err)
+	assert.Nil(t, booking)
+	assert.Len(t, bookings, len(testBookings))
+}
+
+func TestBookingRepositoryInsertDuplicateSeatIsRejectedByDatabase(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	duplicateSeat := entities.SeatEntity{BookingID: testBookings[1].ID, FlightCode: testBookings[0].FlightCode, Row: 1, Column: "A"}
+
+	// Act
+	err := bookingRepo.DB.Create(&duplicateSeat).Error
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestBookingRepositoryCancelledBookingReleasesSeats(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingRepo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", testBookings[0].ID).Update("Status", "Confirmed")
+	bookingEntity := entities.BookingEntity{
+		UserID:      1,
+		FlightCode:  testBookings[0].FlightCode,
+		FlightClass: 1,
+		CreatedAt:   getDate(),
+		Seats:       getSeatEntities(),
+	}
+
+	// Act
+	statusErr := bookingRepo.UpdateStatus(testBookings[0].ID, enums.Confirmed, enums.Cancelled, "Cancelled by user")
+	booking, err := bookingRepo.Create(bookingEntity)
+
+	// Assert
+	assert.NoError(t, statusErr)
+	assert.NoError(t, err)
+	assert.NotNil(t, booking)
+}
+
+func TestBookingRepositoryGetUnpaidIDsReturnsUnpaidBookingsCreatedBeforeDate(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getBookings(bookingRepo)
+	bookingRepo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", testBookings[0].ID).Update("Status", "PaymentFailed")
+	bookingRepo.DB.Model(&entities.BookingEntity{}).Where("ID = ?", testBookings[1].ID).Update("Status", "Confirmed")
+
+	// Act
+	unpaidIDs, err := bookingRepo.GetUnpaidIDs(getDate().Add(time.Minute))
+	laterIDs, laterErr := bookingRepo.GetUnpaidIDs(getDate().Add(-time.Minute))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, laterErr)
+	assert.Equal(t, []int{testBookings[0].ID}, unpaidIDs)
+	assert.Empty(t, laterIDs)
+}
+
+// Bookings of the search tests, created an hour apart
+func getSearchBookings(repo *repositories.BookingRepository) []entities.BookingEntity {
+	repo.DB.Exec("DELETE FROM BookingStatusHistory")
+	repo.DB.Exec("DELETE FROM Seat")
+	repo.DB.Exec("DELETE FROM Passenger")
+	repo.DB.Exec("DELETE FROM Booking")
+
+	testBookings := []entities.BookingEntity{
+		{UserID: 2, FlightCode: "FR788", Status: "Success", Passengers: []entities.PassengerEntity{{FullName: "John Doe", PassportNumber: "1234"}}},
+		{UserID: 4, FlightCode: "FR788", Status: string(enums.Cancelled), Passengers: []entities.PassengerEntity{{FullName: "Jane Smith", PassportNumber: "4321"}}},
+		{UserID: 2, FlightCode: "FR789", Status: string(enums.Confirmed), Passengers: []entities.PassengerEntity{{FullName: "Johnny Walker", PassportNumber: "5678"}}},
+		{UserID: 4, FlightCode: "FR788", Status: string(enums.Pending), Passengers: []entities.PassengerEntity{{FullName: "Mary Major", PassportNumber: "8765"}}},
+	}
+	for i := range testBookings {
+		testBookings[i].CreatedAt = getDate().Add(time.Duration(i) * time.Hour)
+		if err := repo.DB.Create(&testBookings[i]).Error; err != nil {
+			log.Fatalf("Failed to create booking: %v", err)
+		}
 	}
+	return testBookings
+}
+
+func getSearchCriteria() models.BookingSearchCriteria {
+	return models.BookingSearchCriteria{SortBy: "created_at", SortOrder: "asc", Limit: 20}
+}
+
+func getBookingIDs(bookings []entities.BookingEntity) []int {
+	ids := []int{}
+	for _, booking := range bookings {
+		ids = append(ids, booking.ID)
+	}
+	return ids
+}
+
+func TestBookingRepositorySearchFiltersByFlightCodeAndStatus(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getSearchBookings(bookingRepo)
+	criteria := getSearchCriteria()
+	criteria.FlightCode = "FR788"
+	criteria.Status = enums.Confirmed
+
+	// Act
+	bookings, totalCount, nextCursor, err := bookingRepo.Search(criteria)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), totalCount)
+	assert.Equal(t, []int{testBookings[0].ID}, getBookingIDs(bookings))
+	assert.Empty(t, nextCursor)
+}
+
+func TestBookingRepositorySearchFiltersByPassengerNameAndPassport(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getSearchBookings(bookingRepo)
+	byName := getSearchCriteria()
+	byName.PassengerName = "JOHN"
+	byPassport := getSearchCriteria()
+	byPassport.PassportNumber = "4321"
+
+	// Act
+	nameBookings, _, _, nameErr := bookingRepo.Search(byName)
+	passportBookings, _, _, passportErr := bookingRepo.Search(byPassport)
+
+	// Assert
+	assert.NoError(t, nameErr)
+	assert.NoError(t, passportErr)
+	assert.Equal(t, []int{testBookings[0].ID, testBookings[2].ID}, getBookingIDs(nameBookings))
+	assert.Equal(t, []int{testBookings[1].ID}, getBookingIDs(passportBookings))
+	assert.Len(t, nameBookings[0].Passengers, 1)
+}
+
+func TestBookingRepositorySearchFiltersByUserAndCreatedAtRange(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getSearchBookings(bookingRepo)
+	createdFrom := getDate().Add(time.Hour)
+	createdTo := getDate().Add(3 * time.Hour)
+	criteria := getSearchCriteria()
+	criteria.UserID = 4
+	criteria.CreatedFrom = &createdFrom
+	criteria.CreatedTo = &createdTo
+
+	// Act
+	bookings, totalCount, _, err := bookingRepo.Search(criteria)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), totalCount)
+	assert.Equal(t, []int{testBookings[1].ID}, getBookingIDs(bookings))
+}
+
+func TestBookingRepositorySearchPagesThroughAllBookingsWithCursor(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getSearchBookings(bookingRepo)
+	criteria := getSearchCriteria()
+	criteria.SortOrder = "desc"
+	criteria.Limit = 3
+
+	// Act
+	firstPage, firstTotal, cursor, firstErr := bookingRepo.Search(criteria)
+	criteria.Cursor = cursor
+	secondPage, secondTotal, lastCursor, secondErr := bookingRepo.Search(criteria)
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.Equal(t, int64(4), firstTotal)
+	assert.Equal(t, int64(4), secondTotal)
+	assert.Equal(t, []int{testBookings[3].ID, testBookings[2].ID, testBookings[1].ID}, getBookingIDs(firstPage))
+	assert.Equal(t, []int{testBookings[0].ID}, getBookingIDs(secondPage))
+	assert.NotEmpty(t, cursor)
+	assert.Empty(t, lastCursor)
+}
+
+func TestBookingRepositorySearchSortsByFlightCodeWithIDAsTieBreaker(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	testBookings := getSearchBookings(bookingRepo)
+	criteria := getSearchCriteria()
+	criteria.SortBy = "flight_code"
+	criteria.Limit = 2
+
+	// Act
+	firstPage, _, cursor, _ := bookingRepo.Search(criteria)
+	criteria.Cursor = cursor
+	secondPage, _, _, err := bookingRepo.Search(criteria)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []int{testBookings[0].ID, testBookings[1].ID}, getBookingIDs(firstPage))
+	assert.Equal(t, []int{testBookings[3].ID, testBookings[2].ID}, getBookingIDs(secondPage))
+}
+
+func TestBookingRepositorySearchWithCursorOfOtherSortingReturnsError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	getSearchBookings(bookingRepo)
+	criteria := getSearchCriteria()
+	criteria.Limit = 1
+	_, _, cursor, _ := bookingRepo.Search(criteria)
+	criteria.SortBy = "flight_code"
+	criteria.Cursor = cursor
+
+	// Act
+	bookings, _, _, err := bookingRepo.Search(criteria)
+
+	// Assert
+	assert.Nil(t, bookings)
+	assert.IsType(t, &errors.InvalidSearchCriteriaError{}, err)
+}
+
+func TestBookingRepositorySearchWithUnknownSortReturnsError(t *testing.T) {
+	// Arrange
+	bookingRepo := NewTestBookingRepository()
+	criteria := getSearchCriteria()
+	criteria.SortBy = "Luggage"
 
 	// Act
-	booking := bookingRepo.Update(updatedBooking)
+	_, _, _, err := bookingRepo.Search(criteria)
 
 	// Assert
-	assert.Equal(t, updatedBooking, booking)
-	assert.NotNil(t, testBookings)
+	assert.IsType(t, &errors.InvalidSearchCriteriaError{}, err)
 }
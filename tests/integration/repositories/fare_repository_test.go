@@ -0,0 +1,62 @@
+package repositories_test
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/repositories"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTestFareRepository() *repositories.FareRepository {
+	baseRepo := &TestBookingRepository{}
+	_, err := baseRepo.CreateConnection()
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+	return repositories.NewFareRepository(&baseRepo.BaseRepository)
+}
+
+func getFlightFares(repo *repositories.FareRepository) []entities.FlightFareEntity {
+	fares := []entities.FlightFareEntity{
+		{FlightCode: "FR788", FlightClass: 0, Currency: "EUR", BaseFareCents: 8999, SeatSurcharges: `{"ExtraLegroom":2500}`, LuggagePrices: `{"Cargo20kg":3500}`},
+		{FlightCode: "FR788", FlightClass: 1, Currency: "EUR", BaseFareCents: 34999, SeatSurcharges: `{}`, LuggagePrices: `{}`},
+	}
+
+	// Clear any existing data
+	repo.DB.Exec("DELETE FROM FlightFare")
+
+	if err := repo.DB.Create(&fares).Error; err != nil {
+		log.Fatalf("Failed to create flight fares: %v", err)
+	}
+	return fares
+}
+
+func TestFareRepositoryGetByFlightReturnsFareOfFlightClass(t *testing.T) {
+	// Arrange
+	fareRepo := NewTestFareRepository()
+	fares := getFlightFares(fareRepo)
+
+	// Act
+	fare, err := fareRepo.GetByFlight("FR788", enums.Business)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, fares[1], *fare)
+}
+
+func TestFareRepositoryGetByFlightWithoutFareReturnsFareNotFoundError(t *testing.T) {
+	// Arrange
+	fareRepo := NewTestFareRepository()
+	getFlightFares(fareRepo)
+
+	// Act
+	fare, err := fareRepo.GetByFlight("FR999", enums.Economy)
+
+	// Assert
+	assert.Nil(t, fare)
+	assert.Equal(t, errors.NewFareNotFoundError("FR999", enums.Economy, 404), err)
+}
@@ -0,0 +1,90 @@
+package repositories_test
+
+import (
+	"flyhorizons-bookingservice/repositories"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTestIdempotencyRepository() *repositories.IdempotencyRepository {
+	baseRepo := &TestBookingRepository{}
+	_, err := baseRepo.CreateConnection()
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Clear any existing data
+	baseRepo.DB.Exec("DELETE FROM IdempotencyKey")
+
+	return repositories.NewIdempotencyRepository(&baseRepo.BaseRepository)
+}
+
+func getIdempotencyKey(expiresAt time.Time) entities.IdempotencyKeyEntity {
+	return entities.IdempotencyKeyEntity{Key: "key-1", RequestHash: "hash", CreatedAt: time.Now(), ExpiresAt: expiresAt}
+}
+
+func TestIdempotencyRepositoryReserveNewKeyReturnsNil(t *testing.T) {
+	// Arrange
+	idempotencyRepo := NewTestIdempotencyRepository()
+
+	// Act
+	existing, err := idempotencyRepo.Reserve(getIdempotencyKey(time.Now().Add(time.Hour)))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestIdempotencyRepositoryReserveUsedKeyReturnsStoredResponse(t *testing.T) {
+	// Arrange
+	idempotencyRepo := NewTestIdempotencyRepository()
+	idempotencyRepo.Reserve(getIdempotencyKey(time.Now().Add(time.Hour)))
+	idempotencyRepo.Complete("key-1", 201, `{"id":1}`)
+
+	// Act
+	existing, err := idempotencyRepo.Reserve(getIdempotencyKey(time.Now().Add(time.Hour)))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, `{"id":1}`, existing.ResponseBody)
+}
+
+func TestIdempotencyRepositoryReleaseAbandonedReleasesOnlyOldInFlightKeys(t *testing.T) {
+	// Arrange
+	idempotencyRepo := NewTestIdempotencyRepository()
+	abandonedKey := getIdempotencyKey(time.Now().Add(time.Hour))
+	abandonedKey.CreatedAt = time.Now().Add(-2 * time.Minute)
+	idempotencyRepo.Reserve(abandonedKey)
+	completedKey := getIdempotencyKey(time.Now().Add(time.Hour))
+	completedKey.Key = "key-2"
+	completedKey.CreatedAt = time.Now().Add(-2 * time.Minute)
+	idempotencyRepo.Reserve(completedKey)
+	idempotencyRepo.Complete("key-2", 201, `{"id":1}`)
+
+	// Act
+	abandonedReleased, err := idempotencyRepo.ReleaseAbandoned("key-1", time.Now().Add(-time.Minute))
+	completedReleased, _ := idempotencyRepo.ReleaseAbandoned("key-2", time.Now().Add(-time.Minute))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, abandonedReleased)
+	assert.False(t, completedReleased)
+}
+
+func TestIdempotencyRepositoryReserveExpiredKeyReturnsNil(t *testing.T) {
+	// Arrange
+	idempotencyRepo := NewTestIdempotencyRepository()
+	idempotencyRepo.Reserve(getIdempotencyKey(time.Now().Add(-time.Minute)))
+
+	// Act
+	existing, err := idempotencyRepo.Reserve(getIdempotencyKey(time.Now().Add(time.Hour)))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+}
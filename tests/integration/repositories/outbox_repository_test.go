@@ -0,0 +1,87 @@
+package repositories_test
+
+import (
+	"flyhorizons-bookingservice/repositories"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTestOutboxRepository() *repositories.OutboxRepository {
+	baseRepo := &TestBookingRepository{}
+	_, err := baseRepo.CreateConnection()
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+	return repositories.NewOutboxRepository(&baseRepo.BaseRepository)
+}
+
+func getOutboxMessages(repo *repositories.OutboxRepository) []entities.OutboxMessageEntity {
+	dispatchedAt := getDate()
+	testMessages := []entities.OutboxMessageEntity{
+		{Queue: "booking.created", Payload: `{"booking_id":1}`, CreatedAt: getDate(), NextAttemptAt: getDate()},
+		{Queue: "booking.confirmed", Payload: `{"id":1}`, CreatedAt: getDate(), NextAttemptAt: getDate(), DispatchedAt: &dispatchedAt},
+		{Queue: "booking.created", Payload: `{"booking_id":2}`, CreatedAt: getDate(), NextAttemptAt: time.Now().Add(time.Hour)},
+	}
+
+	// Clear any existing data
+	repo.DB.Exec("DELETE FROM OutboxMessage")
+
+	for i := range testMessages {
+		if err := repo.DB.Create(&testMessages[i]).Error; err != nil {
+			log.Fatalf("Failed to create outbox message: %v", err)
+		}
+	}
+
+	return testMessages
+}
+
+func TestOutboxRepositoryGetPendingReturnsOnlyDueUndispatchedMessages(t *testing.T) {
+	// Arrange
+	outboxRepo := NewTestOutboxRepository()
+	testMessages := getOutboxMessages(outboxRepo)
+
+	// Act
+	messages, err := outboxRepo.GetPending(10)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, testMessages[0].ID, messages[0].ID)
+}
+
+func TestOutboxRepositoryMarkDispatchedRemovesMessageFromPending(t *testing.T) {
+	// Arrange
+	outboxRepo := NewTestOutboxRepository()
+	testMessages := getOutboxMessages(outboxRepo)
+
+	// Act
+	err := outboxRepo.MarkDispatched(testMessages[0].ID)
+	messages, _ := outboxRepo.GetPending(10)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestOutboxRepositoryMarkFailedRecordsAttemptAndDelaysRetry(t *testing.T) {
+	// Arrange
+	outboxRepo := NewTestOutboxRepository()
+	testMessages := getOutboxMessages(outboxRepo)
+
+	// Act
+	err := outboxRepo.MarkFailed(testMessages[0].ID, "channel closed", time.Now().Add(time.Minute))
+	messages, _ := outboxRepo.GetPending(10)
+
+	var message entities.OutboxMessageEntity
+	outboxRepo.DB.First(&message, testMessages[0].ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+	assert.Equal(t, 1, message.Attempts)
+	assert.Equal(t, "channel closed", message.LastError)
+}
@@ -0,0 +1,126 @@
+package repositories_test
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/repositories"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTestSeatHoldRepository() *repositories.SeatHoldRepository {
+	baseRepo := &TestBookingRepository{}
+	_, err := baseRepo.CreateConnection()
+	if err != nil {
+		log.Fatalf("Failed to initialize test database: %v", err)
+	}
+
+	// Clear any existing data
+	baseRepo.DB.Exec("DELETE FROM SeatHold")
+
+	return repositories.NewSeatHoldRepository(&baseRepo.BaseRepository)
+}
+
+func getSeatHoldEntities(holdID string, userID int, expiresAt time.Time) []entities.SeatHoldEntity {
+	return []entities.SeatHoldEntity{
+		{HoldID: holdID, UserID: userID, FlightCode: "FR788", Row: 3, Column: "A", CreatedAt: time.Now(), ExpiresAt: expiresAt},
+		{HoldID: holdID, UserID: userID, FlightCode: "FR788", Row: 3, Column: "B", CreatedAt: time.Now(), ExpiresAt: expiresAt},
+	}
+}
+
+func TestSeatHoldRepositoryCreateAvailableSeatsHoldsSeats(t *testing.T) {
+	// Arrange
+	seatHoldRepo := NewTestSeatHoldRepository()
+	holds := getSeatHoldEntities("hold1", 1, time.Now().Add(time.Minute))
+
+	// Act
+	err := seatHoldRepo.Create(holds)
+	activeHolds, _ := seatHoldRepo.GetActiveByHoldID("hold1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, activeHolds, 2)
+}
+
+func TestSeatHoldRepositoryCreateSeatsHeldByOtherUserReturnsError(t *testing.T) {
+	// Arrange
+	seatHoldRepo := NewTestSeatHoldRepository()
+	seatHoldRepo.Create(getSeatHoldEntities("hold1", 1, time.Now().Add(time.Minute)))
+
+	// Act
+	err := seatHoldRepo.Create(getSeatHoldEntities("hold2", 2, time.Now().Add(time.Minute)))
+	activeHolds, _ := seatHoldRepo.GetActiveByHoldID("hold2")
+
+	// Assert
+	expectedSeats := []models.Seat{{Row: 3, Column: "A"}, {Row: 3, Column: "B"}}
+	assert.Equal(t, errors.NewSeatHeldError("FR788", expectedSeats, 409), err)
+	assert.Empty(t, activeHolds)
+}
+
+func TestSeatHoldRepositoryCreateSeatsWithExpiredHoldHoldsSeats(t *testing.T) {
+	// Arrange
+	seatHoldRepo := NewTestSeatHoldRepository()
+	seatHoldRepo.DB.Create(getSeatHoldEntities("hold1", 1, time.Now().Add(-time.Minute)))
+
+	// Act
+	err := seatHoldRepo.Create(getSeatHoldEntities("hold2", 2, time.Now().Add(time.Minute)))
+	activeHolds, _ := seatHoldRepo.GetActiveByHoldID("hold2")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, activeHolds, 2)
+}
+
+func TestSeatHoldRepositoryGetActiveByUserReturnsOnlyActiveHoldsOfUserOnFlight(t *testing.T) {
+	// Arrange
+	seatHoldRepo := NewTestSeatHoldRepository()
+	seatHoldRepo.Create(getSeatHoldEntities("hold1", 1, time.Now().Add(time.Minute)))
+	seatHoldRepo.DB.Create(&entities.SeatHoldEntity{HoldID: "hold2", UserID: 1, FlightCode: "FR788", Row: 4, Column: "A", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(-time.Minute)})
+	seatHoldRepo.DB.Create(&entities.SeatHoldEntity{HoldID: "hold3", UserID: 1, FlightCode: "FR789", Row: 3, Column: "A", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)})
+	seatHoldRepo.DB.Create(&entities.SeatHoldEntity{HoldID: "hold4", UserID: 2, FlightCode: "FR788", Row: 5, Column: "A", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)})
+
+	// Act
+	activeHolds, err := seatHoldRepo.GetActiveByUser(1, "FR788")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, activeHolds, 2)
+	for _, hold := range activeHolds {
+		assert.Equal(t, "hold1", hold.HoldID)
+	}
+}
+
+func TestSeatHoldRepositoryExtendExpiredHoldReturnsError(t *testing.T) {
+	// Arrange
+	seatHoldRepo := NewTestSeatHoldRepository()
+	seatHoldRepo.DB.Create(getSeatHoldEntities("hold1", 1, time.Now().Add(-time.Minute)))
+
+	// Act
+	err := seatHoldRepo.Extend("hold1", time.Now().Add(time.Minute))
+
+	// Assert
+	assert.Equal(t, errors.NewSeatHoldNotFoundError("hold1", 404), err)
+}
+
+func TestSeatHoldRepositoryDeleteExpiredRemovesOnlyExpiredHolds(t *testing.T) {
+	// Arrange
+	seatHoldRepo := NewTestSeatHoldRepository()
+	seatHoldRepo.DB.Create(getSeatHoldEntities("hold1", 1, time.Now().Add(-time.Minute)))
+	activeHold := []entities.SeatHoldEntity{
+		{HoldID: "hold2", UserID: 2, FlightCode: "FR789", Row: 3, Column: "A", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)},
+	}
+	seatHoldRepo.DB.Create(activeHold)
+
+	// Act
+	deleted, err := seatHoldRepo.DeleteExpired()
+	activeHolds, _ := seatHoldRepo.GetActiveByHoldID("hold2")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.Len(t, activeHolds, 1)
+}
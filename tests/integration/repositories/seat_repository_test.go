@@ -1,120 +1,90 @@
 package repositories_test
 
 import (
+	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/repositories"
 	entities "flyhorizons-bookingservice/repositories/entity"
 	"log"
 	"testing"
+	"time"
 
-	"github.com/glebarez/sqlite"
-	"gorm.io/gorm"
+	"github.com/stretchr/testify/assert"
 )
 
-// Create a test version of TestSeatRepository that uses an in-memory SQLite database
-type TestSeatRepository struct {
-	repositories.BaseRepository
-}
-
-func (repo *TestSeatRepository) CreateConnection() (*gorm.DB, error) {
-	if repo.DB != nil {
-		return repo.DB, nil
-	}
-
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	if err != nil {
-		log.Printf("Failed to create SQLite database: %v", err)
-		return nil, err
-	}
-
-	// Auto migrate entities for the test database
-	if err := db.AutoMigrate(&entities.SeatOptionEntity{}, &entities.SeatEntity{}); err != nil {
-		log.Printf("Failed to auto-migrate schema: %v", err)
-		return nil, err
-	}
-
-	repo.DB = db
-	return db, nil
-}
-
 func NewTestSeatRepository() *repositories.SeatRepository {
 	baseRepo := &TestBookingRepository{}
-	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{}) // No shared cache
+	_, err := baseRepo.CreateConnection()
 	if err != nil {
 		log.Fatalf("Failed to initialize test database: %v", err)
 	}
-
-	// Auto-migrate tables for the test database
-	if err := db.AutoMigrate(&entities.SeatOptionEntity{}); err != nil {
-		log.Fatalf("Failed to migrate test database: %v", err)
-	}
-
-	baseRepo.DB = db
 	return repositories.NewSeatRepository(&baseRepo.BaseRepository)
 }
 
-func getSeatOptionEntities(repo *repositories.SeatRepository) []entities.SeatOptionEntity {
-	// seats := []entities.SeatEntity{
-	// 	{
-	// 		ID:        1,
-	// 		BookingID: getBookings(repositories.NewBookingRepository())[0].ID,
-	// 		Booking:   getBookings(repositories.NewBookingRepository())[0],
-	// 		Row:       1,
-	// 		Column:    "A",
-	// 	},
-	// 	{
-	// 		ID:        2,
-	// 		BookingID: getBookings(repositories.NewBookingRepository())[1].ID,
-	// 		Booking:   getBookings(repositories.NewBookingRepository())[1],
-	// 		Row:       1,
-	// 		Column:    "B",
-	// 	},
-	// }
-
-	seatOptionEntities := []entities.SeatOptionEntity{
-		{
-			ID:     1,
-			Row:    1,
-			Column: "A",
-			Status: true,
-		},
-		{
-			ID:     1,
-			Row:    1,
-			Column: "B",
-			Status: false,
+func getAircraftConfiguration(repo *repositories.SeatRepository) entities.AircraftConfigurationEntity {
+	configuration := entities.AircraftConfigurationEntity{
+		Name: "A320 180Y",
+		Cabins: []entities.AircraftCabinEntity{
+			{FlightClass: 0, FirstRow: 1, LastRow: 30, Columns: "ABC-DEF"},
 		},
+		ExitRows:     "[12,13]",
+		BlockedSeats: `["13C"]`,
 	}
 
 	// Clear any existing data
-	repo.DB.Exec("DELETE FROM Seat")
+	repo.DB.Exec("DELETE FROM FlightSeatConfiguration")
+	repo.DB.Exec("DELETE FROM AircraftCabin")
+	repo.DB.Exec("DELETE FROM AircraftConfiguration")
 
-	// Save bookings with associations
-	// Create Seats in the Seat Table
-	// Create SeatOptions in the SeatOptions Table
+	if err := repo.DB.Create(&configuration).Error; err != nil {
+		log.Fatalf("Failed to create aircraft configuration: %v", err)
+	}
+	if err := repo.DB.Create(&entities.FlightSeatConfigurationEntity{FlightCode: "FR788", ConfigurationID: configuration.ID}).Error; err != nil {
+		log.Fatalf("Failed to create flight seat configuration: %v", err)
+	}
 
-	return seatOptionEntities
+	return configuration
 }
 
-func TestSeatRepositoryGetByValidFlightCodeReturnsSeats(t *testing.T) {
+func TestSeatRepositoryGetConfigurationByValidFlightCodeReturnsConfiguration(t *testing.T) {
 	// Arrange
-	// seatRepo := NewTestSeatRepository()
-	// flightSeats := getSeatEntities()
-	// flightCode := 1
+	seatRepo := NewTestSeatRepository()
+	expectedConfiguration := getAircraftConfiguration(seatRepo)
 
-	// // Act
-	// seats, err := seatRepo.GetByFlightID(flightCode)
+	// Act
+	configuration, err := seatRepo.GetConfigurationByFlightCode("FR788")
 
-	// // Assert
-	// assert.NoError(t, err)
-	// assert.Equal(t, flightSeats, seats)
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedConfiguration, *configuration)
+}
+
+func TestSeatRepositoryGetConfigurationByInvalidFlightCodeReturnsNil(t *testing.T) {
+	// Arrange
+	seatRepo := NewTestSeatRepository()
+	getAircraftConfiguration(seatRepo)
+
+	// Act
+	configuration, err := seatRepo.GetConfigurationByFlightCode("FR999")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, configuration)
 }
 
-// Returns all seats
-// But if none have been booked yet, all of them are available, per say for a whole new flight
-func TestSeatRepositoryGetByInvalidFlightCodeReturnsNoSeats(t *testing.T) {
+// Only the bookings and holds of the requested flight occupy its seats
+func TestSeatRepositoryGetOccupiedSeatsReturnsBookedAndHeldSeatsOfFlight(t *testing.T) {
 	// Arrange
+	seatRepo := NewTestSeatRepository()
+	bookingRepo := NewTestBookingRepository()
+	getBookings(bookingRepo)
+	seatRepo.DB.Exec("DELETE FROM SeatHold")
+	seatRepo.DB.Create(&entities.SeatHoldEntity{HoldID: "hold1", UserID: 1, FlightCode: "FR788", Row: 5, Column: "F", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)})
+	seatRepo.DB.Create(&entities.SeatHoldEntity{HoldID: "hold2", UserID: 1, FlightCode: "FR789", Row: 6, Column: "F", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)})
 
 	// Act
+	seats, err := seatRepo.GetOccupiedSeats("FR788")
 
 	// Assert
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []models.Seat{{Row: 1, Column: "A"}, {Row: 1, Column: "B"}, {Row: 5, Column: "F"}}, seats)
 }
@@ -0,0 +1,187 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/routes"
+	"flyhorizons-bookingservice/services/errors"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestAdminBookingRouter struct {
+}
+
+// Setup
+func setupAdminBookingRouter(mockService *mock_repositories.MockBookingService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+	routes.RegisterAdminBookingRoutes(router, mockService, gatewayAuthMiddleware)
+	return router
+}
+
+// Router Integration Tests
+func TestSearchBookingsAsAdminReturnsSearchResultJSON(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockBookingService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	createdFrom := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	expectedCriteria := models.BookingSearchCriteria{
+		FlightCode:    "FR788",
+		Status:        enums.Confirmed,
+		UserID:        4,
+		PassengerName: "doe",
+		CreatedFrom:   &createdFrom,
+		SortBy:        "flight_code",
+		SortOrder:     "asc",
+		Limit:         10,
+		Cursor:        "abc",
+	}
+	mockResult := &models.BookingSearchResult{Bookings: getBookings(), TotalCount: 12, NextCursor: "def"}
+	mockService.On("Search", expectedCriteria).Return(mockResult, nil)
+
+	router := setupAdminBookingRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/admin/bookings?flight_code=FR788&status=Confirmed&user_id=4&passenger_name=doe&created_from=2025-04-01T00:00:00Z&sort=flight_code&order=asc&limit=10&cursor=abc", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var result models.BookingSearchResult
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, *mockResult, result)
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchBookingsWithInvalidCriteriaReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockBookingService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("support-agent", 1)
+	mockService.On("Search", models.BookingSearchCriteria{SortBy: "Luggage"}).Return(nil, errors.NewInvalidSearchCriteriaError("sort", "Luggage", 400))
+
+	router := setupAdminBookingRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/admin/bookings?sort=Luggage", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchBookingsWithInvalidDateReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockBookingService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+
+	router := setupAdminBookingRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/admin/bookings?created_to=yesterday", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Search")
+}
+
+func TestSearchBookingsAsCustomerReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockBookingService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("customer", 4)
+
+	router := setupAdminBookingRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/admin/bookings", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func TestPurgeBookingsAsAdminReturnsPurgedCount(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockBookingService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	deletedBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("Purge", deletedBefore).Return(int64(3), nil)
+
+	router := setupAdminBookingRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/bookings/purge", bytes.NewBufferString(`{"deleted_before": "2025-01-01T00:00:00Z"}`))
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.JSONEq(t, `{"purged": 3}`, responseRecorder.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestPurgeBookingsWithoutDateReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockBookingService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+
+	router := setupAdminBookingRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/bookings/purge", bytes.NewBufferString(`{"deleted_before": "last year"}`))
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Purge")
+}
+
+func TestPurgeBookingsAsSupportAgentReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockBookingService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("support-agent", 1)
+
+	router := setupAdminBookingRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/bookings/purge", bytes.NewBufferString(`{"deleted_before": "2025-01-01T00:00:00Z"}`))
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Purge")
+}
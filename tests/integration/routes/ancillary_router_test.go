@@ -0,0 +1,86 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/routes"
+	"flyhorizons-bookingservice/services/errors"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup
+func setupAncillaryRouter(mockService *mock_repositories.MockAncillaryService) *gin.Engine {
+	router := gin.Default()
+	routes.RegisterAncillaryRoutes(router, mockService)
+	return router
+}
+
+func getAvailableAncillaries() []models.Ancillary {
+	return []models.Ancillary{
+		{Code: "LoungeAccess", Category: enums.Lounge, Name: "Lounge access", Currency: "EUR", PriceCents: 0},
+		{Code: "PriorityBoarding", Category: enums.PriorityBoarding, Name: "Priority boarding", Currency: "EUR", PriceCents: 900},
+	}
+}
+
+func TestGetAncillariesOfFlightClassReturnsAncillariesJSON(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockAncillaryService)
+	mockService.On("GetAvailable", enums.Business).Return(getAvailableAncillaries(), nil)
+
+	router := setupAncillaryRouter(mockService)
+
+	httpRequest, _ := http.NewRequest("GET", "/bookings/ancillaries?flight_class=1", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var ancillaries []models.Ancillary
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &ancillaries)
+	assert.NoError(t, err)
+	assert.Equal(t, getAvailableAncillaries(), ancillaries)
+}
+
+func TestGetAncillariesWithUnknownFlightClassReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockAncillaryService)
+	router := setupAncillaryRouter(mockService)
+
+	httpRequest, _ := http.NewRequest("GET", "/bookings/ancillaries?flight_class=first", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "GetAvailable", mock.Anything)
+}
+
+func TestGetAncillariesWithUnavailableDatabaseReturnsServiceUnavailable(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockAncillaryService)
+	mockService.On("GetAvailable", enums.Economy).Return(nil, errors.NewDatabaseUnavailableError(fmt.Errorf("connection refused"), 503))
+	router := setupAncillaryRouter(mockService)
+
+	httpRequest, _ := http.NewRequest("GET", "/bookings/ancillaries", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+}
@@ -0,0 +1,127 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/routes"
+	"flyhorizons-bookingservice/services/errors"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestDeadLetterRoute struct {
+}
+
+// Setup
+func setupDeadLetterRouter(mockService *mock_repositories.MockDeadLetterService, gatewayAuthMiddleware *mock_repositories.MockGatewayAuthMiddleware) *gin.Engine {
+	router := gin.Default()
+
+	routes.RegisterDeadLetterRoutes(router, mockService, gatewayAuthMiddleware)
+
+	return router
+}
+
+func getDeadLetterMessages() []models.DeadLetterMessage {
+	deadLetteredAt := time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC)
+	return []models.DeadLetterMessage{
+		{
+			Queue:          "payment.success",
+			Body:           "42",
+			Attempts:       5,
+			LastError:      "database unavailable",
+			DeadLetteredAt: &deadLetteredAt,
+		},
+	}
+}
+
+// Router Integration Tests
+func TestListDeadLettersAsAdminReturnsMessagesJSON(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockDeadLetterService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockMessages := getDeadLetterMessages()
+	mockService.On("List", "payment.success", 10).Return(mockMessages, nil)
+
+	router := setupDeadLetterRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/admin/dead-letters/payment.success?limit=10", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+
+	var messages []models.DeadLetterMessage
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &messages)
+	assert.NoError(t, err)
+	assert.Equal(t, mockMessages, messages)
+	mockService.AssertExpectations(t)
+}
+
+func TestListDeadLettersOfUnknownQueueReturnsHTTPStatusNotFound(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockDeadLetterService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockService.On("List", "booking.created", 50).Return(nil, errors.NewUnknownQueueError("booking.created", 404))
+
+	router := setupDeadLetterRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("GET", "/admin/dead-letters/booking.created", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func TestReplayDeadLettersAsAdminReturnsReplayedCount(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockDeadLetterService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("admin", 1)
+	mockService.On("Replay", "payment.failed", 50).Return(3, nil)
+
+	router := setupDeadLetterRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/dead-letters/payment.failed/replay", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.JSONEq(t, `{"replayed":3}`, responseRecorder.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestReplayDeadLettersAsUserReturnsAccessDenied(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockDeadLetterService)
+	mockAPIGatewayMiddleware := mock_repositories.NewMockGatewayAuthMiddleware("user", 4)
+
+	router := setupDeadLetterRouter(mockService, mockAPIGatewayMiddleware)
+
+	httpRequest, _ := http.NewRequest("POST", "/admin/dead-letters/payment.failed/replay", nil)
+	httpRequest.Header.Set("Authorization", "Bearer mocktoken12345")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockService.AssertNotCalled(t, "Replay", "payment.failed", 50)
+}
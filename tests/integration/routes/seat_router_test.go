@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/routes"
+	"flyhorizons-bookingservice/services/errors"
 	mock_repositories "flyhorizons-bookingservice/tests/mocks"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -19,8 +22,17 @@ type TestSeatRouter struct {
 
 // Setup
 func setupSeatRouter(mockService *mock_repositories.MockSeatService) *gin.Engine {
+	return setupSeatHoldRouter(mockService, new(mock_repositories.MockSeatHoldService))
+}
+
+func setupSeatHoldRouter(mockService *mock_repositories.MockSeatService, mockSeatHoldService *mock_repositories.MockSeatHoldService) *gin.Engine {
+	return setupSeatHoldRouterWithRole(mockService, mockSeatHoldService, "user")
+}
+
+func setupSeatHoldRouterWithRole(mockService *mock_repositories.MockSeatService, mockSeatHoldService *mock_repositories.MockSeatHoldService, role string) *gin.Engine {
 	router := gin.Default()
-	routes.RegisterSeatRoutes(router, mockService)
+	gatewayAuthMiddleware := mock_repositories.NewMockGatewayAuthMiddleware(role, 1)
+	routes.RegisterSeatRoutes(router, mockService, mockSeatHoldService, gatewayAuthMiddleware)
 	return router
 }
 
@@ -63,3 +75,182 @@ func TestGetSeatsByFlightCodeReturnsSeatsJSON(t *testing.T) {
 	assert.Equal(t, seats, mockSeats)
 	mockService.AssertExpectations(t)
 }
+
+func getSeatHold() models.SeatHold {
+	return models.SeatHold{
+		HoldID:     "abc123",
+		FlightCode: "FR788",
+		Seats:      []models.Seat{{Row: 1, Column: "A"}},
+		ExpiresAt:  time.Date(2025, 4, 3, 9, 10, 0, 0, time.UTC),
+	}
+}
+
+func TestHoldSeatsReturnsCreatedSeatHoldJSON(t *testing.T) {
+	// Arrange
+	mockSeatHoldService := new(mock_repositories.MockSeatHoldService)
+	mockSeatHold := getSeatHold()
+	request := models.SeatHoldRequest{FlightCode: "FR788", Seats: []models.Seat{{Row: 1, Column: "A"}}}
+	mockSeatHoldService.On("Hold", 1, request).Return(&mockSeatHold, nil)
+
+	router := setupSeatHoldRouter(new(mock_repositories.MockSeatService), mockSeatHoldService)
+
+	httpRequest, _ := http.NewRequest("POST", "/bookings/seat-holds", strings.NewReader(`{"flight_code":"FR788","seats":[{"row":1,"column":"A"}]}`))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+
+	var seatHold models.SeatHold
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &seatHold)
+	assert.NoError(t, err)
+	assert.Equal(t, mockSeatHold, seatHold)
+	mockSeatHoldService.AssertExpectations(t)
+}
+
+func TestHoldTakenSeatsReturnsConflict(t *testing.T) {
+	// Arrange
+	mockSeatHoldService := new(mock_repositories.MockSeatHoldService)
+	takenSeats := []models.Seat{{Row: 1, Column: "A"}}
+	mockSeatHoldService.On("Hold", 1, models.SeatHoldRequest{FlightCode: "FR788", Seats: takenSeats}).
+		Return(nil, errors.NewSeatHeldError("FR788", takenSeats, 409))
+
+	router := setupSeatHoldRouter(new(mock_repositories.MockSeatService), mockSeatHoldService)
+
+	httpRequest, _ := http.NewRequest("POST", "/bookings/seat-holds", strings.NewReader(`{"flight_code":"FR788","seats":[{"row":1,"column":"A"}]}`))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+
+	var response struct {
+		Seats []models.Seat `json:"seats"`
+	}
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, takenSeats, response.Seats)
+}
+
+func TestHoldBlockedSeatsReturnsBadRequest(t *testing.T) {
+	// Arrange
+	mockSeatHoldService := new(mock_repositories.MockSeatHoldService)
+	blockedSeats := []models.Seat{{Row: 2, Column: "C"}}
+	mockSeatHoldService.On("Hold", 1, models.SeatHoldRequest{FlightCode: "FR788", Seats: blockedSeats}).
+		Return(nil, errors.NewInvalidSeatHoldError("FR788", blockedSeats, 400))
+
+	router := setupSeatHoldRouter(new(mock_repositories.MockSeatService), mockSeatHoldService)
+
+	httpRequest, _ := http.NewRequest("POST", "/bookings/seat-holds", strings.NewReader(`{"flight_code":"FR788","seats":[{"row":2,"column":"C"}]}`))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+
+	var response struct {
+		Seats []models.Seat `json:"seats"`
+	}
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, blockedSeats, response.Seats)
+}
+
+func TestHoldSeatsWithoutCreatePermissionReturnsForbidden(t *testing.T) {
+	// Arrange
+	mockSeatHoldService := new(mock_repositories.MockSeatHoldService)
+	router := setupSeatHoldRouterWithRole(new(mock_repositories.MockSeatService), mockSeatHoldService, "guest")
+
+	httpRequest, _ := http.NewRequest("POST", "/bookings/seat-holds", strings.NewReader(`{"flight_code":"FR788","seats":[{"row":1,"column":"A"}]}`))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	mockSeatHoldService.AssertNotCalled(t, "Hold", 1, models.SeatHoldRequest{FlightCode: "FR788", Seats: []models.Seat{{Row: 1, Column: "A"}}})
+}
+
+func TestExtendSeatHoldPastMaxLifetimeReturnsConflict(t *testing.T) {
+	// Arrange
+	mockSeatHoldService := new(mock_repositories.MockSeatHoldService)
+	mockSeatHoldService.On("Extend", 1, "abc123").Return(nil, errors.NewSeatHoldLifetimeLimitError("FR788", 30*time.Minute, 409))
+
+	router := setupSeatHoldRouter(new(mock_repositories.MockSeatService), mockSeatHoldService)
+
+	httpRequest, _ := http.NewRequest("PUT", "/bookings/seat-holds/abc123", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+	mockSeatHoldService.AssertExpectations(t)
+}
+
+func TestExtendUnknownSeatHoldReturnsNotFound(t *testing.T) {
+	// Arrange
+	mockSeatHoldService := new(mock_repositories.MockSeatHoldService)
+	mockSeatHoldService.On("Extend", 1, "abc123").Return(nil, errors.NewSeatHoldNotFoundError("abc123", 404))
+
+	router := setupSeatHoldRouter(new(mock_repositories.MockSeatService), mockSeatHoldService)
+
+	httpRequest, _ := http.NewRequest("PUT", "/bookings/seat-holds/abc123", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	mockSeatHoldService.AssertExpectations(t)
+}
+
+func TestReleaseSeatHoldReturnsOK(t *testing.T) {
+	// Arrange
+	mockSeatHoldService := new(mock_repositories.MockSeatHoldService)
+	mockSeatHoldService.On("Release", 1, "abc123").Return(nil)
+
+	router := setupSeatHoldRouter(new(mock_repositories.MockSeatService), mockSeatHoldService)
+
+	httpRequest, _ := http.NewRequest("DELETE", "/bookings/seat-holds/abc123", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	mockSeatHoldService.AssertExpectations(t)
+}
+
+func TestGetSeatsByFlightCodeWithoutSeatMapReturnsNotFound(t *testing.T) {
+	// Arrange
+	mockService := new(mock_repositories.MockSeatService)
+	flightCode := "FR999"
+	mockService.On("GetByFlightCode", flightCode).Return(nil, errors.NewSeatMapNotFoundError(flightCode, 404))
+
+	router := setupSeatRouter(mockService)
+
+	httpRequest, _ := http.NewRequest("GET", fmt.Sprintf("/bookings/seats/%s", flightCode), nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	mockService.AssertExpectations(t)
+}
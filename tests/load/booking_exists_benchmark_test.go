@@ -0,0 +1,138 @@
+package load_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/repositories"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services"
+	"flyhorizons-bookingservice/services/converter"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Unlike the load tests these benchmarks run against an in-memory SQLite database, no running microservice is needed
+// go test ./tests/load -run ^$ -bench . -benchmem
+// The latency of a create or update should stay about the same for every table size
+
+var benchmarkTableSizes = []int{100, 1000, 10000}
+
+func setupBenchmarkBookingService(b *testing.B, tableSize int) (*services.BookingService, []entities.BookingEntity) {
+	b.Helper()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:bookings%d?mode=memory&cache=shared", tableSize)), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		b.Fatalf("Failed to create SQLite database: %v", err)
+	}
+	if err := db.AutoMigrate(&entities.BookingEntity{}, &entities.PassengerEntity{}, &entities.SeatEntity{}, &entities.BookingStatusHistoryEntity{}, &entities.OutboxMessageEntity{}, &entities.SeatHoldEntity{}, &entities.FlightFareEntity{}); err != nil {
+		b.Fatalf("Failed to auto-migrate schema: %v", err)
+	}
+
+	bookings := make([]entities.BookingEntity, tableSize)
+	for i := range bookings {
+		bookings[i] = entities.BookingEntity{
+			UserID:      i%50 + 1,
+			FlightCode:  fmt.Sprintf("FR%d", i%100),
+			FlightClass: 1,
+			CreatedAt:   time.Now(),
+			Status:      "Confirmed",
+			Passengers: []entities.PassengerEntity{
+				{FullName: "John Doe", DateOfBirth: time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC), PassportNumber: fmt.Sprintf("P%d", i), Luggage: `[{"type":"SmallBag","quantity":1}]`},
+			},
+		}
+	}
+	if err := db.CreateInBatches(&bookings, 500).Error; err != nil {
+		b.Fatalf("Failed to seed bookings: %v", err)
+	}
+	// Every seeded flight gets a Business fare, so the seeded bookings can be priced as well
+	departureAt := time.Now().AddDate(0, 1, 0)
+	fares := []entities.FlightFareEntity{{FlightCode: "FR788", FlightClass: 1, DepartureAt: &departureAt, Currency: "EUR", BaseFareCents: 24900}}
+	for i := 0; i < 100; i++ {
+		fares = append(fares, entities.FlightFareEntity{FlightCode: fmt.Sprintf("FR%d", i), FlightClass: 1, DepartureAt: &departureAt, Currency: "EUR", BaseFareCents: 24900})
+	}
+	if err := db.Create(&fares).Error; err != nil {
+		b.Fatalf("Failed to seed flight fares: %v", err)
+	}
+
+	bookingRepo := repositories.NewBookingRepository(&repositories.BaseRepository{DB: db})
+	pricingService := services.NewPricingService(repositories.NewFareRepository(&repositories.BaseRepository{DB: db}), nil, nil, []byte("benchmark-quote-secret"), 15*time.Minute)
+	bookingService := services.NewBookingService(bookingRepo, nil, pricingService, nil, converter.BookingConverter{}, converter.PassengerConverter{}, converter.SeatConverter{})
+
+	// Each table size gets its own database, which is dropped once the benchmark is done
+	b.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	return bookingService, bookings
+}
+
+func BenchmarkCreateBooking(b *testing.B) {
+	for _, tableSize := range benchmarkTableSizes {
+		b.Run(fmt.Sprintf("bookings=%d", tableSize), func(b *testing.B) {
+			bookingService, _ := setupBenchmarkBookingService(b, tableSize)
+			// The booking has no seats, so the benchmark does not depend on the seat map
+			booking := models.Booking{
+				UserID:      2,
+				FlightCode:  "FR788",
+				FlightClass: 1,
+				Passengers: []models.Passenger{
+					{FullName: "John Doe", DateOfBirth: time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC), PassportNumber: "P788"},
+				},
+				Payment: models.Payment{Token: "pm_benchmark"},
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := bookingService.Create(booking); err != nil {
+					b.Fatalf("Failed to create booking: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUpdateBooking(b *testing.B) {
+	for _, tableSize := range benchmarkTableSizes {
+		b.Run(fmt.Sprintf("bookings=%d", tableSize), func(b *testing.B) {
+			bookingService, bookings := setupBenchmarkBookingService(b, tableSize)
+			booking, err := bookingService.GetByID(bookings[tableSize/2].ID)
+			if err != nil {
+				b.Fatalf("Failed to get booking: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Only the contact details change, so the price of the booking stays the same
+				booking.Passengers[0].Email = fmt.Sprintf("john%d@doe.nl", i%2)
+				// Every update increments the version, so the next update is made on the updated booking
+				booking, err = bookingService.Update(*booking)
+				if err != nil {
+					b.Fatalf("Failed to update booking: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBookingExists(b *testing.B) {
+	for _, tableSize := range benchmarkTableSizes {
+		b.Run(fmt.Sprintf("bookings=%d", tableSize), func(b *testing.B) {
+			bookingService, bookings := setupBenchmarkBookingService(b, tableSize)
+			bookingID := bookings[tableSize/2].ID
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := bookingService.BookingExists(bookingID); err != nil {
+					b.Fatalf("Failed to check booking: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package load_test
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,33 +18,48 @@ import (
 
 // Before running the load tests
 // Run the microservice at the same time
+// Set LOAD_TEST_TOKEN or the JWT_SECRET of the microservice, the bookings are posted as the load test user
+// Every booking takes its own seats, so point LOAD_TEST_FLIGHT_CODE to a flight without bookings when running the tests again
 
 type BookingLoadTest struct {
 	loadTestUtils load_test_utils.LoadTestUtils
 }
 
-func getBookingPayload() []byte {
+const (
+	loadTestUserID  = 2
+	loadTestRows    = 30
+	loadTestColumns = "ABCDEF"
+)
+
+func getFlightCode() string {
+	if flightCode := os.Getenv("LOAD_TEST_FLIGHT_CODE"); flightCode != "" {
+		return flightCode
+	}
+	return "FR788"
+}
+
+// Returns the two neighbouring seats of the nth booking, once every seat has been taken the bookings are posted without seats
+func getSeats(n int) []models.Seat {
+	pairsPerRow := len(loadTestColumns) / 2
+	if n >= loadTestRows*pairsPerRow {
+		return nil
+	}
+
+	row := 1 + n/pairsPerRow
+	column := (n % pairsPerRow) * 2
+	return []models.Seat{
+		{Row: row, Column: string(loadTestColumns[column]), Available: true},
+		{Row: row, Column: string(loadTestColumns[column+1]), Available: true},
+	}
+}
+
+func getBookingPayload(seats []models.Seat) []byte {
 	booking := models.Booking{
 		ID:          0,
-		UserID:      2,
-		FlightCode:  "FR788",
+		UserID:      loadTestUserID,
+		FlightCode:  getFlightCode(),
 		FlightClass: 0,
-		Luggage: []enums.Luggage{
-			enums.SmallBag,
-			enums.CabinBag,
-		},
-		Seats: []models.Seat{
-			{
-				Row:       1,
-				Column:    "A",
-				Available: true,
-			},
-			{
-				Row:       1,
-				Column:    "B",
-				Available: true,
-			},
-		},
+		Seats:       seats,
 		Passengers: []models.Passenger{
 			{
 				ID:             1,
@@ -49,6 +67,10 @@ func getBookingPayload() []byte {
 				Email:          "john@doe.nl",
 				DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 				PassportNumber: "1234",
+				Luggage: []models.LuggageItem{
+					{Type: enums.SmallBag, Quantity: 1},
+					{Type: enums.CabinBag, Quantity: 1},
+				},
 			},
 			{
 				ID:             2,
@@ -58,6 +80,13 @@ func getBookingPayload() []byte {
 				PassportNumber: "4321",
 			},
 		},
+		Payment: models.Payment{
+			Token:     "pm_loadtest",
+			FirstName: "John",
+			LastName:  "Doe",
+			Amount:    149.99,
+			Currency:  "EUR",
+		},
 	}
 
 	payload, err := json.Marshal(booking)
@@ -69,24 +98,37 @@ func getBookingPayload() []byte {
 	return payload
 }
 
+// Rotates the seats of the posted bookings, so the bookings do not conflict with each other
+func newBookingTargeter(authorization string) vegeta.Targeter {
+	var bookings int64
+	return func(target *vegeta.Target) error {
+		if target == nil {
+			return vegeta.ErrNilTarget
+		}
+
+		n := atomic.AddInt64(&bookings, 1) - 1
+		target.Method = "POST"
+		target.URL = "http://localhost:8083/bookings"
+		target.Body = getBookingPayload(getSeats(int(n)))
+		target.Header = http.Header{
+			"Content-Type":  {"application/json"},
+			"Authorization": {authorization},
+		}
+		return nil
+	}
+}
+
 func createBooking(t *testing.T, rate vegeta.Rate, duration time.Duration, htmlReport string, title string) vegeta.Metrics {
 	loadTest := BookingLoadTest{
 		loadTestUtils: load_test_utils.LoadTestUtils{},
 	}
 
-	target := vegeta.Target{
-		Method: "POST",
-		URL:    "http://localhost:8083/bookings",
-		Body:   getBookingPayload(),
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-	}
+	targeter := newBookingTargeter(loadTest.loadTestUtils.GetAuthorizationHeader(t, loadTestUserID))
 
 	attacker := vegeta.NewAttacker()
 
 	var metrics vegeta.Metrics
-	for res := range attacker.Attack(vegeta.NewStaticTargeter(target), rate, duration, "Load Test CreateBookings") {
+	for res := range attacker.Attack(targeter, rate, duration, "Load Test CreateBookings") {
 		metrics.Add(res)
 	}
 	metrics.Close()
@@ -104,14 +146,7 @@ func createBookingsIncrementally(t *testing.T, rates []int, htmlReport string, t
 		loadTestUtils: load_test_utils.LoadTestUtils{},
 	}
 
-	target := vegeta.Target{
-		Method: "POST",
-		URL:    "http://localhost:8083/bookings",
-		Body:   getBookingPayload(),
-		Header: map[string][]string{
-			"Content-Type": {"application/json"},
-		},
-	}
+	targeter := newBookingTargeter(loadTest.loadTestUtils.GetAuthorizationHeader(t, loadTestUserID))
 
 	attacker := vegeta.NewAttacker()
 	var metrics vegeta.Metrics
@@ -121,7 +156,7 @@ func createBookingsIncrementally(t *testing.T, rates []int, htmlReport string, t
 		rate := vegeta.Rate{Freq: freq, Per: time.Second}
 		duration := 1 * time.Second
 
-		for res := range attacker.Attack(vegeta.NewStaticTargeter(target), rate, duration, fmt.Sprintf("Ramp-Up %d rps", freq)) {
+		for res := range attacker.Attack(targeter, rate, duration, fmt.Sprintf("Ramp-Up %d rps", freq)) {
 			metrics.Add(res)
 		}
 	}
@@ -7,12 +7,44 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
 )
 
 type LoadTestUtils struct {
 }
 
+// Returns the Authorization header of the load test user
+// LOAD_TEST_TOKEN is used as is, otherwise a customer token is signed with the JWT_SECRET of the microservice
+func (utils *LoadTestUtils) GetAuthorizationHeader(t *testing.T, userID int) string {
+	if token := os.Getenv("LOAD_TEST_TOKEN"); token != "" {
+		return "Bearer " + token
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		t.Fatalf("Set LOAD_TEST_TOKEN or JWT_SECRET to authenticate the load test requests")
+	}
+
+	claims := jwt.MapClaims{
+		"sub":  userID,
+		"role": "customer",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		claims["iss"] = issuer
+	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		claims["aud"] = audience
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Failed to sign the load test token: %v", err)
+	}
+	return "Bearer " + token
+}
+
 func (utils *LoadTestUtils) LogMetrics(t *testing.T, metrics *vegeta.Metrics) {
 	t.Logf("Requests: %d", metrics.Requests)
 	t.Logf("Success Rate: %.2f%%", metrics.Success*100)
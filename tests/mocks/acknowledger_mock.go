@@ -0,0 +1,28 @@
+package mock_repositories
+
+import (
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/mock"
+)
+
+// Acknowledges the deliveries of the tests, instead of a RabbitMQ channel
+type MockAcknowledger struct {
+	mock.Mock
+}
+
+var _ amqp091.Acknowledger = (*MockAcknowledger)(nil)
+
+func (m *MockAcknowledger) Ack(tag uint64, multiple bool) error {
+	args := m.Called(tag, multiple)
+	return args.Error(0)
+}
+
+func (m *MockAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	args := m.Called(tag, multiple, requeue)
+	return args.Error(0)
+}
+
+func (m *MockAcknowledger) Reject(tag uint64, requeue bool) error {
+	args := m.Called(tag, requeue)
+	return args.Error(0)
+}
@@ -0,0 +1,22 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAncillaryRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.AncillaryRepository = (*MockAncillaryRepository)(nil)
+
+func (m *MockAncillaryRepository) GetAll() ([]entities.AncillaryEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.AncillaryEntity), args.Error(1)
+}
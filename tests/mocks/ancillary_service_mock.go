@@ -0,0 +1,23 @@
+package mock_repositories
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAncillaryService struct {
+	mock.Mock
+}
+
+var _ interfaces.AncillaryService = (*MockAncillaryService)(nil)
+
+func (m *MockAncillaryService) GetAvailable(flightClass enums.FlightClass) ([]models.Ancillary, error) {
+	args := m.Called(flightClass)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Ancillary), args.Error(1)
+}
@@ -1,9 +1,11 @@
 package mock_repositories
 
 import (
+	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/models/enums"
 	entities "flyhorizons-bookingservice/repositories/entity"
 	"flyhorizons-bookingservice/services/interfaces"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -14,36 +16,96 @@ type MockBookingRepository struct {
 
 var _ interfaces.BookingRepository = (*MockBookingRepository)(nil)
 
-func (m *MockBookingRepository) GetAll() []entities.BookingEntity {
+// Runs fn directly against the mock, there is no transaction to roll back
+func (m *MockBookingRepository) Transaction(fn func(repo interfaces.BookingRepository) error) error {
+	return fn(m)
+}
+
+func (m *MockBookingRepository) GetAll() ([]entities.BookingEntity, error) {
 	args := m.Called()
-	return args.Get(0).([]entities.BookingEntity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.BookingEntity), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetByID(id int) (*entities.BookingEntity, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingEntity), args.Error(1)
 }
 
-func (m *MockBookingRepository) GetByID(id int) entities.BookingEntity {
+func (m *MockBookingRepository) Exists(id int) (bool, error) {
 	args := m.Called(id)
-	return args.Get(0).(entities.BookingEntity)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetByUserID(userID int, criteria models.BookingSearchCriteria) ([]entities.BookingEntity, int64, string, error) {
+	args := m.Called(userID, criteria)
+	if args.Get(0) == nil {
+		return nil, 0, "", args.Error(3)
+	}
+	return args.Get(0).([]entities.BookingEntity), args.Get(1).(int64), args.String(2), args.Error(3)
 }
 
-func (m *MockBookingRepository) GetByUserID(userID int) []entities.BookingEntity {
-	args := m.Called(userID)
-	return args.Get(0).([]entities.BookingEntity)
+func (m *MockBookingRepository) Search(criteria models.BookingSearchCriteria) ([]entities.BookingEntity, int64, string, error) {
+	args := m.Called(criteria)
+	if args.Get(0) == nil {
+		return nil, 0, "", args.Error(3)
+	}
+	return args.Get(0).([]entities.BookingEntity), args.Get(1).(int64), args.String(2), args.Error(3)
 }
 
-func (m *MockBookingRepository) Create(booking entities.BookingEntity) *entities.BookingEntity {
+func (m *MockBookingRepository) Create(booking entities.BookingEntity) (*entities.BookingEntity, error) {
 	args := m.Called(booking)
-	return args.Get(0).(*entities.BookingEntity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingEntity), args.Error(1)
 }
 
-func (m *MockBookingRepository) DeleteByBookingID(ID int) bool {
+func (m *MockBookingRepository) DeleteByBookingID(ID int) error {
 	args := m.Called(ID)
-	return args.Bool(0)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) Purge(closedBefore time.Time) (int64, error) {
+	args := m.Called(closedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetUnpaidIDs(createdBefore time.Time) ([]int, error) {
+	args := m.Called(createdBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockBookingRepository) UpdateStatus(bookingID int, from enums.Status, to enums.Status, reason string) error {
+	args := m.Called(bookingID, from, to, reason)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) GetStatusHistory(bookingID int) ([]entities.BookingStatusHistoryEntity, error) {
+	args := m.Called(bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.BookingStatusHistoryEntity), args.Error(1)
 }
 
-func (m *MockBookingRepository) UpdateStatus(bookingID int, status enums.Status) {
-	m.Called(bookingID, status)
+func (m *MockBookingRepository) AddOutboxMessage(queue string, payload []byte) error {
+	args := m.Called(queue, payload)
+	return args.Error(0)
 }
 
-func (m *MockBookingRepository) Update(booking entities.BookingEntity) entities.BookingEntity {
+func (m *MockBookingRepository) Update(booking entities.BookingEntity) (*entities.BookingEntity, error) {
 	args := m.Called(booking)
-	return args.Get(0).(entities.BookingEntity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingEntity), args.Error(1)
 }
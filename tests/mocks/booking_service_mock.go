@@ -3,6 +3,7 @@ package mock_repositories
 import (
 	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/services/interfaces"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -13,14 +14,41 @@ type MockBookingService struct {
 
 var _ interfaces.BookingService = (*MockBookingService)(nil)
 
-func (m *MockBookingService) BookingExists(bookingID int) bool {
+func (m *MockBookingService) BookingExists(bookingID int) (bool, error) {
 	args := m.Called(bookingID)
-	return args.Bool(0)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBookingService) GetByID(id int) (*models.Booking, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Booking), args.Error(1)
 }
 
-func (m *MockBookingService) GetByUserID(userID int) []models.Booking {
-	args := m.Called(userID)
-	return args.Get(0).([]models.Booking)
+func (m *MockBookingService) GetByUserID(userID int, criteria models.BookingSearchCriteria) (*models.BookingSearchResult, error) {
+	args := m.Called(userID, criteria)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookingSearchResult), args.Error(1)
+}
+
+func (m *MockBookingService) Search(criteria models.BookingSearchCriteria) (*models.BookingSearchResult, error) {
+	args := m.Called(criteria)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookingSearchResult), args.Error(1)
+}
+
+func (m *MockBookingService) Quote(booking models.Booking) (*models.Quote, error) {
+	args := m.Called(booking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Quote), args.Error(1)
 }
 
 func (m *MockBookingService) Create(booking models.Booking) (*models.Booking, error) {
@@ -31,11 +59,21 @@ func (m *MockBookingService) Create(booking models.Booking) (*models.Booking, er
 	return args.Get(0).(*models.Booking), args.Error(1)
 }
 
+func (m *MockBookingService) Cancel(bookingID int, reason string) error {
+	args := m.Called(bookingID, reason)
+	return args.Error(0)
+}
+
 func (m *MockBookingService) DeleteByBookingID(id int) (bool, error) {
 	args := m.Called(id)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockBookingService) Purge(closedBefore time.Time) (int64, error) {
+	args := m.Called(closedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockBookingService) Update(booking models.Booking) (*models.Booking, error) {
 	args := m.Called(booking)
 	if args.Get(0) == nil {
@@ -43,3 +81,19 @@ func (m *MockBookingService) Update(booking models.Booking) (*models.Booking, er
 	}
 	return args.Get(0).(*models.Booking), args.Error(1)
 }
+
+func (m *MockBookingService) RetryPayment(bookingID int, payment models.Payment) (*models.Booking, error) {
+	args := m.Called(bookingID, payment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Booking), args.Error(1)
+}
+
+func (m *MockBookingService) GetStatusHistory(bookingID int) ([]models.BookingStatusChange, error) {
+	args := m.Called(bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BookingStatusChange), args.Error(1)
+}
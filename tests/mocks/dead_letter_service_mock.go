@@ -0,0 +1,27 @@
+package mock_repositories
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeadLetterService struct {
+	mock.Mock
+}
+
+var _ interfaces.DeadLetterService = (*MockDeadLetterService)(nil)
+
+func (m *MockDeadLetterService) List(queue string, limit int) ([]models.DeadLetterMessage, error) {
+	args := m.Called(queue, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeadLetterMessage), args.Error(1)
+}
+
+func (m *MockDeadLetterService) Replay(queue string, limit int) (int, error) {
+	args := m.Called(queue, limit)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,23 @@
+package mock_repositories
+
+import (
+	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFareRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.FareRepository = (*MockFareRepository)(nil)
+
+func (m *MockFareRepository) GetByFlight(flightCode string, flightClass enums.FlightClass) (*entities.FlightFareEntity, error) {
+	args := m.Called(flightCode, flightClass)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FlightFareEntity), args.Error(1)
+}
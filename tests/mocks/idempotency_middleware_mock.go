@@ -0,0 +1,17 @@
+package mock_repositories
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyMiddleware struct {
+	mock.Mock
+}
+
+// Passes every request through, the idempotency is tested separately
+func (m *MockIdempotencyMiddleware) IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+	}
+}
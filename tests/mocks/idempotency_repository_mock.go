@@ -0,0 +1,43 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.IdempotencyRepository = (*MockIdempotencyRepository)(nil)
+
+func (m *MockIdempotencyRepository) Reserve(key entities.IdempotencyKeyEntity) (*entities.IdempotencyKeyEntity, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.IdempotencyKeyEntity), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Complete(key string, statusCode int, responseBody string) error {
+	args := m.Called(key, statusCode, responseBody)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Release(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) ReleaseAbandoned(key string, reservedBefore time.Time) (bool, error) {
+	args := m.Called(key, reservedBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,24 @@
+package mock_repositories
+
+import (
+	"flyhorizons-bookingservice/services/interfaces"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMessageBroker struct {
+	mock.Mock
+}
+
+var _ interfaces.MessageBroker = (*MockMessageBroker)(nil)
+
+func (m *MockMessageBroker) RegisterConsumer(start func(channel interfaces.RabbitMQChannel) error) error {
+	args := m.Called(start)
+	return args.Error(0)
+}
+
+func (m *MockMessageBroker) PublishWithHeaders(queue string, body []byte, headers amqp091.Table) error {
+	args := m.Called(queue, body, headers)
+	return args.Error(0)
+}
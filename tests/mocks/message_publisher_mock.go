@@ -0,0 +1,18 @@
+package mock_repositories
+
+import (
+	"flyhorizons-bookingservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMessagePublisher struct {
+	mock.Mock
+}
+
+var _ interfaces.MessagePublisher = (*MockMessagePublisher)(nil)
+
+func (m *MockMessagePublisher) Publish(queue string, body []byte) error {
+	args := m.Called(queue, body)
+	return args.Error(0)
+}
@@ -0,0 +1,30 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.OutboxRepository = (*MockOutboxRepository)(nil)
+
+func (m *MockOutboxRepository) GetPending(limit int) ([]entities.OutboxMessageEntity, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]entities.OutboxMessageEntity), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkDispatched(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkFailed(id int, lastError string, nextAttemptAt time.Time) error {
+	args := m.Called(id, lastError, nextAttemptAt)
+	return args.Error(0)
+}
@@ -0,0 +1,48 @@
+package mock_repositories
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/services/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPricingService struct {
+	mock.Mock
+}
+
+var _ interfaces.PricingService = (*MockPricingService)(nil)
+
+func (m *MockPricingService) GetDeparture(flightCode string, flightClass enums.FlightClass) (*time.Time, error) {
+	args := m.Called(flightCode, flightClass)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockPricingService) Calculate(booking models.Booking) (*models.PriceBreakdown, error) {
+	args := m.Called(booking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PriceBreakdown), args.Error(1)
+}
+
+func (m *MockPricingService) Quote(booking models.Booking) (*models.Quote, error) {
+	args := m.Called(booking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Quote), args.Error(1)
+}
+
+func (m *MockPricingService) PriceFromQuote(quoteID string, booking models.Booking) (*models.PriceBreakdown, error) {
+	args := m.Called(quoteID, booking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PriceBreakdown), args.Error(1)
+}
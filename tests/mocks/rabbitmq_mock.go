@@ -0,0 +1,115 @@
+package mock_repositories
+
+import (
+	"context"
+	"flyhorizons-bookingservice/services/interfaces"
+	"sync"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRabbitMQDialer struct {
+	mock.Mock
+}
+
+var _ interfaces.RabbitMQDialer = (*MockRabbitMQDialer)(nil)
+
+func (m *MockRabbitMQDialer) Dial(url string) (interfaces.RabbitMQConnection, error) {
+	args := m.Called(url)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(interfaces.RabbitMQConnection), args.Error(1)
+}
+
+// Keeps the close receivers, so the tests can drop the connection like the broker would
+type MockRabbitMQConnection struct {
+	mock.Mock
+	mutex          sync.Mutex
+	closeReceivers []chan *amqp091.Error
+}
+
+var _ interfaces.RabbitMQConnection = (*MockRabbitMQConnection)(nil)
+
+func (m *MockRabbitMQConnection) Channel() (interfaces.RabbitMQChannel, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(interfaces.RabbitMQChannel), args.Error(1)
+}
+
+func (m *MockRabbitMQConnection) NotifyClose(receiver chan *amqp091.Error) chan *amqp091.Error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.closeReceivers = append(m.closeReceivers, receiver)
+	return receiver
+}
+
+func (m *MockRabbitMQConnection) IsClosed() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *MockRabbitMQConnection) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockRabbitMQConnection) Drop(reason *amqp091.Error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, receiver := range m.closeReceivers {
+		receiver <- reason
+	}
+}
+
+type MockRabbitMQChannel struct {
+	mock.Mock
+}
+
+var _ interfaces.RabbitMQChannel = (*MockRabbitMQChannel)(nil)
+
+func (m *MockRabbitMQChannel) Confirm(noWait bool) error {
+	args := m.Called(noWait)
+	return args.Error(0)
+}
+
+func (m *MockRabbitMQChannel) QueueDeclare(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, queueArgs amqp091.Table) (amqp091.Queue, error) {
+	args := m.Called(name, durable, autoDelete, exclusive, noWait, queueArgs)
+	return amqp091.Queue{Name: name}, args.Error(0)
+}
+
+func (m *MockRabbitMQChannel) Consume(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, consumeArgs amqp091.Table) (<-chan amqp091.Delivery, error) {
+	args := m.Called(queue, consumer, autoAck, exclusive, noLocal, noWait, consumeArgs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(<-chan amqp091.Delivery), args.Error(1)
+}
+
+func (m *MockRabbitMQChannel) Get(queue string, autoAck bool) (amqp091.Delivery, bool, error) {
+	args := m.Called(queue, autoAck)
+	return args.Get(0).(amqp091.Delivery), args.Bool(1), args.Error(2)
+}
+
+func (m *MockRabbitMQChannel) PublishWithDeferredConfirmWithContext(ctx context.Context, exchange string, key string, mandatory bool, immediate bool, msg amqp091.Publishing) (*amqp091.DeferredConfirmation, error) {
+	args := m.Called(exchange, key, mandatory, immediate, msg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*amqp091.DeferredConfirmation), args.Error(1)
+}
+
+// The channel is closed together with its connection, so it is never dropped on its own
+func (m *MockRabbitMQChannel) NotifyClose(receiver chan *amqp091.Error) chan *amqp091.Error {
+	return receiver
+}
+
+func (m *MockRabbitMQChannel) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
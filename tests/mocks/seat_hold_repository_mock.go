@@ -0,0 +1,51 @@
+package mock_repositories
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSeatHoldRepository struct {
+	mock.Mock
+}
+
+var _ interfaces.SeatHoldRepository = (*MockSeatHoldRepository)(nil)
+
+func (m *MockSeatHoldRepository) Create(holds []entities.SeatHoldEntity) error {
+	args := m.Called(holds)
+	return args.Error(0)
+}
+
+func (m *MockSeatHoldRepository) GetActiveByHoldID(holdID string) ([]entities.SeatHoldEntity, error) {
+	args := m.Called(holdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.SeatHoldEntity), args.Error(1)
+}
+
+func (m *MockSeatHoldRepository) GetActiveByUser(userID int, flightCode string) ([]entities.SeatHoldEntity, error) {
+	args := m.Called(userID, flightCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.SeatHoldEntity), args.Error(1)
+}
+
+func (m *MockSeatHoldRepository) Extend(holdID string, expiresAt time.Time) error {
+	args := m.Called(holdID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSeatHoldRepository) Release(holdID string) error {
+	args := m.Called(holdID)
+	return args.Error(0)
+}
+
+func (m *MockSeatHoldRepository) DeleteExpired() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
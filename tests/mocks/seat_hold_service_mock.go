@@ -0,0 +1,35 @@
+package mock_repositories
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/services/interfaces"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSeatHoldService struct {
+	mock.Mock
+}
+
+var _ interfaces.SeatHoldService = (*MockSeatHoldService)(nil)
+
+func (m *MockSeatHoldService) Hold(userID int, request models.SeatHoldRequest) (*models.SeatHold, error) {
+	args := m.Called(userID, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SeatHold), args.Error(1)
+}
+
+func (m *MockSeatHoldService) Extend(userID int, holdID string) (*models.SeatHold, error) {
+	args := m.Called(userID, holdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SeatHold), args.Error(1)
+}
+
+func (m *MockSeatHoldService) Release(userID int, holdID string) error {
+	args := m.Called(userID, holdID)
+	return args.Error(0)
+}
@@ -1,6 +1,7 @@
 package mock_repositories
 
 import (
+	"flyhorizons-bookingservice/models"
 	entities "flyhorizons-bookingservice/repositories/entity"
 	"flyhorizons-bookingservice/services/interfaces"
 
@@ -13,7 +14,15 @@ type MockSeatRepository struct {
 
 var _ interfaces.SeatRepository = (*MockSeatRepository)(nil)
 
-func (m *MockSeatRepository) GetByFlightCode(flightCode string) ([]entities.SeatOptionEntity, error) {
+func (m *MockSeatRepository) GetConfigurationByFlightCode(flightCode string) (*entities.AircraftConfigurationEntity, error) {
 	args := m.Called(flightCode)
-	return args.Get(0).([]entities.SeatOptionEntity), nil
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.AircraftConfigurationEntity), args.Error(1)
+}
+
+func (m *MockSeatRepository) GetOccupiedSeats(flightCode string) ([]models.Seat, error) {
+	args := m.Called(flightCode)
+	return args.Get(0).([]models.Seat), args.Error(1)
 }
@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services"
+	"flyhorizons-bookingservice/services/errors"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Setup
+func setupAncillaryService() (*mock_repositories.MockAncillaryRepository, *services.AncillaryService) {
+	mockAncillaryRepo := new(mock_repositories.MockAncillaryRepository)
+	return mockAncillaryRepo, services.NewAncillaryService(mockAncillaryRepo)
+}
+
+func getAncillaryEntities() []entities.AncillaryEntity {
+	return []entities.AncillaryEntity{
+		{Code: "LoungeAccess", Category: "Lounge", Name: "Lounge access", Currency: "EUR", Prices: `{"Business":0}`},
+		{Code: "VegetarianMeal", Category: "Meal", Name: "Vegetarian meal", Description: "A hot vegetarian meal", Currency: "EUR", Prices: `{"Economy":1200,"Business":0}`},
+		{Code: "PetInCabin", Category: "PetInCabin", Name: "Pet in cabin", Currency: "EUR", Prices: `{"Economy":6000}`},
+	}
+}
+
+// Unit Tests
+func TestGetAvailableAncillariesReturnsAncillariesWithPriceOfFlightClass(t *testing.T) {
+	// Arrange
+	mockAncillaryRepo, ancillaryService := setupAncillaryService()
+	mockAncillaryRepo.On("GetAll").Return(getAncillaryEntities(), nil)
+
+	// Act
+	ancillaries, err := ancillaryService.GetAvailable(enums.Business)
+
+	// Assert
+	assert.NoError(t, err)
+	expectedAncillaries := []models.Ancillary{
+		{Code: "LoungeAccess", Category: enums.Lounge, Name: "Lounge access", Currency: "EUR", PriceCents: 0},
+		{Code: "VegetarianMeal", Category: enums.Meal, Name: "Vegetarian meal", Description: "A hot vegetarian meal", Currency: "EUR", PriceCents: 0},
+	}
+	assert.Equal(t, expectedAncillaries, ancillaries)
+}
+
+func TestGetAvailableAncillariesLeavesOutAncillariesWithoutPrice(t *testing.T) {
+	// Arrange
+	mockAncillaryRepo, ancillaryService := setupAncillaryService()
+	mockAncillaryRepo.On("GetAll").Return(getAncillaryEntities(), nil)
+
+	// Act
+	ancillaries, err := ancillaryService.GetAvailable(enums.Economy)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, ancillaries, 2)
+	assert.Equal(t, "VegetarianMeal", ancillaries[0].Code)
+	assert.Equal(t, int64(1200), ancillaries[0].PriceCents)
+	assert.Equal(t, "PetInCabin", ancillaries[1].Code)
+}
+
+func TestGetAvailableAncillariesWithUnavailableDatabaseThrowsDatabaseUnavailableError(t *testing.T) {
+	// Arrange
+	mockAncillaryRepo, ancillaryService := setupAncillaryService()
+	databaseError := errors.NewDatabaseUnavailableError(fmt.Errorf("connection refused"), 503)
+	mockAncillaryRepo.On("GetAll").Return(nil, databaseError)
+
+	// Act
+	ancillaries, err := ancillaryService.GetAvailable(enums.Economy)
+
+	// Assert
+	assert.Equal(t, databaseError, err)
+	assert.Nil(t, ancillaries)
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"encoding/json"
+	"flyhorizons-bookingservice/services/authorization"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestAuthorization struct {
+}
+
+// Setup
+func setupAuthorizationRouter(role string) *gin.Engine {
+	router := gin.Default()
+	router.GET("/bookings", func(ctx *gin.Context) {
+		ctx.Set("user_id", 4)
+		ctx.Set("role", role)
+	}, authorization.RequirePermission(authorization.ReadOwnBookings, authorization.ReadAnyBooking), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/admin", func(ctx *gin.Context) {
+		ctx.Set("user_id", 4)
+		ctx.Set("role", role)
+	}, authorization.RequirePermission(authorization.ManageDeadLetters), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	return router
+}
+
+// Policy Unit Tests
+func TestCustomerCanOnlyManageOwnBookings(t *testing.T) {
+	// Act & Assert
+	for _, role := range []authorization.Role{authorization.Customer, authorization.User} {
+		assert.True(t, role.HasPermission(authorization.CreateBooking))
+		assert.True(t, role.HasPermission(authorization.ReadOwnBookings))
+		assert.True(t, role.HasPermission(authorization.DeleteOwnBookings))
+		assert.False(t, role.HasPermission(authorization.ReadAnyBooking))
+		assert.False(t, role.HasPermission(authorization.UpdateAnyBooking))
+		assert.False(t, role.HasPermission(authorization.ManageDeadLetters))
+	}
+}
+
+func TestSupportAgentCanViewAndModifyAnyBooking(t *testing.T) {
+	// Act & Assert
+	assert.True(t, authorization.SupportAgent.HasPermission(authorization.ReadAnyBooking))
+	assert.True(t, authorization.SupportAgent.HasPermission(authorization.UpdateAnyBooking))
+	assert.False(t, authorization.SupportAgent.HasPermission(authorization.DeleteAnyBooking))
+	assert.False(t, authorization.SupportAgent.HasPermission(authorization.ManageDeadLetters))
+}
+
+func TestUnknownRoleHasNoPermissions(t *testing.T) {
+	// Act & Assert
+	assert.False(t, authorization.Role("pilot").HasPermission(authorization.ReadOwnBookings))
+	assert.False(t, authorization.Role("").HasPermission(authorization.CreateBooking))
+}
+
+// Middleware Unit Tests
+func TestRequirePermissionWithGrantedPermissionPassesThrough(t *testing.T) {
+	// Arrange
+	router := setupAuthorizationRouter("support-agent")
+	httpRequest, _ := http.NewRequest("GET", "/bookings", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+}
+
+func TestRequirePermissionWithoutPermissionReturnsReason(t *testing.T) {
+	// Arrange
+	router := setupAuthorizationRouter("customer")
+	httpRequest, _ := http.NewRequest("GET", "/admin", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+
+	var errResponse map[string]interface{}
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, authorization.ReasonMissingPermission, errResponse["reason"])
+}
+
+func TestRequirePermissionWithUnknownRoleReturnsForbidden(t *testing.T) {
+	// Arrange
+	router := setupAuthorizationRouter("")
+	httpRequest, _ := http.NewRequest("GET", "/bookings", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, httpRequest)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
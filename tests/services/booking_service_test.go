@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"encoding/json"
 	"flyhorizons-bookingservice/models"
 	"flyhorizons-bookingservice/models/enums"
 	entities "flyhorizons-bookingservice/repositories/entity"
@@ -8,6 +9,7 @@ import (
 	"flyhorizons-bookingservice/services/converter"
 	"flyhorizons-bookingservice/services/errors"
 	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"fmt"
 	"testing"
 	"time"
 
@@ -20,14 +22,64 @@ type TestBookingService struct {
 
 // Setup
 func setupBookingService() (*mock_repositories.MockBookingRepository, *services.BookingService) {
+	mockSeatService := new(mock_repositories.MockSeatService)
+	mockSeatService.On("GetByFlightCode", mock.Anything).Return(getSeatMap(), nil)
+	return setupBookingServiceWithSeatService(mockSeatService)
+}
+
+func setupBookingServiceWithSeatService(mockSeatService *mock_repositories.MockSeatService) (*mock_repositories.MockBookingRepository, *services.BookingService) {
+	mockPricingService := new(mock_repositories.MockPricingService)
+	mockPricingService.On("Calculate", mock.Anything).Return(getPrice(), nil)
+	mockPricingService.On("GetDeparture", mock.Anything, mock.Anything).Return(getDeparture(), nil)
+	return setupBookingServiceWith(mockSeatService, mockPricingService)
+}
+
+func setupBookingServiceWithPricingService(mockPricingService *mock_repositories.MockPricingService) (*mock_repositories.MockBookingRepository, *services.BookingService) {
+	mockSeatService := new(mock_repositories.MockSeatService)
+	mockSeatService.On("GetByFlightCode", mock.Anything).Return(getSeatMap(), nil)
+	mockPricingService.On("GetDeparture", mock.Anything, mock.Anything).Return(getDeparture(), nil)
+	return setupBookingServiceWith(mockSeatService, mockPricingService)
+}
+
+func setupBookingServiceWith(mockSeatService *mock_repositories.MockSeatService, mockPricingService *mock_repositories.MockPricingService) (*mock_repositories.MockBookingRepository, *services.BookingService) {
 	mockRepo := new(mock_repositories.MockBookingRepository)
 	bookingConverter := converter.BookingConverter{}
 	passengerConverter := converter.PassengerConverter{}
 	seatConverter := converter.SeatConverter{}
-	bookingService := services.NewBookingService(mockRepo, bookingConverter, passengerConverter, seatConverter)
+	mockAncillaryService := new(mock_repositories.MockAncillaryService)
+	mockAncillaryService.On("GetAvailable", mock.Anything).Return(getAncillaries(), nil)
+	bookingService := services.NewBookingService(mockRepo, mockSeatService, mockPricingService, mockAncillaryService, bookingConverter, passengerConverter, seatConverter)
 	return mockRepo, bookingService
 }
 
+func getAncillaries() []models.Ancillary {
+	return []models.Ancillary{
+		{Code: "VegetarianMeal", Category: enums.Meal, Name: "Vegetarian meal", Currency: "EUR", PriceCents: 1200},
+		{Code: "KosherMeal", Category: enums.Meal, Name: "Kosher meal", Currency: "EUR", PriceCents: 1200},
+		{Code: "PriorityBoarding", Category: enums.PriorityBoarding, Name: "Priority boarding", Currency: "EUR", PriceCents: 900},
+	}
+}
+
+func getPrice() *models.PriceBreakdown {
+	return &models.PriceBreakdown{
+		Currency: "EUR",
+		Items: []models.PriceItem{
+			{Type: "fare", Description: "Business Adult fare", Quantity: 2, UnitPriceCents: 24900, AmountCents: 49800},
+		},
+		TotalCents: 49800,
+	}
+}
+
+// Row 1 is the Business cabin, row 2 the Economy cabin
+func getSeatMap() []models.Seat {
+	return []models.Seat{
+		{Row: 1, Column: "A", Available: true, Cabin: enums.Business, Position: enums.Window},
+		{Row: 1, Column: "B", Available: true, Cabin: enums.Business, Position: enums.Window},
+		{Row: 2, Column: "A", Available: true, Cabin: enums.Economy, Position: enums.Window},
+		{Row: 2, Column: "B", Available: true, Cabin: enums.Economy, Position: enums.Window},
+	}
+}
+
 func getPassengerEntities() []entities.PassengerEntity {
 	return []entities.PassengerEntity{
 		{
@@ -37,6 +89,8 @@ func getPassengerEntities() []entities.PassengerEntity {
 			Email:          "john@doe.nl",
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			PassportNumber: "1234",
+			Luggage:        getLuggageString(),
+			Ancillaries:    "[]",
 		},
 		{
 			ID:             2,
@@ -45,6 +99,8 @@ func getPassengerEntities() []entities.PassengerEntity {
 			Email:          "jane@doe.it",
 			DateOfBirth:    time.Date(1986, 8, 8, 2, 30, 0, 0, time.UTC),
 			PassportNumber: "4321",
+			Luggage:        "[]",
+			Ancillaries:    "[]",
 		},
 	}
 }
@@ -74,6 +130,8 @@ func getPassengers() []models.Passenger {
 			Email:          "john@doe.nl",
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			PassportNumber: "1234",
+			Luggage:        getLuggageList(),
+			Ancillaries:    []string{},
 		},
 		{
 			ID:             2,
@@ -81,6 +139,8 @@ func getPassengers() []models.Passenger {
 			Email:          "jane@doe.it",
 			DateOfBirth:    time.Date(1986, 8, 8, 2, 30, 0, 0, time.UTC),
 			PassportNumber: "4321",
+			Luggage:        []models.LuggageItem{},
+			Ancillaries:    []string{},
 		},
 	}
 }
@@ -100,12 +160,20 @@ func getSeats() []models.Seat {
 	}
 }
 
-func getLuggageList() []enums.Luggage {
-	return []enums.Luggage{enums.SmallBag, enums.Cargo20kg}
+func getLuggageList() []models.LuggageItem {
+	return []models.LuggageItem{{Type: enums.SmallBag, Quantity: 1}, {Type: enums.Cargo20kg, Quantity: 1}}
 }
 
 func getLuggageString() string {
-	return `["SmallBag","Cargo20kg"]`
+	return `[{"type":"SmallBag","quantity":1},{"type":"Cargo20kg","quantity":1}]`
+}
+
+// A month from now, the same for every booking of a test run
+var departure = time.Now().AddDate(0, 1, 0).UTC().Truncate(time.Second)
+
+func getDeparture() *time.Time {
+	departureAt := departure
+	return &departureAt
 }
 
 func getBookingEntities() []entities.BookingEntity {
@@ -115,23 +183,33 @@ func getBookingEntities() []entities.BookingEntity {
 			UserID:      2,
 			FlightCode:  "FR788",
 			FlightClass: 1,
+			DepartureAt: getDeparture(),
 			CreatedAt:   time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
 			Passengers:  getPassengerEntities(),
 			Seats:       getSeatEntities(),
-			Luggage:     getLuggageString(),
 		},
 		{
-			ID:         1,
-			UserID:     4,
-			FlightCode: "FR789",
-			CreatedAt:  time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
-			Passengers: getPassengerEntities(),
-			Seats:      getSeatEntities(),
-			Luggage:    getLuggageString(),
+			ID:          1,
+			UserID:      4,
+			FlightCode:  "FR789",
+			DepartureAt: getDeparture(),
+			CreatedAt:   time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+			Passengers:  getPassengerEntities(),
+			Seats:       getSeatEntities(),
 		},
 	}
 }
 
+func getPayment() models.Payment {
+	return models.Payment{
+		Token:     "pm_1PqR2sT3uV4wX5yZ",
+		FirstName: "John",
+		LastName:  "Doe",
+		Amount:    149.99,
+		Currency:  "EUR",
+	}
+}
+
 func getBookings() []models.Booking {
 	return []models.Booking{
 		{
@@ -139,7 +217,7 @@ func getBookings() []models.Booking {
 			UserID:      2,
 			FlightCode:  "FR788",
 			FlightClass: 1,
-			Luggage:     getLuggageList(),
+			DepartureAt: getDeparture(),
 			Seats:       getSeats(),
 			Passengers:  getPassengers(),
 		},
@@ -148,26 +226,32 @@ func getBookings() []models.Booking {
 			UserID:      4,
 			FlightCode:  "FR789",
 			FlightClass: 1,
-			Luggage:     getLuggageList(),
+			DepartureAt: getDeparture(),
 			Seats:       getSeats(),
 			Passengers:  getPassengers(),
 		},
 	}
 }
 
+func getDefaultSearchCriteria() models.BookingSearchCriteria {
+	return models.BookingSearchCriteria{SortBy: "created_at", SortOrder: "desc", Limit: services.DefaultSearchLimit}
+}
+
 // Service Unit Tests
 func TestGetByUserIDWithBookingsReturnsBookings(t *testing.T) {
 	// Arrange
 	mockRepo, bookingService := setupBookingService()
 	userID := 2
 	expectedBookings := []models.Booking{getBookings()[0]}
-	mockRepo.On("GetByUserID", userID).Return([]entities.BookingEntity{getBookingEntities()[0]}, nil)
+	mockRepo.On("GetByUserID", userID, getDefaultSearchCriteria()).Return([]entities.BookingEntity{getBookingEntities()[0]}, int64(1), "", nil)
 
 	// Act
-	bookings := bookingService.GetByUserID(userID)
+	result, err := bookingService.GetByUserID(userID, models.BookingSearchCriteria{})
 
 	// Assert
-	assert.Equal(t, expectedBookings, bookings)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedBookings, result.Bookings)
+	assert.Equal(t, int64(1), result.TotalCount)
 }
 
 func TestGetByUserIDWithoutBookingsReturnsNoBookings(t *testing.T) {
@@ -175,130 +259,1125 @@ func TestGetByUserIDWithoutBookingsReturnsNoBookings(t *testing.T) {
 	mockRepo, bookingService := setupBookingService()
 	userID := 2
 	expectedBookings := []models.Booking{}
-	mockRepo.On("GetByUserID", userID).Return([]entities.BookingEntity{}, nil)
+	mockRepo.On("GetByUserID", userID, getDefaultSearchCriteria()).Return([]entities.BookingEntity{}, int64(0), "", nil)
 
 	// Act
-	bookings := bookingService.GetByUserID(userID)
-
-	if bookings == nil {
-		bookings = []models.Booking{}
-	}
+	result, err := bookingService.GetByUserID(userID, models.BookingSearchCriteria{})
 
 	// Assert
-	assert.Equal(t, expectedBookings, bookings)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedBookings, result.Bookings)
 }
 
-// TODO: Fix this is probably failing because of the RabbitMQ implementation
-// This could be made such that it is modular, for example using a MessagingService and mocking that
-// Or I could mock the RabbitMQ as a whole, and make it work for this unit test
-// func TestCreateNonExistingBookingReturnsCreatedBooking(t *testing.T) {
-// 	// Arrange
-// 	mockRepo, bookingService := setupBookingService()
-// 	booking := getBookings()[0]
-// 	bookingEntity := getBookingEntities()[0]
-// 	// Mock exists method
-// 	mockRepo.On("GetAll").Return([]entities.BookingEntity{})
-// 	mockRepo.On("Create", mock.MatchedBy(func(u entities.BookingEntity) bool {
-// 		return u.ID == bookingEntity.ID // Ignore CreatedAt difference
-// 	})).Return(bookingEntity)
+func TestCreateNonExistingBookingReturnsCreatedBooking(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	bookingEntity := getBookingEntities()[0]
+	// Mock exists method
+	mockRepo.On("Exists", bookingEntity.ID).Return(false, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(u entities.BookingEntity) bool {
+		return u.ID == bookingEntity.ID && u.Status == string(enums.Pending) // Ignore CreatedAt difference
+	})).Return(&bookingEntity, nil)
+	mockRepo.On("AddOutboxMessage", "booking.created", mock.Anything).Return(nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.Pending, enums.AwaitingPayment, "Payment requested").Return(nil)
 
-// 	// Act
-// 	postBooking, err := bookingService.Create(booking)
+	// Act
+	postBooking, err := bookingService.Create(booking)
 
-// 	// Assert
-// 	assert.NoError(t, err)
-// 	assert.Equal(t, booking, *postBooking)
-// }
+	// Assert
+	assert.NoError(t, err)
+	// The payment is only published, it is not stored with the booking
+	booking.Status = enums.AwaitingPayment
+	booking.Payment = models.Payment{}
+	booking.Price = getPrice()
+	assert.Equal(t, booking, *postBooking)
+	mockRepo.AssertExpectations(t)
+}
 
-func TestCreateExistingBookingThrowsException(t *testing.T) {
+func TestCreateBookingPublishesPaymentToken(t *testing.T) {
 	// Arrange
 	mockRepo, bookingService := setupBookingService()
 	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("Exists", bookingEntity.ID).Return(false, nil)
+	mockRepo.On("Create", mock.Anything).Return(&bookingEntity, nil)
+	var payload []byte
+	mockRepo.On("AddOutboxMessage", "booking.created", mock.MatchedBy(func(body []byte) bool {
+		payload = body
+		return true
+	})).Return(nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.Pending, enums.AwaitingPayment, "Payment requested").Return(nil)
+
+	// Act
+	_, err := bookingService.Create(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	var paymentRequest models.PaymentRequest
+	assert.NoError(t, json.Unmarshal(payload, &paymentRequest))
+	assert.Equal(t, getPayment().Token, paymentRequest.Payment.Token)
+	assert.Equal(t, bookingEntity.ID, paymentRequest.BookingID)
+}
+
+func TestCreateBookingOverridesAmountSentByClient(t *testing.T) {
+	// Arrange
+	mockPricingService := new(mock_repositories.MockPricingService)
+	mockRepo, bookingService := setupBookingServiceWithPricingService(mockPricingService)
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.Payment.Amount = 0.01
+	booking.Payment.Currency = "USD"
 	bookingEntity := getBookingEntities()[0]
-	mockRepo.On("GetAll").Return([]entities.BookingEntity{bookingEntity})
-	mockRepo.On("Create", &bookingEntity).Return(nil, nil)
+	mockPricingService.On("Calculate", booking).Return(getPrice(), nil)
+	mockRepo.On("Exists", bookingEntity.ID).Return(false, nil)
+	mockRepo.On("Create", mock.Anything).Return(&bookingEntity, nil)
+	var payload []byte
+	mockRepo.On("AddOutboxMessage", "booking.created", mock.MatchedBy(func(body []byte) bool {
+		payload = body
+		return true
+	})).Return(nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.Pending, enums.AwaitingPayment, "Payment requested").Return(nil)
 
 	// Act
 	createdBooking, err := bookingService.Create(booking)
 
 	// Assert
-	assert.Error(t, err)
-	assert.Equal(t, errors.NewBookingExistsError(booking.ID, 409), err)
+	assert.NoError(t, err)
+	assert.Equal(t, getPrice(), createdBooking.Price)
+
+	var paymentRequest models.PaymentRequest
+	assert.NoError(t, json.Unmarshal(payload, &paymentRequest))
+	assert.Equal(t, 498.0, paymentRequest.Payment.Amount)
+	assert.Equal(t, "EUR", paymentRequest.Payment.Currency)
+	assert.Equal(t, *getPrice(), paymentRequest.Price)
+}
+
+func TestCreateBookingWithQuoteChargesQuotedPrice(t *testing.T) {
+	// Arrange
+	mockPricingService := new(mock_repositories.MockPricingService)
+	mockRepo, bookingService := setupBookingServiceWithPricingService(mockPricingService)
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.QuoteID = "quote-1"
+	bookingEntity := getBookingEntities()[0]
+	mockPricingService.On("PriceFromQuote", "quote-1", booking).Return(getPrice(), nil)
+	mockRepo.On("Exists", bookingEntity.ID).Return(false, nil)
+	mockRepo.On("Create", mock.Anything).Return(&bookingEntity, nil)
+	mockRepo.On("AddOutboxMessage", "booking.created", mock.Anything).Return(nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.Pending, enums.AwaitingPayment, "Payment requested").Return(nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, getPrice(), createdBooking.Price)
+	mockPricingService.AssertNotCalled(t, "Calculate", mock.Anything)
+}
+
+func TestCreateBookingWithExpiredQuoteThrowsQuoteExpiredError(t *testing.T) {
+	// Arrange
+	mockPricingService := new(mock_repositories.MockPricingService)
+	mockRepo, bookingService := setupBookingServiceWithPricingService(mockPricingService)
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.QuoteID = "quote-1"
+	quoteError := errors.NewQuoteExpiredError(time.Now().Add(-time.Minute), 410)
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+	mockPricingService.On("PriceFromQuote", "quote-1", booking).Return(nil, quoteError)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.Equal(t, quoteError, err)
 	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
 }
 
-func TestDeleteExistingBookingReturnsTrue(t *testing.T) {
+func TestQuoteBookingWithInvalidSeatsThrowsInvalidSeatSelectionError(t *testing.T) {
+	// Arrange
+	mockPricingService := new(mock_repositories.MockPricingService)
+	_, bookingService := setupBookingServiceWithPricingService(mockPricingService)
+	booking := getBookings()[0]
+	booking.Seats = []models.Seat{{Row: 40, Column: "K"}}
+
+	// Act
+	quote, err := bookingService.Quote(booking)
+
+	// Assert
+	assert.IsType(t, &errors.InvalidSeatSelectionError{}, err)
+	assert.Nil(t, quote)
+	mockPricingService.AssertNotCalled(t, "Quote", mock.Anything)
+}
+
+func TestCreateBookingWithoutPassengersThrowsInvalidBookingError(t *testing.T) {
 	// Arrange
 	mockRepo, bookingService := setupBookingService()
-	bookingEntity := getBookingEntities()[0]
-	mockRepo.On("GetAll").Return([]entities.BookingEntity{bookingEntity})
-	mockRepo.On("DeleteByBookingID", bookingEntity.ID).Return(true, nil)
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.Passengers = nil
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
 
 	// Act
-	isDeleted, err := bookingService.DeleteByBookingID(bookingEntity.ID)
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	expectedViolations := []string{"a booking needs at least one passenger", fmt.Sprintf("%d seats were selected for 0 passengers", len(booking.Seats))}
+	assert.Equal(t, errors.NewInvalidBookingError(expectedViolations, 400), err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBookingWithSameSeatTwiceThrowsInvalidBookingError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.Seats = []models.Seat{booking.Seats[0], booking.Seats[0]}
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	expectedViolation := fmt.Sprintf("seat %d%s was selected more than once", booking.Seats[0].Row, booking.Seats[0].Column)
+	assert.Equal(t, errors.NewInvalidBookingError([]string{expectedViolation}, 400), err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBookingWithMoreSeatsThanPassengersThrowsInvalidBookingError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.Passengers = booking.Passengers[:1]
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	expectedViolations := []string{fmt.Sprintf("%d seats were selected for 1 passengers", len(booking.Seats))}
+	assert.Equal(t, errors.NewInvalidBookingError(expectedViolations, 400), err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestQuoteBookingOfDepartedFlightThrowsInvalidBookingError(t *testing.T) {
+	// Arrange
+	mockSeatService := new(mock_repositories.MockSeatService)
+	mockSeatService.On("GetByFlightCode", mock.Anything).Return(getSeatMap(), nil)
+	mockPricingService := new(mock_repositories.MockPricingService)
+	_, bookingService := setupBookingServiceWith(mockSeatService, mockPricingService)
+	booking := getBookings()[0]
+	departureAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	mockPricingService.On("GetDeparture", "FR788", enums.Business).Return(&departureAt, nil)
+
+	// Act
+	quote, err := bookingService.Quote(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidBookingError([]string{"flight FR788 departed at 2025-01-01T09:00:00Z"}, 400), err)
+	assert.Nil(t, quote)
+	mockPricingService.AssertNotCalled(t, "Quote", mock.Anything)
+}
+
+func TestQuoteBookingIgnoresDepartureSentByClient(t *testing.T) {
+	// Arrange
+	mockPricingService := new(mock_repositories.MockPricingService)
+	_, bookingService := setupBookingServiceWithPricingService(mockPricingService)
+	booking := getBookings()[0]
+	// A departure in 1900 would price every passenger as an infant
+	departureAt := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	booking.DepartureAt = &departureAt
+	quotedBooking := getBookings()[0]
+	expectedQuote := &models.Quote{QuoteID: "quote-1", Price: *getPrice()}
+	mockPricingService.On("Quote", quotedBooking).Return(expectedQuote, nil)
+
+	// Act
+	quote, err := bookingService.Quote(booking)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.True(t, isDeleted)
+	assert.Equal(t, expectedQuote, quote)
 }
 
-func TestDeleteByNonExistingBookingThrowsException(t *testing.T) {
+func TestCreateBookingOfFlightWithUnknownDepartureThrowsDepartureNotFoundError(t *testing.T) {
+	// Arrange
+	mockSeatService := new(mock_repositories.MockSeatService)
+	mockSeatService.On("GetByFlightCode", mock.Anything).Return(getSeatMap(), nil)
+	mockPricingService := new(mock_repositories.MockPricingService)
+	mockRepo, bookingService := setupBookingServiceWith(mockSeatService, mockPricingService)
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	departureError := errors.NewDepartureNotFoundError("FR788", 404)
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+	mockPricingService.On("GetDeparture", "FR788", enums.Business).Return(nil, departureError)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.Equal(t, departureError, err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBookingWithoutFareThrowsFareNotFoundError(t *testing.T) {
+	// Arrange
+	mockPricingService := new(mock_repositories.MockPricingService)
+	mockRepo, bookingService := setupBookingServiceWithPricingService(mockPricingService)
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	fareError := errors.NewFareNotFoundError(booking.FlightCode, booking.FlightClass, 404)
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+	mockPricingService.On("Calculate", booking).Return(nil, fareError)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.Equal(t, fareError, err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBookingWithoutPaymentTokenThrowsInvalidPaymentError(t *testing.T) {
 	// Arrange
 	mockRepo, bookingService := setupBookingService()
-	bookingEntity := getBookingEntities()[0]
-	mockRepo.On("GetAll").Return([]entities.BookingEntity{})
-	mockRepo.On("DeleteByBookingID", bookingEntity.ID).Return(false, nil)
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.Payment.Token = ""
 
 	// Act
-	isDeleted, err := bookingService.DeleteByBookingID(bookingEntity.ID)
+	createdBooking, err := bookingService.Create(booking)
 
 	// Assert
-	assert.Error(t, err)
-	assert.Equal(t, errors.NewBookingNotFoundError(bookingEntity.ID, 404), err)
-	assert.False(t, isDeleted)
+	assert.IsType(t, &errors.InvalidPaymentError{}, err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
 }
 
-func TestUpdateByExistingBookingReturnsUpdatedBooking(t *testing.T) {
+func TestCreateBookingWithFailingOutboxThrowsException(t *testing.T) {
 	// Arrange
 	mockRepo, bookingService := setupBookingService()
 	booking := getBookings()[0]
+	booking.Payment = getPayment()
 	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("Exists", bookingEntity.ID).Return(false, nil)
+	mockRepo.On("Create", mock.Anything).Return(&bookingEntity, nil)
+	mockRepo.On("AddOutboxMessage", "booking.created", mock.Anything).Return(fmt.Errorf("database unavailable"))
 
-	mockRepo.On("GetAll").Return(getBookingEntities())
-	mockRepo.On("Update", mock.MatchedBy(func(u entities.BookingEntity) bool {
-		return u.ID == bookingEntity.ID
-	})).Return(bookingEntity)
+	// Act
+	postBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewBookingCreateError(booking.ID, 500), err)
+	assert.Nil(t, postBooking)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateBookingWithAlreadyBookedSeatsThrowsSeatAlreadyBookedError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	seatBookedError := errors.NewSeatAlreadyBookedError(booking.FlightCode, []models.Seat{{Row: 1, Column: "A"}}, 409)
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+	mockRepo.On("Create", mock.Anything).Return(nil, seatBookedError)
 
 	// Act
-	updateBooking, err := bookingService.Update(booking)
+	createdBooking, err := bookingService.Create(booking)
 
 	// Assert
-	assert.NoError(t, err)
-	assert.Equal(t, booking.ID, updateBooking.ID)
-	assert.Equal(t, booking.UserID, updateBooking.UserID)
-	assert.Equal(t, booking.FlightCode, updateBooking.FlightCode)
-	assert.Equal(t, booking.FlightClass, updateBooking.FlightClass)
-	assert.Equal(t, booking.Luggage, updateBooking.Luggage)
-	assert.Equal(t, booking.Passengers, updateBooking.Passengers)
-	assert.Equal(t, booking.Seats, updateBooking.Seats)
+	assert.Equal(t, seatBookedError, err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "AddOutboxMessage", mock.Anything, mock.Anything)
 }
 
-func TestUpdateByNonExistingBookingReturnsUpdatedBooking(t *testing.T) {
+func TestCreateEconomyBookingWithBusinessSeatsThrowsInvalidSeatSelectionError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.FlightClass = enums.Economy
+	booking.Seats = []models.Seat{{Row: 1, Column: "A"}, {Row: 2, Column: "A"}}
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	expectedSeats := []models.Seat{{Row: 1, Column: "A", Cabin: enums.Business}}
+	assert.Equal(t, errors.NewInvalidSeatSelectionError(booking.FlightCode, enums.Economy, expectedSeats, 400), err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBookingWithSeatsNotOnSeatMapThrowsInvalidSeatSelectionError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.Seats = []models.Seat{{Row: 40, Column: "K"}}
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.IsType(t, &errors.InvalidSeatSelectionError{}, err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBookingWithBlockedSeatThrowsInvalidSeatSelectionError(t *testing.T) {
+	// Arrange
+	mockSeatService := new(mock_repositories.MockSeatService)
+	seatMap := getSeatMap()
+	seatMap[1].Available = false
+	seatMap[1].Blocked = true
+	mockSeatService.On("GetByFlightCode", mock.Anything).Return(seatMap, nil)
+	mockRepo, bookingService := setupBookingServiceWithSeatService(mockSeatService)
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	expectedSeats := []models.Seat{{Row: 1, Column: "B", Cabin: enums.Business}}
+	assert.Equal(t, errors.NewInvalidSeatSelectionError(booking.FlightCode, booking.FlightClass, expectedSeats, 400), err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBookingAboveLuggageAllowanceThrowsInvalidLuggageError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.FlightClass = enums.Economy
+	booking.Seats = nil
+	booking.Passengers[0].Luggage = []models.LuggageItem{
+		{Type: enums.Cargo20kg, Quantity: 2},
+		{Type: enums.CabinBag, Quantity: 1, WeightKg: 12},
+		{Type: enums.Cargo20kg, Quantity: 1},
+	}
+	booking.Passengers[1].Luggage = []models.LuggageItem{{Type: enums.Cargo30kg, Quantity: 0}}
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	expectedViolations := []string{
+		"John Doe: CabinBag weighs 12 kg, the limit is 10 kg",
+		"John Doe: 3 x Cargo20kg exceeds the maximum of 2 in Economy",
+		"Jane Doe: the quantity of Cargo30kg must be at least 1",
+	}
+	assert.Equal(t, errors.NewInvalidLuggageError(enums.Economy, expectedViolations, 400), err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBusinessBookingWithIncludedLuggageReturnsCreatedBooking(t *testing.T) {
 	// Arrange
 	mockRepo, bookingService := setupBookingService()
 	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	// Above the maximum of Economy, but within the maximum of Business
+	booking.Passengers[0].Luggage = []models.LuggageItem{{Type: enums.CabinBag, Quantity: 2}, {Type: enums.Cargo30kg, Quantity: 3, WeightKg: 30}}
 	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+	mockRepo.On("Create", mock.Anything).Return(&bookingEntity, nil)
+	mockRepo.On("AddOutboxMessage", "booking.created", mock.Anything).Return(nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.Pending, enums.AwaitingPayment, "Payment requested").Return(nil)
 
-	mockRepo.On("GetAll").Return([]entities.BookingEntity{})
-	mockRepo.On("Update", mock.MatchedBy(func(u entities.BookingEntity) bool {
-		return u.ID == bookingEntity.ID
-	})).Return(bookingEntity)
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, createdBooking)
+}
+
+func TestQuoteBookingWithUnknownLuggageThrowsInvalidLuggageError(t *testing.T) {
+	// Arrange
+	mockPricingService := new(mock_repositories.MockPricingService)
+	_, bookingService := setupBookingServiceWithPricingService(mockPricingService)
+	booking := getBookings()[0]
+	booking.Passengers[0].Luggage = []models.LuggageItem{{Type: "Surfboard", Quantity: 1}}
 
 	// Act
-	updateBooking, err := bookingService.Update(booking)
+	quote, err := bookingService.Quote(booking)
 
 	// Assert
-	assert.Error(t, err)
-	assert.Equal(t, errors.NewBookingNotFoundError(booking.ID, 404), err)
-	assert.Nil(t, updateBooking)
+	assert.Equal(t, errors.NewInvalidLuggageError(enums.Business, []string{`John Doe: unknown luggage item "Surfboard"`}, 400), err)
+	assert.Nil(t, quote)
+	mockPricingService.AssertNotCalled(t, "Quote", mock.Anything)
+}
+
+func TestCreateBookingWithUnavailableAncillariesThrowsInvalidAncillaryError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.Passengers[0].Ancillaries = []string{"VegetarianMeal", "KosherMeal"}
+	booking.Passengers[1].Ancillaries = []string{"LoungeAccess", "PriorityBoarding"}
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	expectedViolations := []string{
+		"John Doe: only one Meal can be added",
+		"Jane Doe: LoungeAccess is not available in Business",
+	}
+	assert.Equal(t, errors.NewInvalidAncillaryError(enums.Business, expectedViolations, 400), err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateBookingStoresAncillariesPerPassenger(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	booking.Passengers[0].Ancillaries = []string{"VegetarianMeal", "PriorityBoarding"}
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("Exists", booking.ID).Return(false, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(entity entities.BookingEntity) bool {
+		return entity.Passengers[0].Ancillaries == `["VegetarianMeal","PriorityBoarding"]` && entity.Passengers[1].Ancillaries == "[]"
+	})).Return(&bookingEntity, nil)
+	mockRepo.On("AddOutboxMessage", "booking.created", mock.Anything).Return(nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.Pending, enums.AwaitingPayment, "Payment requested").Return(nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, createdBooking)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateExistingBookingThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("Exists", bookingEntity.ID).Return(true, nil)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, errors.NewBookingExistsError(booking.ID, 409), err)
+	assert.Nil(t, createdBooking)
+}
+
+func TestDeleteExistingBookingReturnsTrue(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("DeleteByBookingID", bookingEntity.ID).Return(nil)
+
+	// Act
+	isDeleted, err := bookingService.DeleteByBookingID(bookingEntity.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, isDeleted)
+}
+
+func TestDeleteByNonExistingBookingThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("DeleteByBookingID", bookingEntity.ID).Return(errors.NewBookingNotFoundError(bookingEntity.ID, 404))
+
+	// Act
+	isDeleted, err := bookingService.DeleteByBookingID(bookingEntity.ID)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, errors.NewBookingNotFoundError(bookingEntity.ID, 404), err)
+	assert.False(t, isDeleted)
+}
+
+func TestCancelConfirmedBookingUpdatesStatusWithReason(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	bookingEntity.Status = string(enums.Confirmed)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.Confirmed, enums.Cancelled, "Change of plans").Return(nil)
+
+	// Act
+	err := bookingService.Cancel(bookingEntity.ID, "Change of plans")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "DeleteByBookingID", mock.Anything)
+}
+
+func TestCancelWithoutReasonUsesDefaultReason(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	bookingEntity.Status = string(enums.AwaitingPayment)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.AwaitingPayment, enums.Cancelled, "Booking cancelled").Return(nil)
+
+	// Act
+	err := bookingService.Cancel(bookingEntity.ID, "")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelCancelledBookingThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	bookingEntity.Status = string(enums.Cancelled)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	err := bookingService.Cancel(bookingEntity.ID, "Change of plans")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidStatusTransitionError(bookingEntity.ID, enums.Cancelled, enums.Cancelled, 409), err)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExpireUnpaidExpiresUnpaidBookingsAndSkipsPaidOnes(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	createdBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	unpaidEntity := getBookingEntities()[0]
+	unpaidEntity.ID = 1
+	unpaidEntity.Status = string(enums.PaymentFailed)
+	// The second booking was paid after the unpaid bookings were read
+	paidEntity := getBookingEntities()[1]
+	paidEntity.ID = 2
+	paidEntity.Status = string(enums.Confirmed)
+	mockRepo.On("GetUnpaidIDs", createdBefore).Return([]int{1, 2}, nil)
+	mockRepo.On("GetByID", 1).Return(&unpaidEntity, nil)
+	mockRepo.On("GetByID", 2).Return(&paidEntity, nil)
+	mockRepo.On("UpdateStatus", 1, enums.PaymentFailed, enums.Expired, "Payment not received in time").Return(nil)
+
+	// Act
+	expired, err := bookingService.ExpireUnpaid(createdBefore)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", 2, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPurgeReturnsPurgedCount(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	deletedBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("Purge", deletedBefore).Return(int64(2), nil)
+
+	// Act
+	purged, err := bookingService.Purge(deletedBefore)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+}
+
+func TestUpdateByExistingBookingReturnsUpdatedBooking(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+	mockRepo.On("Update", mock.MatchedBy(func(u entities.BookingEntity) bool {
+		return u.ID == bookingEntity.ID
+	})).Return(&bookingEntity, nil)
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, booking.ID, updateBooking.ID)
+	assert.Equal(t, booking.UserID, updateBooking.UserID)
+	assert.Equal(t, booking.FlightCode, updateBooking.FlightCode)
+	assert.Equal(t, booking.FlightClass, updateBooking.FlightClass)
+	assert.Equal(t, booking.Passengers, updateBooking.Passengers)
+	assert.Equal(t, booking.Seats, updateBooking.Seats)
+}
+
+func TestUpdateByNonExistingBookingReturnsUpdatedBooking(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	mockRepo.On("GetByID", booking.ID).Return(nil, errors.NewBookingNotFoundError(booking.ID, 404))
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, errors.NewBookingNotFoundError(booking.ID, 404), err)
+	assert.Nil(t, updateBooking)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateEconomyBookingWithBusinessSeatsThrowsInvalidSeatSelectionError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.FlightClass = enums.Economy
+	booking.Seats = []models.Seat{{Row: 1, Column: "A"}, {Row: 2, Column: "A"}}
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.FlightClass = int(enums.Economy)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	expectedSeats := []models.Seat{{Row: 1, Column: "A", Cabin: enums.Business}}
+	assert.Equal(t, errors.NewInvalidSeatSelectionError(booking.FlightCode, enums.Economy, expectedSeats, 400), err)
+	assert.Nil(t, updateBooking)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateBookingWithOtherFlightClassThrowsInvalidBookingError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.FlightClass = enums.Economy
+	booking.Seats = nil
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidBookingError([]string{"the flight class cannot be changed"}, 400), err)
+	assert.Nil(t, updateBooking)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateBookingChangingPriceThrowsInvalidBookingError(t *testing.T) {
+	// Arrange
+	mockPricingService := new(mock_repositories.MockPricingService)
+	mockRepo, bookingService := setupBookingServiceWithPricingService(mockPricingService)
+	booking := getBookings()[0]
+	booking.Passengers[0].Luggage = []models.LuggageItem{{Type: enums.Cargo20kg, Quantity: 1}}
+	bookingEntity := getBookingEntities()[0]
+	currentBooking := (&converter.BookingConverter{}).ConvertBookingEntityToBooking(bookingEntity)
+	changedPrice := getPrice()
+	changedPrice.TotalCents += 3500
+	mockPricingService.On("Calculate", currentBooking).Return(getPrice(), nil)
+	mockPricingService.On("Calculate", booking).Return(changedPrice, nil)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidBookingError([]string{"the changes would change the price of the booking from 498.00 to 533.00 EUR"}, 400), err)
+	assert.Nil(t, updateBooking)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateBookingAboveLuggageAllowanceThrowsInvalidLuggageError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Passengers[0].Luggage = []models.LuggageItem{{Type: "Surfboard", Quantity: 1}}
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidLuggageError(enums.Business, []string{`John Doe: unknown luggage item "Surfboard"`}, 400), err)
+	assert.Nil(t, updateBooking)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateBookingWithUnavailableAncillariesThrowsInvalidAncillaryError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Passengers[1].Ancillaries = []string{"LoungeAccess"}
+	bookingEntity := getBookingEntities()[0]
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidAncillaryError(enums.Business, []string{"Jane Doe: LoungeAccess is not available in Business"}, 400), err)
+	assert.Nil(t, updateBooking)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateBoardedBookingThrowsBookingNotModifiableError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.Status = string(enums.Boarded)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotModifiableError(booking.ID, enums.Boarded, 409), err)
+	assert.Nil(t, updateBooking)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateCancelledBookingThrowsBookingNotModifiableError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.Status = string(enums.Cancelled)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	updateBooking, err := bookingService.Update(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotModifiableError(booking.ID, enums.Cancelled, 409), err)
+	assert.Nil(t, updateBooking)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestGetByExistingIDReturnsBooking(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	expectedBooking := getBookings()[0]
+	expectedBooking.ID = 1
+	mockRepo.On("GetByID", 1).Return(&bookingEntity, nil)
+
+	// Act
+	booking, err := bookingService.GetByID(1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedBooking, booking)
+}
+
+func TestGetByNonExistingIDReturnsNotFoundError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	mockRepo.On("GetByID", 999).Return(nil, errors.NewBookingNotFoundError(999, 404))
+
+	// Act
+	booking, err := bookingService.GetByID(999)
+
+	// Assert
+	assert.Nil(t, booking)
+	assert.Equal(t, errors.NewBookingNotFoundError(999, 404), err)
+}
+
+func TestGetByIDWithUnavailableDatabaseThrowsDatabaseUnavailableError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	databaseError := errors.NewDatabaseUnavailableError(fmt.Errorf("connection refused"), 503)
+	mockRepo.On("GetByID", 1).Return(nil, databaseError)
+
+	// Act
+	booking, err := bookingService.GetByID(1)
+
+	// Assert
+	assert.Nil(t, booking)
+	assert.Equal(t, databaseError, err)
+}
+
+func TestCreateBookingWithUnavailableDatabaseThrowsDatabaseUnavailableError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	booking := getBookings()[0]
+	booking.Payment = getPayment()
+	databaseError := errors.NewDatabaseUnavailableError(fmt.Errorf("connection refused"), 503)
+	mockRepo.On("Exists", booking.ID).Return(false, databaseError)
+
+	// Act
+	createdBooking, err := bookingService.Create(booking)
+
+	// Assert
+	assert.Equal(t, databaseError, err)
+	assert.Nil(t, createdBooking)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestUpdateStatusWithAllowedTransitionUpdatesStatus(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	bookingEntity.Status = string(enums.AwaitingPayment)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+	mockRepo.On("UpdateStatus", bookingEntity.ID, enums.AwaitingPayment, enums.Confirmed, "Payment succeeded").Return(nil)
+	mockRepo.On("AddOutboxMessage", "booking.confirmed", mock.Anything).Return(nil)
+
+	// Act
+	err := bookingService.UpdateStatus(bookingEntity.ID, enums.Confirmed, "Payment succeeded")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateStatusWithRejectedTransitionThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	bookingEntity.Status = string(enums.Cancelled)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	err := bookingService.UpdateStatus(bookingEntity.ID, enums.Confirmed, "Payment succeeded")
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidStatusTransitionError(bookingEntity.ID, enums.Cancelled, enums.Confirmed, 409), err)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateStatusOfNonExistingBookingThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	mockRepo.On("GetByID", 999).Return(nil, errors.NewBookingNotFoundError(999, 404))
+
+	// Act
+	err := bookingService.UpdateStatus(999, enums.Confirmed, "Payment succeeded")
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotFoundError(999, 404), err)
+}
+
+func TestConfirmPaymentOfExpiredBookingRequestsRefund(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	bookingEntity.Status = string(enums.Expired)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+	var refundRequest models.RefundRequest
+	mockRepo.On("AddOutboxMessage", "payment.refund_requested", mock.MatchedBy(func(body []byte) bool {
+		return json.Unmarshal(body, &refundRequest) == nil
+	})).Return(nil)
+
+	// Act
+	err := bookingService.ConfirmPayment(bookingEntity.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.RefundRequest{BookingID: 1, Status: enums.Expired, Reason: "Payment received after the booking became Expired"}, refundRequest)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmPaymentOfBookingExpiredWhileConfirmingRequestsRefund(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	awaitingEntity := getBookingEntities()[0]
+	awaitingEntity.ID = 1
+	awaitingEntity.Status = string(enums.AwaitingPayment)
+	expiredEntity := awaitingEntity
+	expiredEntity.Status = string(enums.Expired)
+	mockRepo.On("GetByID", 1).Return(&awaitingEntity, nil).Once()
+	mockRepo.On("GetByID", 1).Return(&expiredEntity, nil).Once()
+	transitionError := errors.NewInvalidStatusTransitionError(1, enums.AwaitingPayment, enums.Confirmed, 409)
+	mockRepo.On("UpdateStatus", 1, enums.AwaitingPayment, enums.Confirmed, "Payment succeeded").Return(transitionError)
+	mockRepo.On("AddOutboxMessage", "payment.refund_requested", mock.Anything).Return(nil)
+
+	// Act
+	err := bookingService.ConfirmPayment(1)
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConfirmPaymentOfConfirmedBookingThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	bookingEntity.Status = string(enums.Confirmed)
+	mockRepo.On("GetByID", bookingEntity.ID).Return(&bookingEntity, nil)
+
+	// Act
+	err := bookingService.ConfirmPayment(bookingEntity.ID)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidStatusTransitionError(bookingEntity.ID, enums.Confirmed, enums.Confirmed, 409), err)
+	mockRepo.AssertNotCalled(t, "AddOutboxMessage", mock.Anything, mock.Anything)
+}
+
+func TestRetryPaymentOfFailedPaymentRequestsPaymentAgain(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	failedEntity := getBookingEntities()[0]
+	failedEntity.ID = 1
+	failedEntity.Status = string(enums.PaymentFailed)
+	retriedEntity := failedEntity
+	retriedEntity.Status = string(enums.AwaitingPayment)
+	mockRepo.On("GetByID", 1).Return(&failedEntity, nil).Once()
+	mockRepo.On("GetByID", 1).Return(&retriedEntity, nil).Once()
+	mockRepo.On("UpdateStatus", 1, enums.PaymentFailed, enums.AwaitingPayment, "Payment retried").Return(nil)
+	var paymentRequest models.PaymentRequest
+	mockRepo.On("AddOutboxMessage", "booking.created", mock.MatchedBy(func(body []byte) bool {
+		return json.Unmarshal(body, &paymentRequest) == nil
+	})).Return(nil)
+	payment := getPayment()
+	payment.Amount = 0.01
+
+	// Act
+	retriedBooking, err := bookingService.RetryPayment(1, payment)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, enums.AwaitingPayment, retriedBooking.Status)
+	assert.Equal(t, 1, paymentRequest.BookingID)
+	assert.Equal(t, getPayment().Token, paymentRequest.Payment.Token)
+	assert.Equal(t, getPrice().Total(), paymentRequest.Payment.Amount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRetryPaymentOfConfirmedBookingThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	bookingEntity.ID = 1
+	bookingEntity.Status = string(enums.Confirmed)
+	mockRepo.On("GetByID", 1).Return(&bookingEntity, nil)
+
+	// Act
+	retriedBooking, err := bookingService.RetryPayment(1, getPayment())
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidStatusTransitionError(1, enums.Confirmed, enums.AwaitingPayment, 409), err)
+	assert.Nil(t, retriedBooking)
+	mockRepo.AssertNotCalled(t, "AddOutboxMessage", mock.Anything, mock.Anything)
+}
+
+func TestRetryPaymentWithoutPaymentTokenThrowsInvalidPaymentError(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+
+	// Act
+	retriedBooking, err := bookingService.RetryPayment(1, models.Payment{})
+
+	// Assert
+	assert.IsType(t, &errors.InvalidPaymentError{}, err)
+	assert.Nil(t, retriedBooking)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+}
+
+func TestGetStatusHistoryOfExistingBookingReturnsStatusChanges(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntity := getBookingEntities()[0]
+	changedAt := time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC)
+	mockRepo.On("Exists", bookingEntity.ID).Return(true, nil)
+	mockRepo.On("GetStatusHistory", bookingEntity.ID).Return([]entities.BookingStatusHistoryEntity{
+		{BookingID: bookingEntity.ID, ToStatus: "Pending", Reason: "Booking created", ChangedAt: changedAt},
+		{BookingID: bookingEntity.ID, FromStatus: "Pending", ToStatus: "Success", Reason: "Payment succeeded", ChangedAt: changedAt},
+	}, nil)
+
+	// Act
+	history, err := bookingService.GetStatusHistory(bookingEntity.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []models.BookingStatusChange{
+		{ToStatus: enums.Pending, Reason: "Booking created", ChangedAt: changedAt},
+		{FromStatus: enums.Pending, ToStatus: enums.Confirmed, Reason: "Payment succeeded", ChangedAt: changedAt},
+	}, history)
+}
+
+func TestGetStatusHistoryOfNonExistingBookingThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	mockRepo.On("Exists", 999).Return(false, nil)
+
+	// Act
+	history, err := bookingService.GetStatusHistory(999)
+
+	// Assert
+	assert.Equal(t, errors.NewBookingNotFoundError(999, 404), err)
+	assert.Nil(t, history)
+	mockRepo.AssertNotCalled(t, "GetStatusHistory", mock.Anything)
+}
+
+func TestSearchBookingsAppliesDefaultsAndReturnsPage(t *testing.T) {
+	// Arrange
+	mockRepo, bookingService := setupBookingService()
+	bookingEntities := getBookingEntities()[:1]
+	expectedCriteria := getDefaultSearchCriteria()
+	expectedCriteria.FlightCode = "FR788"
+	mockRepo.On("Search", expectedCriteria).Return(bookingEntities, int64(42), "next", nil)
+
+	// Act
+	result, err := bookingService.Search(models.BookingSearchCriteria{FlightCode: "FR788"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, getBookings()[:1], result.Bookings)
+	assert.Equal(t, int64(42), result.TotalCount)
+	assert.Equal(t, "next", result.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchBookingsWithInvalidCriteriaReturnsError(t *testing.T) {
+	createdFrom := time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC)
+	createdTo := createdFrom.Add(-time.Hour)
+	testCases := map[string]models.BookingSearchCriteria{
+		"limit too high": {Limit: services.MaxSearchLimit + 1},
+		"negative limit": {Limit: -1},
+		"unknown order":  {SortOrder: "sideways"},
+		"unknown status": {Status: "Lost"},
+		"inverted range": {CreatedFrom: &createdFrom, CreatedTo: &createdTo},
+	}
+
+	for name, criteria := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			mockRepo, bookingService := setupBookingService()
+
+			// Act
+			result, err := bookingService.Search(criteria)
+
+			// Assert
+			assert.Nil(t, result)
+			assert.IsType(t, &errors.InvalidSearchCriteriaError{}, err)
+			mockRepo.AssertNotCalled(t, "Search", mock.Anything)
+		})
+	}
 }
@@ -2,7 +2,6 @@ package converter_test
 
 import (
 	"flyhorizons-bookingservice/models"
-	"flyhorizons-bookingservice/models/enums"
 	entities "flyhorizons-bookingservice/repositories/entity"
 	"flyhorizons-bookingservice/services/converter"
 	"testing"
@@ -25,7 +24,6 @@ func getBookingEntity() entities.BookingEntity {
 		CreatedAt:   time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
 		Passengers:  getPassengerEntities(),
 		Seats:       getSeatEntities(),
-		Luggage:     `["SmallBag","Cargo20kg"]`,
 	}
 }
 
@@ -35,7 +33,6 @@ func getBooking() models.Booking {
 		UserID:      2,
 		FlightCode:  "FR788",
 		FlightClass: 1,
-		Luggage:     []enums.Luggage{enums.SmallBag, enums.Cargo20kg},
 		Seats:       getSeats(),
 		Passengers:  getPassengers(),
 	}
@@ -2,6 +2,7 @@ package converter_test
 
 import (
 	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
 	entities "flyhorizons-bookingservice/repositories/entity"
 	"flyhorizons-bookingservice/services/converter"
 	"testing"
@@ -22,16 +23,31 @@ func getPassengers() []models.Passenger {
 			FullName:       "John Doe",
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			PassportNumber: "1234",
+			Luggage:        getLuggage(),
+			Ancillaries:    []string{"VegetarianMeal", "PriorityBoarding"},
 		},
 		{
 			ID:             2,
 			FullName:       "Jane Doe",
 			DateOfBirth:    time.Date(1986, 8, 8, 2, 30, 0, 0, time.UTC),
 			PassportNumber: "4321",
+			Luggage:        []models.LuggageItem{},
+			Ancillaries:    []string{},
 		},
 	}
 }
 
+func getLuggage() []models.LuggageItem {
+	return []models.LuggageItem{
+		{Type: enums.SmallBag, Quantity: 1},
+		{Type: enums.Cargo20kg, Quantity: 1, WeightKg: 18},
+	}
+}
+
+func getLuggageString() string {
+	return `[{"type":"SmallBag","quantity":1},{"type":"Cargo20kg","quantity":1,"weight_kg":18}]`
+}
+
 func getBookingID() int {
 	return 123 // Example booking ID
 }
@@ -45,6 +61,8 @@ func getExpectedPassengerEntities() []entities.PassengerEntity {
 			FullName:       "John Doe",
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			PassportNumber: "1234",
+			Luggage:        getLuggageString(),
+			Ancillaries:    `["VegetarianMeal","PriorityBoarding"]`,
 		},
 		{
 			ID:             2,
@@ -52,6 +70,8 @@ func getExpectedPassengerEntities() []entities.PassengerEntity {
 			FullName:       "Jane Doe",
 			DateOfBirth:    time.Date(1986, 8, 8, 2, 30, 0, 0, time.UTC),
 			PassportNumber: "4321",
+			Luggage:        "[]",
+			Ancillaries:    "[]",
 		},
 	}
 }
@@ -64,6 +84,8 @@ func getPassengerEntities() []entities.PassengerEntity {
 			FullName:       "John Doe",
 			DateOfBirth:    time.Date(1985, 7, 9, 1, 0, 0, 0, time.UTC),
 			PassportNumber: "1234",
+			Luggage:        getLuggageString(),
+			Ancillaries:    `["VegetarianMeal","PriorityBoarding"]`,
 		},
 		{
 			ID:             2,
@@ -71,6 +93,8 @@ func getPassengerEntities() []entities.PassengerEntity {
 			FullName:       "Jane Doe",
 			DateOfBirth:    time.Date(1986, 8, 8, 2, 30, 0, 0, time.UTC),
 			PassportNumber: "4321",
+			Luggage:        "[]",
+			Ancillaries:    "[]",
 		},
 	}
 }
@@ -103,3 +127,17 @@ func TestConvertPassengerEntitiesToPassengers(t *testing.T) {
 
 	assert.Equal(t, expectedPassengers, passengers)
 }
+
+func TestConvertPassengerEntitiesToPassengersLeavesOutUnknownLuggage(t *testing.T) {
+	// Arrange
+	passengerConverter := setupPassengerConverter()
+	passengerEntities := []entities.PassengerEntity{
+		{ID: 1, FullName: "John Doe", Luggage: `[{"type":"Surfboard","quantity":1},{"type":"CabinBag","quantity":1}]`},
+	}
+
+	// Act
+	passengers := passengerConverter.ConvertPassengerEntitiesToPassengers(passengerEntities)
+
+	// Assert
+	assert.Equal(t, []models.LuggageItem{{Type: enums.CabinBag, Quantity: 1}}, passengers[0].Luggage)
+}
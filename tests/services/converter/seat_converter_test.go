@@ -2,6 +2,7 @@ package converter_test
 
 import (
 	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
 	entities "flyhorizons-bookingservice/repositories/entity"
 	"flyhorizons-bookingservice/services/converter"
 	"testing"
@@ -62,20 +63,19 @@ func getSeatEntities() []entities.SeatEntity {
 	}
 }
 
-func getSeatOptionEntities() []entities.SeatOptionEntity {
-	return []entities.SeatOptionEntity{
-		{
-			ID:     1,
-			Row:    1,
-			Column: "A",
-			Status: true,
-		},
-		{
-			ID:     2,
-			Row:    1,
-			Column: "B",
-			Status: true,
+func getAircraftConfigurationEntity() entities.AircraftConfigurationEntity {
+	return entities.AircraftConfigurationEntity{
+		ID:   1,
+		Name: "Test 6J 6Y",
+		Cabins: []entities.AircraftCabinEntity{
+			{ID: 2, ConfigurationID: 1, FlightClass: 0, FirstRow: 2, LastRow: 2, Columns: "ABC-DEF"},
+			{ID: 1, ConfigurationID: 1, FlightClass: 1, FirstRow: 1, LastRow: 1, Columns: "A-B"},
 		},
+		ExitRows:              "[2]",
+		ExtraLegroomRows:      "[2]",
+		RestrictedReclineRows: "[1]",
+		BassinetSeats:         `["1A"]`,
+		BlockedSeats:          `["2C"]`,
 	}
 }
 
@@ -106,14 +106,25 @@ func TestConvertSeatEntitiesToSeats(t *testing.T) {
 	assert.Equal(t, expectedSeats, seats)
 }
 
-func TestConverSeatOptionEntitiesToSeats(t *testing.T) {
+func TestConvertAircraftConfigurationEntityToSeats(t *testing.T) {
 	// Arrange
 	seatConverter := setupSeatConverter()
-	seatOptionEntites := getSeatOptionEntities()
-	expectedSeats := getSeats()
+	configuration := getAircraftConfigurationEntity()
+	occupiedSeats := []models.Seat{{Row: 1, Column: "B"}}
+	exitRow := []enums.SeatAttribute{enums.ExitRow, enums.ExtraLegroom}
+	expectedSeats := []models.Seat{
+		{Row: 1, Column: "A", Available: true, Cabin: enums.Business, Position: enums.Window, Attributes: []enums.SeatAttribute{enums.Bassinet, enums.RestrictedRecline}},
+		{Row: 1, Column: "B", Available: false, Cabin: enums.Business, Position: enums.Window, Attributes: []enums.SeatAttribute{enums.RestrictedRecline}},
+		{Row: 2, Column: "A", Available: true, Cabin: enums.Economy, Position: enums.Window, Attributes: exitRow},
+		{Row: 2, Column: "B", Available: true, Cabin: enums.Economy, Position: enums.Middle, Attributes: exitRow},
+		{Row: 2, Column: "C", Available: false, Blocked: true, Cabin: enums.Economy, Position: enums.Aisle, Attributes: exitRow},
+		{Row: 2, Column: "D", Available: true, Cabin: enums.Economy, Position: enums.Aisle, Attributes: exitRow},
+		{Row: 2, Column: "E", Available: true, Cabin: enums.Economy, Position: enums.Middle, Attributes: exitRow},
+		{Row: 2, Column: "F", Available: true, Cabin: enums.Economy, Position: enums.Window, Attributes: exitRow},
+	}
 
 	// Act
-	seats := seatConverter.ConvertSeatOptionEntitiesToSeats(seatOptionEntites)
+	seats := seatConverter.ConvertAircraftConfigurationEntityToSeats(configuration, occupiedSeats)
 
 	// Assert
 	assert.Equal(t, expectedSeats, seats)
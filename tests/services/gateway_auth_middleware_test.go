@@ -0,0 +1,205 @@
+package services_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"flyhorizons-bookingservice/config"
+	"flyhorizons-bookingservice/services/authentication"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestGatewayAuthMiddleware struct {
+}
+
+// Setup
+func setupGatewayAuthRouter(authConfig config.AuthConfig) *gin.Engine {
+	router := gin.Default()
+	middleware := authentication.NewGatewayAuthMiddleware(authConfig)
+	router.GET("/bookings", middleware.GatewayAuthMiddleware(), func(ctx *gin.Context) {
+		userID, _ := ctx.Get("user_id")
+		ctx.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+	return router
+}
+
+func getValidClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":  4,
+		"role": "customer",
+		"iss":  "https://gateway.flyhorizons.com",
+		"aud":  "booking-service",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims, key interface{}) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": encodeBigInt(key.N), "e": encodeBigInt(big.NewInt(int64(key.E)))}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": encodeBigInt(key.X), "y": encodeBigInt(key.Y)}
+}
+
+func sendWithToken(router *gin.Engine, token string) *httptest.ResponseRecorder {
+	httpRequest, _ := http.NewRequest("GET", "/bookings", nil)
+	httpRequest.Header.Set("Authorization", "Bearer "+token)
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httpRequest)
+	return responseRecorder
+}
+
+// Middleware Unit Tests
+func TestGatewayAuthAcceptsHMACTokenSignedWithSecret(t *testing.T) {
+	// Arrange
+	router := setupGatewayAuthRouter(config.AuthConfig{JWTSecret: "secret"})
+	token := signToken(t, jwt.SigningMethodHS256, "", getValidClaims(), []byte("secret"))
+
+	// Act
+	responseRecorder := sendWithToken(router, token)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.JSONEq(t, `{"user_id":4}`, responseRecorder.Body.String())
+}
+
+func TestGatewayAuthAcceptsRS256TokenFromJWKSFile(t *testing.T) {
+	// Arrange
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []interface{}{rsaJWK("rsa-1", &privateKey.PublicKey)}})
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(jwksFile, jwks, 0600))
+	router := setupGatewayAuthRouter(config.AuthConfig{JWKSSource: jwksFile, JWKSRefreshInterval: time.Minute})
+	token := signToken(t, jwt.SigningMethodRS256, "rsa-1", getValidClaims(), privateKey)
+
+	// Act
+	responseRecorder := sendWithToken(router, token)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+}
+
+func TestGatewayAuthRefreshesJWKSOnUnknownKid(t *testing.T) {
+	// Arrange
+	oldKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	newKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var rotated atomic.Bool
+	var fetches atomic.Int32
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		keys := []interface{}{ecJWK("ec-1", &oldKey.PublicKey)}
+		if rotated.Load() {
+			keys = append(keys, ecJWK("ec-2", &newKey.PublicKey))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer jwksServer.Close()
+	router := setupGatewayAuthRouter(config.AuthConfig{JWKSSource: jwksServer.URL})
+	rotated.Store(true)
+	token := signToken(t, jwt.SigningMethodES256, "ec-2", getValidClaims(), newKey)
+
+	// Act
+	responseRecorder := sendWithToken(router, token)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestGatewayAuthRejectsUnknownKidWithinRefreshInterval(t *testing.T) {
+	// Arrange
+	knownKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	unknownKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var fetches atomic.Int32
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{ecJWK("ec-1", &knownKey.PublicKey)}})
+	}))
+	defer jwksServer.Close()
+	router := setupGatewayAuthRouter(config.AuthConfig{JWKSSource: jwksServer.URL, JWKSRefreshInterval: time.Hour})
+	token := signToken(t, jwt.SigningMethodES256, "ec-9", getValidClaims(), unknownKey)
+
+	// Act
+	firstResponse := sendWithToken(router, token)
+	secondResponse := sendWithToken(router, token)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, firstResponse.Code)
+	assert.Equal(t, http.StatusUnauthorized, secondResponse.Code)
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestGatewayAuthRejectsHMACTokenWhenOnlyJWKSIsConfigured(t *testing.T) {
+	// Arrange
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []interface{}{rsaJWK("rsa-1", &privateKey.PublicKey)}})
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(jwksFile, jwks, 0600))
+	router := setupGatewayAuthRouter(config.AuthConfig{JWKSSource: jwksFile})
+	token := signToken(t, jwt.SigningMethodHS256, "", getValidClaims(), []byte(""))
+
+	// Act
+	responseRecorder := sendWithToken(router, token)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+}
+
+func TestGatewayAuthRejectsInvalidClaims(t *testing.T) {
+	authConfig := config.AuthConfig{
+		JWTSecret: "secret",
+		Issuer:    "https://gateway.flyhorizons.com",
+		Audience:  "booking-service",
+	}
+	testCases := map[string]func(claims jwt.MapClaims){
+		"wrong issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+		"wrong audience": func(claims jwt.MapClaims) { claims["aud"] = "payment-service" },
+		"expired":        func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"missing exp":    func(claims jwt.MapClaims) { delete(claims, "exp") },
+		"not valid yet":  func(claims jwt.MapClaims) { claims["nbf"] = time.Now().Add(time.Hour).Unix() },
+	}
+
+	for name, modify := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			router := setupGatewayAuthRouter(authConfig)
+			claims := getValidClaims()
+			modify(claims)
+			token := signToken(t, jwt.SigningMethodHS256, "", claims, []byte("secret"))
+
+			// Act
+			responseRecorder := sendWithToken(router, token)
+
+			// Assert
+			assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+		})
+	}
+}
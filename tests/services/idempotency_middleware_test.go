@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services/idempotency"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestIdempotencyMiddleware struct {
+}
+
+// Setup
+func setupIdempotencyRouter(mockRepo *mock_repositories.MockIdempotencyRepository, status int) *gin.Engine {
+	router := gin.Default()
+	middleware := idempotency.NewIdempotencyMiddleware(mockRepo, time.Hour)
+	router.POST("/bookings", middleware.IdempotencyMiddleware(), func(ctx *gin.Context) {
+		ctx.JSON(status, gin.H{"id": 1})
+	})
+	return router
+}
+
+func newIdempotentRequest(key string, body string) *http.Request {
+	httpRequest, _ := http.NewRequest("POST", "/bookings", strings.NewReader(body))
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		httpRequest.Header.Set("Idempotency-Key", key)
+	}
+	return httpRequest
+}
+
+// Middleware Unit Tests
+func TestIdempotencyMiddlewareFirstRequestStoresResponse(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockIdempotencyRepository)
+	mockRepo.On("Reserve", mock.MatchedBy(func(key entities.IdempotencyKeyEntity) bool {
+		return key.Key == "key-1" && key.RequestHash != ""
+	})).Return(nil, nil)
+	mockRepo.On("Complete", "key-1", http.StatusCreated, `{"id":1}`).Return(nil)
+	router := setupIdempotencyRouter(mockRepo, http.StatusCreated)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newIdempotentRequest("key-1", `{"flight_code":"FR788"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddlewareServerErrorReleasesKey(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockIdempotencyRepository)
+	mockRepo.On("Reserve", mock.Anything).Return(nil, nil)
+	mockRepo.On("Release", "key-1").Return(nil)
+	router := setupIdempotencyRouter(mockRepo, http.StatusInternalServerError)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newIdempotentRequest("key-1", `{"flight_code":"FR788"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddlewarePanickingHandlerReleasesKey(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockIdempotencyRepository)
+	mockRepo.On("Reserve", mock.Anything).Return(nil, nil)
+	mockRepo.On("Release", "key-1").Return(nil)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	middleware := idempotency.NewIdempotencyMiddleware(mockRepo, time.Hour)
+	router.POST("/bookings", middleware.IdempotencyMiddleware(), func(ctx *gin.Context) {
+		panic("nil booking")
+	})
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newIdempotentRequest("key-1", `{"flight_code":"FR788"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddlewareAbandonedRequestIsTakenOver(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockIdempotencyRepository)
+	var abandonedKey entities.IdempotencyKeyEntity
+	mockRepo.On("Reserve", mock.Anything).Run(func(args mock.Arguments) {
+		// The same request was reserved two minutes ago, and never completed
+		abandonedKey = args.Get(0).(entities.IdempotencyKeyEntity)
+		abandonedKey.CreatedAt = abandonedKey.CreatedAt.Add(-2 * time.Minute)
+	}).Return(&abandonedKey, nil).Once()
+	mockRepo.On("ReleaseAbandoned", "key-1", mock.Anything).Return(true, nil)
+	mockRepo.On("Reserve", mock.Anything).Return(nil, nil).Once()
+	mockRepo.On("Complete", "key-1", http.StatusCreated, `{"id":1}`).Return(nil)
+	router := setupIdempotencyRouter(mockRepo, http.StatusCreated)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newIdempotentRequest("key-1", `{"flight_code":"FR788"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddlewareRequestInProgressReturnsConflict(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockIdempotencyRepository)
+	var reservedKey entities.IdempotencyKeyEntity
+	mockRepo.On("Reserve", mock.Anything).Run(func(args mock.Arguments) {
+		reservedKey = args.Get(0).(entities.IdempotencyKeyEntity)
+	}).Return(nil, nil).Once()
+	mockRepo.On("Reserve", mock.Anything).Return(&reservedKey, nil)
+	mockRepo.On("Complete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	router := setupIdempotencyRouter(mockRepo, http.StatusCreated)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("key-1", `{"flight_code":"FR788"}`))
+	router.ServeHTTP(responseRecorder, newIdempotentRequest("key-1", `{"flight_code":"FR788"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+}
+
+func TestIdempotencyMiddlewareWithoutKeyPassesThrough(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockIdempotencyRepository)
+	router := setupIdempotencyRouter(mockRepo, http.StatusCreated)
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newIdempotentRequest("", `{"flight_code":"FR788"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	mockRepo.AssertNotCalled(t, "Reserve", mock.Anything)
+}
+
+func TestIdempotencyMiddlewareScopesKeyToLoggedInUser(t *testing.T) {
+	// Arrange
+	mockRepo := new(mock_repositories.MockIdempotencyRepository)
+	mockRepo.On("Reserve", mock.MatchedBy(func(key entities.IdempotencyKeyEntity) bool {
+		return key.Key == "4:key-1"
+	})).Return(nil, nil)
+	mockRepo.On("Complete", "4:key-1", http.StatusCreated, `{"id":1}`).Return(nil)
+	router := gin.Default()
+	middleware := idempotency.NewIdempotencyMiddleware(mockRepo, time.Hour)
+	router.POST("/bookings", func(ctx *gin.Context) {
+		ctx.Set("user_id", 4)
+	}, middleware.IdempotencyMiddleware(), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+	responseRecorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(responseRecorder, newIdempotentRequest("key-1", `{"flight_code":"FR788"}`))
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	mockRepo.AssertExpectations(t)
+}
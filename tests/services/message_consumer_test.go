@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"flyhorizons-bookingservice/config"
+	"flyhorizons-bookingservice/services"
+	"flyhorizons-bookingservice/services/errors"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"fmt"
+	"testing"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestMessageConsumer struct {
+}
+
+// Setup
+func setupMessageConsumer() (*mock_repositories.MockMessageBroker, *mock_repositories.MockAcknowledger, *services.MessageConsumer) {
+	mockBroker := new(mock_repositories.MockMessageBroker)
+	mockAcknowledger := new(mock_repositories.MockAcknowledger)
+	messageConsumer := services.NewMessageConsumer(mockBroker)
+	return mockBroker, mockAcknowledger, messageConsumer
+}
+
+func getDelivery(acknowledger amqp091.Acknowledger, attempts int) amqp091.Delivery {
+	delivery := amqp091.Delivery{Acknowledger: acknowledger, DeliveryTag: 1, Body: []byte("42")}
+	if attempts > 0 {
+		delivery.Headers = amqp091.Table{"x-delivery-attempts": int32(attempts)}
+	}
+	return delivery
+}
+
+// Service Unit Tests
+func TestHandleWithSucceedingHandlerAcknowledgesMessage(t *testing.T) {
+	// Arrange
+	mockBroker, mockAcknowledger, messageConsumer := setupMessageConsumer()
+	mockAcknowledger.On("Ack", uint64(1), false).Return(nil)
+
+	// Act
+	messageConsumer.Handle("payment.success", getDelivery(mockAcknowledger, 0), func(message amqp091.Delivery) error {
+		return nil
+	})
+
+	// Assert
+	mockAcknowledger.AssertExpectations(t)
+	mockBroker.AssertNotCalled(t, "PublishWithHeaders", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleWithFailingHandlerMovesMessageToRetryQueue(t *testing.T) {
+	// Arrange
+	mockBroker, mockAcknowledger, messageConsumer := setupMessageConsumer()
+	mockBroker.On("PublishWithHeaders", "payment.success"+config.RetryQueueSuffix, []byte("42"), mock.MatchedBy(func(headers amqp091.Table) bool {
+		return headers["x-delivery-attempts"] == int32(2) && headers["x-last-error"] == "database unavailable" && headers["x-dead-lettered-at"] == nil
+	})).Return(nil)
+	mockAcknowledger.On("Ack", uint64(1), false).Return(nil)
+
+	// Act
+	messageConsumer.Handle("payment.success", getDelivery(mockAcknowledger, 1), func(message amqp091.Delivery) error {
+		return fmt.Errorf("database unavailable")
+	})
+
+	// Assert
+	mockBroker.AssertExpectations(t)
+	mockAcknowledger.AssertExpectations(t)
+}
+
+func TestHandleAfterMaxDeliveryAttemptsMovesMessageToDeadLetterQueue(t *testing.T) {
+	// Arrange
+	mockBroker, mockAcknowledger, messageConsumer := setupMessageConsumer()
+	mockBroker.On("PublishWithHeaders", "payment.success"+config.DeadLetterQueueSuffix, []byte("42"), mock.MatchedBy(func(headers amqp091.Table) bool {
+		return headers["x-delivery-attempts"] == int32(config.MaxDeliveryAttempts) && headers["x-dead-lettered-at"] != nil
+	})).Return(nil)
+	mockAcknowledger.On("Ack", uint64(1), false).Return(nil)
+
+	// Act
+	messageConsumer.Handle("payment.success", getDelivery(mockAcknowledger, config.MaxDeliveryAttempts-1), func(message amqp091.Delivery) error {
+		return fmt.Errorf("database unavailable")
+	})
+
+	// Assert
+	mockBroker.AssertExpectations(t)
+	mockAcknowledger.AssertExpectations(t)
+}
+
+func TestHandleWithUnprocessableMessageMovesMessageToDeadLetterQueueStraightAway(t *testing.T) {
+	// Arrange
+	mockBroker, mockAcknowledger, messageConsumer := setupMessageConsumer()
+	mockBroker.On("PublishWithHeaders", "payment.success"+config.DeadLetterQueueSuffix, []byte("42"), mock.MatchedBy(func(headers amqp091.Table) bool {
+		return headers["x-delivery-attempts"] == int32(1)
+	})).Return(nil)
+	mockAcknowledger.On("Ack", uint64(1), false).Return(nil)
+
+	// Act
+	messageConsumer.Handle("payment.success", getDelivery(mockAcknowledger, 0), func(message amqp091.Delivery) error {
+		return errors.NewUnprocessableMessageError("payment.success", fmt.Errorf("invalid booking ID"))
+	})
+
+	// Assert
+	mockBroker.AssertExpectations(t)
+	mockAcknowledger.AssertExpectations(t)
+}
+
+func TestHandleWithPanickingHandlerMovesMessageToRetryQueue(t *testing.T) {
+	// Arrange
+	mockBroker, mockAcknowledger, messageConsumer := setupMessageConsumer()
+	mockBroker.On("PublishWithHeaders", "payment.success"+config.RetryQueueSuffix, []byte("42"), mock.MatchedBy(func(headers amqp091.Table) bool {
+		return headers["x-last-error"] == "handler for payment.success panicked: nil booking"
+	})).Return(nil)
+	mockAcknowledger.On("Ack", uint64(1), false).Return(nil)
+
+	// Act
+	assert.NotPanics(t, func() {
+		messageConsumer.Handle("payment.success", getDelivery(mockAcknowledger, 0), func(message amqp091.Delivery) error {
+			panic("nil booking")
+		})
+	})
+
+	// Assert
+	mockBroker.AssertExpectations(t)
+	mockAcknowledger.AssertExpectations(t)
+}
+
+func TestHandleWithFailingPublishRequeuesMessage(t *testing.T) {
+	// Arrange
+	mockBroker, mockAcknowledger, messageConsumer := setupMessageConsumer()
+	mockBroker.On("PublishWithHeaders", "payment.success"+config.RetryQueueSuffix, mock.Anything, mock.Anything).Return(fmt.Errorf("RabbitMQ is disconnected"))
+	mockAcknowledger.On("Nack", uint64(1), false, true).Return(nil)
+
+	// Act
+	messageConsumer.Handle("payment.success", getDelivery(mockAcknowledger, 0), func(message amqp091.Delivery) error {
+		return fmt.Errorf("database unavailable")
+	})
+
+	// Assert
+	mockAcknowledger.AssertExpectations(t)
+	mockAcknowledger.AssertNotCalled(t, "Ack", mock.Anything, mock.Anything)
+}
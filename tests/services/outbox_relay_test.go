@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestOutboxRelay struct {
+}
+
+// Setup
+func setupOutboxRelay() (*mock_repositories.MockOutboxRepository, *mock_repositories.MockMessagePublisher, *services.OutboxRelay) {
+	mockRepo := new(mock_repositories.MockOutboxRepository)
+	mockPublisher := new(mock_repositories.MockMessagePublisher)
+	outboxRelay := services.NewOutboxRelay(mockRepo, mockPublisher, time.Second, 10)
+	return mockRepo, mockPublisher, outboxRelay
+}
+
+func getOutboxMessageEntities() []entities.OutboxMessageEntity {
+	return []entities.OutboxMessageEntity{
+		{ID: 1, Queue: "booking.created", Payload: `{"booking_id":1}`},
+		{ID: 2, Queue: "booking.confirmed", Payload: `{"id":2}`, Attempts: 2},
+	}
+}
+
+// Service Unit Tests
+func TestDispatchPendingPublishesAndMarksMessagesAsDispatched(t *testing.T) {
+	// Arrange
+	mockRepo, mockPublisher, outboxRelay := setupOutboxRelay()
+	messages := getOutboxMessageEntities()
+	mockRepo.On("GetPending", 10).Return(messages, nil)
+	mockPublisher.On("Publish", "booking.created", []byte(messages[0].Payload)).Return(nil)
+	mockPublisher.On("Publish", "booking.confirmed", []byte(messages[1].Payload)).Return(nil)
+	mockRepo.On("MarkDispatched", 1).Return(nil)
+	mockRepo.On("MarkDispatched", 2).Return(nil)
+
+	// Act
+	dispatched := outboxRelay.DispatchPending()
+
+	// Assert
+	assert.Equal(t, 2, dispatched)
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestDispatchPendingWithFailingPublishSchedulesRetry(t *testing.T) {
+	// Arrange
+	mockRepo, mockPublisher, outboxRelay := setupOutboxRelay()
+	messages := getOutboxMessageEntities()
+	mockRepo.On("GetPending", 10).Return(messages, nil)
+	mockPublisher.On("Publish", "booking.created", mock.Anything).Return(nil)
+	mockPublisher.On("Publish", "booking.confirmed", mock.Anything).Return(fmt.Errorf("channel closed"))
+	mockRepo.On("MarkDispatched", 1).Return(nil)
+	// Third attempt, so the retry is delayed by four seconds
+	mockRepo.On("MarkFailed", 2, "channel closed", mock.MatchedBy(func(nextAttemptAt time.Time) bool {
+		delay := time.Until(nextAttemptAt)
+		return delay > 3*time.Second && delay <= 4*time.Second
+	})).Return(nil)
+
+	// Act
+	dispatched := outboxRelay.DispatchPending()
+
+	// Assert
+	assert.Equal(t, 1, dispatched)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "MarkDispatched", 2)
+}
@@ -0,0 +1,124 @@
+package services_test
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	"flyhorizons-bookingservice/services"
+	"flyhorizons-bookingservice/services/errors"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getQuotedBooking() models.Booking {
+	return models.Booking{
+		UserID:      4,
+		FlightCode:  "FR788",
+		FlightClass: enums.Economy,
+		DepartureAt: getPricingDeparture(),
+		Passengers: []models.Passenger{
+			{FullName: "John Doe", DateOfBirth: time.Date(1985, 7, 9, 0, 0, 0, 0, time.UTC), Luggage: []models.LuggageItem{{Type: enums.Cargo20kg, Quantity: 1}}},
+		},
+	}
+}
+
+// Unit Tests
+func TestQuoteReturnsPriceAndExpiry(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+
+	// Act
+	quote, err := pricingService.Quote(getQuotedBooking())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, quote.QuoteID)
+	assert.Equal(t, int64(13500), quote.Price.TotalCents)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), quote.ExpiresAt, 2*time.Second)
+}
+
+func TestPriceFromQuoteReturnsQuotedPriceAfterFareChange(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil).Once()
+	quote, _ := pricingService.Quote(getQuotedBooking())
+
+	// Act
+	price, err := pricingService.PriceFromQuote(quote.QuoteID, getQuotedBooking())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, quote.Price, *price)
+	mockFareRepo.AssertNumberOfCalls(t, "GetByFlight", 1)
+}
+
+func TestPriceFromQuoteOfOtherBookingThrowsInvalidQuoteError(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	quote, _ := pricingService.Quote(getQuotedBooking())
+	// A second passenger is added after the quote was made
+	booking := getQuotedBooking()
+	booking.Passengers = append(booking.Passengers, models.Passenger{FullName: "Jane Doe", DateOfBirth: time.Date(1986, 8, 8, 0, 0, 0, 0, time.UTC)})
+
+	// Act
+	price, err := pricingService.PriceFromQuote(quote.QuoteID, booking)
+
+	// Assert
+	assert.IsType(t, &errors.InvalidQuoteError{}, err)
+	assert.Nil(t, price)
+}
+
+func TestPriceFromQuoteOfOtherUserThrowsInvalidQuoteError(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	quote, _ := pricingService.Quote(getQuotedBooking())
+	booking := getQuotedBooking()
+	booking.UserID = 5
+
+	// Act
+	_, err := pricingService.PriceFromQuote(quote.QuoteID, booking)
+
+	// Assert
+	assert.IsType(t, &errors.InvalidQuoteError{}, err)
+}
+
+func TestPriceFromTamperedQuoteThrowsInvalidQuoteError(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	quote, _ := pricingService.Quote(getQuotedBooking())
+	// The quote is signed with the secret of another service
+	otherPricingService := services.NewPricingService(mockFareRepo, new(mock_repositories.MockSeatService), new(mock_repositories.MockAncillaryService), []byte("other-secret"), 15*time.Minute)
+	forgedQuote, _ := otherPricingService.Quote(getQuotedBooking())
+
+	// Act
+	_, malformedErr := pricingService.PriceFromQuote("not-a-quote", getQuotedBooking())
+	_, forgedErr := pricingService.PriceFromQuote(forgedQuote.QuoteID, getQuotedBooking())
+	_, truncatedErr := pricingService.PriceFromQuote(quote.QuoteID[:len(quote.QuoteID)-2], getQuotedBooking())
+
+	// Assert
+	assert.IsType(t, &errors.InvalidQuoteError{}, malformedErr)
+	assert.IsType(t, &errors.InvalidQuoteError{}, forgedErr)
+	assert.IsType(t, &errors.InvalidQuoteError{}, truncatedErr)
+}
+
+func TestPriceFromExpiredQuoteThrowsQuoteExpiredError(t *testing.T) {
+	// Arrange
+	mockFareRepo := new(mock_repositories.MockFareRepository)
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	// Quotes of this service expire before they are issued
+	pricingService := services.NewPricingService(mockFareRepo, new(mock_repositories.MockSeatService), new(mock_repositories.MockAncillaryService), []byte("test-quote-secret"), -time.Minute)
+	quote, _ := pricingService.Quote(getQuotedBooking())
+
+	// Act
+	price, err := pricingService.PriceFromQuote(quote.QuoteID, getQuotedBooking())
+
+	// Assert
+	assert.Equal(t, errors.NewQuoteExpiredError(quote.ExpiresAt, 410), err)
+	assert.Nil(t, price)
+}
@@ -0,0 +1,296 @@
+package services_test
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services"
+	"flyhorizons-bookingservice/services/errors"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Setup
+func setupPricingService() (*mock_repositories.MockFareRepository, *mock_repositories.MockSeatService, *services.PricingService) {
+	return setupPricingServiceWithAncillaryService(new(mock_repositories.MockAncillaryService))
+}
+
+func setupPricingServiceWithAncillaryService(mockAncillaryService *mock_repositories.MockAncillaryService) (*mock_repositories.MockFareRepository, *mock_repositories.MockSeatService, *services.PricingService) {
+	mockFareRepo := new(mock_repositories.MockFareRepository)
+	mockSeatService := new(mock_repositories.MockSeatService)
+	pricingService := services.NewPricingService(mockFareRepo, mockSeatService, mockAncillaryService, []byte("test-quote-secret"), 15*time.Minute)
+	return mockFareRepo, mockSeatService, pricingService
+}
+
+func getFlightFare() *entities.FlightFareEntity {
+	return &entities.FlightFareEntity{
+		FlightCode:     "FR788",
+		FlightClass:    int(enums.Economy),
+		DepartureAt:    getPricingDeparture(),
+		Currency:       "EUR",
+		BaseFareCents:  10000,
+		SeatSurcharges: `{"ExtraLegroom":2500,"ExitRow":1500}`,
+		LuggagePrices:  `{"CabinBag":1500,"Cargo20kg":3500}`,
+	}
+}
+
+func getPricingDeparture() *time.Time {
+	departure := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	return &departure
+}
+
+// Unit Tests
+func TestCalculatePriceAppliesAgeBandsOnDepartureDate(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	booking := models.Booking{
+		FlightCode:  "FR788",
+		FlightClass: enums.Economy,
+		DepartureAt: getPricingDeparture(),
+		Passengers: []models.Passenger{
+			{FullName: "John Doe", DateOfBirth: time.Date(1985, 7, 9, 0, 0, 0, 0, time.UTC)},
+			// Turns 12 the day after the departure, so still travels as a child
+			{FullName: "Jimmy Doe", DateOfBirth: time.Date(2013, 7, 2, 0, 0, 0, 0, time.UTC)},
+			{FullName: "Baby Doe", DateOfBirth: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	// Act
+	price, err := pricingService.Calculate(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	expectedPrice := &models.PriceBreakdown{
+		Currency: "EUR",
+		Items: []models.PriceItem{
+			{Type: "fare", Description: "Economy Adult fare", Quantity: 1, UnitPriceCents: 10000, AmountCents: 10000},
+			{Type: "fare", Description: "Economy Child fare", Quantity: 1, UnitPriceCents: 7500, AmountCents: 7500},
+			{Type: "fare", Description: "Economy Infant fare", Quantity: 1, UnitPriceCents: 1000, AmountCents: 1000},
+		},
+		TotalCents: 18500,
+	}
+	assert.Equal(t, expectedPrice, price)
+}
+
+func TestCalculatePriceAddsSeatSurchargesAndLuggage(t *testing.T) {
+	// Arrange
+	mockFareRepo, mockSeatService, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	mockSeatService.On("GetByFlightCode", "FR788").Return([]models.Seat{
+		{Row: 12, Column: "A", Attributes: []enums.SeatAttribute{enums.ExitRow, enums.ExtraLegroom}},
+		{Row: 14, Column: "A", Attributes: []enums.SeatAttribute{enums.RestrictedRecline}},
+		{Row: 20, Column: "A"},
+	}, nil)
+	booking := models.Booking{
+		FlightCode:  "FR788",
+		FlightClass: enums.Economy,
+		DepartureAt: getPricingDeparture(),
+		Passengers: []models.Passenger{
+			{FullName: "John Doe", DateOfBirth: time.Date(1985, 7, 9, 0, 0, 0, 0, time.UTC), Luggage: []models.LuggageItem{
+				{Type: enums.SmallBag, Quantity: 1},
+				{Type: enums.Cargo20kg, Quantity: 2},
+			}},
+			{FullName: "Jane Doe", DateOfBirth: time.Date(1986, 8, 8, 0, 0, 0, 0, time.UTC), Luggage: []models.LuggageItem{
+				{Type: enums.CabinBag, Quantity: 1},
+			}},
+		},
+		Seats: []models.Seat{{Row: 12, Column: "A"}, {Row: 14, Column: "A"}},
+	}
+
+	// Act
+	price, err := pricingService.Calculate(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	expectedItems := []models.PriceItem{
+		{Type: "fare", Description: "Economy Adult fare", Quantity: 2, UnitPriceCents: 10000, AmountCents: 20000},
+		{Type: "seat", Description: "Seat 12A ExitRow", Quantity: 1, UnitPriceCents: 1500, AmountCents: 1500},
+		{Type: "seat", Description: "Seat 12A ExtraLegroom", Quantity: 1, UnitPriceCents: 2500, AmountCents: 2500},
+		{Type: "luggage", Description: "Cargo20kg", Quantity: 2, UnitPriceCents: 3500, AmountCents: 7000},
+		{Type: "luggage", Description: "CabinBag", Quantity: 1, UnitPriceCents: 1500, AmountCents: 1500},
+	}
+	assert.Equal(t, expectedItems, price.Items)
+	assert.Equal(t, int64(32500), price.TotalCents)
+	assert.Equal(t, 325.0, price.Total())
+}
+
+func TestCalculatePriceOnlyChargesLuggageAboveTheIncludedAllowance(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	businessFare := getFlightFare()
+	businessFare.FlightClass = int(enums.Business)
+	businessFare.LuggagePrices = `{"CabinBag":1500,"Cargo30kg":5000}`
+	mockFareRepo.On("GetByFlight", "FR788", enums.Business).Return(businessFare, nil)
+	booking := models.Booking{
+		FlightCode:  "FR788",
+		FlightClass: enums.Business,
+		DepartureAt: getPricingDeparture(),
+		Passengers: []models.Passenger{
+			// Business includes a cabin bag and a Cargo30kg per passenger
+			{FullName: "John Doe", DateOfBirth: time.Date(1985, 7, 9, 0, 0, 0, 0, time.UTC), Luggage: []models.LuggageItem{
+				{Type: enums.CabinBag, Quantity: 1},
+				{Type: enums.Cargo30kg, Quantity: 1},
+				{Type: enums.Cargo30kg, Quantity: 1},
+			}},
+			{FullName: "Jane Doe", DateOfBirth: time.Date(1986, 8, 8, 0, 0, 0, 0, time.UTC), Luggage: []models.LuggageItem{
+				{Type: enums.Cargo30kg, Quantity: 1},
+			}},
+		},
+	}
+
+	// Act
+	price, err := pricingService.Calculate(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	expectedItems := []models.PriceItem{
+		{Type: "fare", Description: "Business Adult fare", Quantity: 2, UnitPriceCents: 10000, AmountCents: 20000},
+		{Type: "luggage", Description: "Cargo30kg", Quantity: 1, UnitPriceCents: 5000, AmountCents: 5000},
+	}
+	assert.Equal(t, expectedItems, price.Items)
+	assert.Equal(t, int64(25000), price.TotalCents)
+}
+
+func TestCalculatePriceAddsAncillariesOfPassengers(t *testing.T) {
+	// Arrange
+	mockAncillaryService := new(mock_repositories.MockAncillaryService)
+	mockFareRepo, _, pricingService := setupPricingServiceWithAncillaryService(mockAncillaryService)
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	mockAncillaryService.On("GetAvailable", enums.Economy).Return([]models.Ancillary{
+		{Code: "VegetarianMeal", Category: enums.Meal, Name: "Vegetarian meal", Currency: "EUR", PriceCents: 1200},
+		{Code: "PriorityBoarding", Category: enums.PriorityBoarding, Name: "Priority boarding", Currency: "EUR", PriceCents: 900},
+		{Code: "TravelInsurance", Category: enums.TravelInsurance, Name: "Travel insurance", Currency: "EUR", PriceCents: 0},
+	}, nil)
+	booking := models.Booking{
+		FlightCode:  "FR788",
+		FlightClass: enums.Economy,
+		DepartureAt: getPricingDeparture(),
+		Passengers: []models.Passenger{
+			{FullName: "John Doe", DateOfBirth: time.Date(1985, 7, 9, 0, 0, 0, 0, time.UTC), Ancillaries: []string{"PriorityBoarding", "VegetarianMeal"}},
+			{FullName: "Jane Doe", DateOfBirth: time.Date(1986, 8, 8, 0, 0, 0, 0, time.UTC), Ancillaries: []string{"VegetarianMeal", "TravelInsurance"}},
+		},
+	}
+
+	// Act
+	price, err := pricingService.Calculate(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	expectedItems := []models.PriceItem{
+		{Type: "fare", Description: "Economy Adult fare", Quantity: 2, UnitPriceCents: 10000, AmountCents: 20000},
+		{Type: "ancillary", Description: "Priority boarding", Quantity: 1, UnitPriceCents: 900, AmountCents: 900},
+		{Type: "ancillary", Description: "Vegetarian meal", Quantity: 2, UnitPriceCents: 1200, AmountCents: 2400},
+	}
+	assert.Equal(t, expectedItems, price.Items)
+	assert.Equal(t, int64(23300), price.TotalCents)
+}
+
+func TestCalculatePriceWithAncillaryInOtherCurrencyThrowsAncillaryCurrencyError(t *testing.T) {
+	// Arrange
+	mockAncillaryService := new(mock_repositories.MockAncillaryService)
+	mockFareRepo, _, pricingService := setupPricingServiceWithAncillaryService(mockAncillaryService)
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	mockAncillaryService.On("GetAvailable", enums.Economy).Return([]models.Ancillary{
+		{Code: "PriorityBoarding", Category: enums.PriorityBoarding, Name: "Priority boarding", Currency: "USD", PriceCents: 900},
+	}, nil)
+	booking := models.Booking{
+		FlightCode:  "FR788",
+		FlightClass: enums.Economy,
+		DepartureAt: getPricingDeparture(),
+		Passengers: []models.Passenger{
+			{FullName: "John Doe", DateOfBirth: time.Date(1985, 7, 9, 0, 0, 0, 0, time.UTC), Ancillaries: []string{"PriorityBoarding"}},
+		},
+	}
+
+	// Act
+	price, err := pricingService.Calculate(booking)
+
+	// Assert
+	assert.Equal(t, errors.NewAncillaryCurrencyError("PriorityBoarding", "USD", "EUR", 500), err)
+	assert.Nil(t, price)
+}
+
+func TestCalculatePriceWithoutSeatsDoesNotLoadSeatMap(t *testing.T) {
+	// Arrange
+	mockFareRepo, mockSeatService, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	booking := models.Booking{FlightCode: "FR788", FlightClass: enums.Economy, DepartureAt: getPricingDeparture()}
+
+	// Act
+	price, err := pricingService.Calculate(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), price.TotalCents)
+	mockSeatService.AssertNotCalled(t, "GetByFlightCode", mock.Anything)
+}
+
+func TestCalculatePriceWithoutFareThrowsFareNotFoundError(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	fareError := errors.NewFareNotFoundError("FR788", enums.Business, 404)
+	mockFareRepo.On("GetByFlight", "FR788", enums.Business).Return(nil, fareError)
+
+	// Act
+	price, err := pricingService.Calculate(models.Booking{FlightCode: "FR788", FlightClass: enums.Business})
+
+	// Assert
+	assert.Equal(t, fareError, err)
+	assert.Nil(t, price)
+}
+
+func TestCalculatePriceIgnoresDepartureOfBooking(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+	// A year later the child would be 12 and pay the adult fare
+	laterDeparture := getPricingDeparture().AddDate(1, 0, 0)
+	booking := models.Booking{
+		FlightCode:  "FR788",
+		FlightClass: enums.Economy,
+		DepartureAt: &laterDeparture,
+		Passengers: []models.Passenger{
+			{FullName: "Jimmy Doe", DateOfBirth: time.Date(2013, 7, 2, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	// Act
+	price, err := pricingService.Calculate(booking)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7500), price.TotalCents)
+}
+
+func TestCalculatePriceWithoutDepartureOfFlightThrowsDepartureNotFoundError(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	fare := getFlightFare()
+	fare.DepartureAt = nil
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(fare, nil)
+
+	// Act
+	price, err := pricingService.Calculate(models.Booking{FlightCode: "FR788", FlightClass: enums.Economy, DepartureAt: getPricingDeparture()})
+
+	// Assert
+	assert.Equal(t, errors.NewDepartureNotFoundError("FR788", 404), err)
+	assert.Nil(t, price)
+}
+
+func TestGetDepartureReturnsDepartureOfFlight(t *testing.T) {
+	// Arrange
+	mockFareRepo, _, pricingService := setupPricingService()
+	mockFareRepo.On("GetByFlight", "FR788", enums.Economy).Return(getFlightFare(), nil)
+
+	// Act
+	departureAt, err := pricingService.GetDeparture("FR788", enums.Economy)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, getPricingDeparture(), departureAt)
+}
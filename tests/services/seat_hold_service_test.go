@@ -0,0 +1,231 @@
+package services_test
+
+import (
+	"flyhorizons-bookingservice/models"
+	"flyhorizons-bookingservice/models/enums"
+	entities "flyhorizons-bookingservice/repositories/entity"
+	"flyhorizons-bookingservice/services"
+	"flyhorizons-bookingservice/services/converter"
+	"flyhorizons-bookingservice/services/errors"
+	mock_repositories "flyhorizons-bookingservice/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TestSeatHoldService struct {
+}
+
+// Setup
+func setupSeatHoldService() (*mock_repositories.MockSeatHoldRepository, *services.SeatHoldService) {
+	mockRepo := new(mock_repositories.MockSeatHoldRepository)
+	mockSeatService := new(mock_repositories.MockSeatService)
+	mockSeatService.On("GetByFlightCode", "FR788").Return(append(getSeatMap(), models.Seat{Row: 2, Column: "C", Cabin: enums.Economy, Blocked: true}), nil)
+	seatConverter := converter.SeatConverter{}
+	seatHoldService := services.NewSeatHoldService(mockRepo, mockSeatService, seatConverter, 10*time.Minute, 30*time.Minute)
+	return mockRepo, seatHoldService
+}
+
+func getSeatHoldRequest() models.SeatHoldRequest {
+	return models.SeatHoldRequest{
+		FlightCode: "FR788",
+		Seats: []models.Seat{
+			{Row: 1, Column: "A"},
+			{Row: 1, Column: "B"},
+		},
+	}
+}
+
+func getSeatHoldEntities(userID int) []entities.SeatHoldEntity {
+	createdAt := time.Now().Add(-5 * time.Minute)
+	expiresAt := time.Now().Add(5 * time.Minute)
+	return []entities.SeatHoldEntity{
+		{ID: 1, HoldID: "abc123", UserID: userID, FlightCode: "FR788", Row: 1, Column: "A", CreatedAt: createdAt, ExpiresAt: expiresAt},
+		{ID: 2, HoldID: "abc123", UserID: userID, FlightCode: "FR788", Row: 1, Column: "B", CreatedAt: createdAt, ExpiresAt: expiresAt},
+	}
+}
+
+// Service Unit Tests
+func TestHoldAvailableSeatsReturnsSeatHold(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	request := getSeatHoldRequest()
+	mockRepo.On("GetActiveByUser", 1, "FR788").Return([]entities.SeatHoldEntity{}, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(holds []entities.SeatHoldEntity) bool {
+		return len(holds) == 2 && holds[0].UserID == 1 && holds[0].HoldID != "" && holds[0].HoldID == holds[1].HoldID
+	})).Return(nil)
+
+	// Act
+	seatHold, err := seatHoldService.Hold(1, request)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, request.FlightCode, seatHold.FlightCode)
+	assert.Len(t, seatHold.Seats, 2)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), seatHold.ExpiresAt, time.Second)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHoldTakenSeatsThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	request := getSeatHoldRequest()
+	seatHeldError := errors.NewSeatHeldError(request.FlightCode, []models.Seat{{Row: 1, Column: "A"}}, 409)
+	mockRepo.On("GetActiveByUser", 1, "FR788").Return([]entities.SeatHoldEntity{}, nil)
+	mockRepo.On("Create", mock.Anything).Return(seatHeldError)
+
+	// Act
+	seatHold, err := seatHoldService.Hold(1, request)
+
+	// Assert
+	assert.Equal(t, seatHeldError, err)
+	assert.Nil(t, seatHold)
+}
+
+func TestHoldBlockedAndUnknownSeatsThrowsInvalidSeatHoldError(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	request := models.SeatHoldRequest{FlightCode: "FR788", Seats: []models.Seat{{Row: 1, Column: "A"}, {Row: 2, Column: "C"}, {Row: 40, Column: "A"}}}
+
+	// Act
+	seatHold, err := seatHoldService.Hold(1, request)
+
+	// Assert
+	assert.Equal(t, errors.NewInvalidSeatHoldError("FR788", []models.Seat{{Row: 2, Column: "C"}, {Row: 40, Column: "A"}}, 400), err)
+	assert.Nil(t, seatHold)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestHoldMoreThanMaxSeatsPerFlightThrowsSeatHoldLimitError(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	activeHolds := []entities.SeatHoldEntity{}
+	for row := 10; row < 10+services.MaxHeldSeatsPerFlight-1; row++ {
+		activeHolds = append(activeHolds, entities.SeatHoldEntity{HoldID: "abc123", UserID: 1, FlightCode: "FR788", Row: row, Column: "A", CreatedAt: time.Now()})
+	}
+	mockRepo.On("GetActiveByUser", 1, "FR788").Return(activeHolds, nil)
+
+	// Act
+	seatHold, err := seatHoldService.Hold(1, getSeatHoldRequest())
+
+	// Assert
+	assert.Equal(t, errors.NewSeatHoldCountLimitError("FR788", services.MaxHeldSeatsPerFlight, 409), err)
+	assert.Nil(t, seatHold)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestHoldSeatsHeldBeforeKeepsTheirLifetime(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	heldSince := time.Now().Add(-25 * time.Minute)
+	mockRepo.On("GetActiveByUser", 1, "FR788").Return([]entities.SeatHoldEntity{
+		{HoldID: "abc123", UserID: 1, FlightCode: "FR788", Row: 1, Column: "A", CreatedAt: heldSince, ExpiresAt: time.Now().Add(time.Minute)},
+	}, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(holds []entities.SeatHoldEntity) bool {
+		return len(holds) == 2 && holds[0].CreatedAt.Equal(heldSince) && holds[1].CreatedAt.Equal(heldSince)
+	})).Return(nil)
+
+	// Act
+	seatHold, err := seatHoldService.Hold(1, getSeatHoldRequest())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.WithinDuration(t, heldSince.Add(30*time.Minute), seatHold.ExpiresAt, time.Second)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExtendOwnHoldReturnsExtendedSeatHold(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	mockRepo.On("GetActiveByHoldID", "abc123").Return(getSeatHoldEntities(1), nil)
+	mockRepo.On("Extend", "abc123", mock.Anything).Return(nil)
+
+	// Act
+	seatHold, err := seatHoldService.Extend(1, "abc123")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), seatHold.ExpiresAt, time.Second)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExtendHoldNearMaxLifetimeCapsExpiry(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	holdEntities := getSeatHoldEntities(1)
+	createdAt := time.Now().Add(-25 * time.Minute)
+	for i := range holdEntities {
+		holdEntities[i].CreatedAt = createdAt
+	}
+	mockRepo.On("GetActiveByHoldID", "abc123").Return(holdEntities, nil)
+	mockRepo.On("Extend", "abc123", createdAt.Add(30*time.Minute)).Return(nil)
+
+	// Act
+	seatHold, err := seatHoldService.Extend(1, "abc123")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, createdAt.Add(30*time.Minute), seatHold.ExpiresAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExtendHoldPastMaxLifetimeThrowsSeatHoldLimitError(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	holdEntities := getSeatHoldEntities(1)
+	for i := range holdEntities {
+		holdEntities[i].CreatedAt = time.Now().Add(-31 * time.Minute)
+	}
+	mockRepo.On("GetActiveByHoldID", "abc123").Return(holdEntities, nil)
+
+	// Act
+	seatHold, err := seatHoldService.Extend(1, "abc123")
+
+	// Assert
+	assert.Equal(t, errors.NewSeatHoldLifetimeLimitError("FR788", 30*time.Minute, 409), err)
+	assert.Nil(t, seatHold)
+	mockRepo.AssertNotCalled(t, "Extend", mock.Anything, mock.Anything)
+}
+
+func TestExtendHoldOfOtherUserThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	mockRepo.On("GetActiveByHoldID", "abc123").Return(getSeatHoldEntities(2), nil)
+
+	// Act
+	seatHold, err := seatHoldService.Extend(1, "abc123")
+
+	// Assert
+	assert.Equal(t, errors.NewSeatHoldNotFoundError("abc123", 404), err)
+	assert.Nil(t, seatHold)
+	mockRepo.AssertNotCalled(t, "Extend", mock.Anything, mock.Anything)
+}
+
+func TestReleaseOwnHoldReleasesSeats(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	mockRepo.On("GetActiveByHoldID", "abc123").Return(getSeatHoldEntities(1), nil)
+	mockRepo.On("Release", "abc123").Return(nil)
+
+	// Act
+	err := seatHoldService.Release(1, "abc123")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReleaseExpiredHoldThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, seatHoldService := setupSeatHoldService()
+	mockRepo.On("GetActiveByHoldID", "abc123").Return([]entities.SeatHoldEntity{}, nil)
+
+	// Act
+	err := seatHoldService.Release(1, "abc123")
+
+	// Assert
+	assert.Equal(t, errors.NewSeatHoldNotFoundError("abc123", 404), err)
+	mockRepo.AssertNotCalled(t, "Release", mock.Anything)
+}
@@ -1,9 +1,11 @@
 package services_test
 
 import (
+	"flyhorizons-bookingservice/models"
 	entities "flyhorizons-bookingservice/repositories/entity"
 	"flyhorizons-bookingservice/services"
 	"flyhorizons-bookingservice/services/converter"
+	"flyhorizons-bookingservice/services/errors"
 	mock_repositories "flyhorizons-bookingservice/tests/mocks"
 	"testing"
 
@@ -21,20 +23,15 @@ func setupSeatService() (*mock_repositories.MockSeatRepository, *services.SeatSe
 	return mockRepo, seatService
 }
 
-func getSeatOptionEntites() []entities.SeatOptionEntity {
-	return []entities.SeatOptionEntity{
-		{
-			ID:     1,
-			Row:    1,
-			Column: "A",
-			Status: true,
-		},
-		{
-			ID:     2,
-			Row:    1,
-			Column: "B",
-			Status: true,
+func getAircraftConfigurationEntity() *entities.AircraftConfigurationEntity {
+	return &entities.AircraftConfigurationEntity{
+		ID:   1,
+		Name: "Test 4J",
+		Cabins: []entities.AircraftCabinEntity{
+			{ID: 1, ConfigurationID: 1, FlightClass: 1, FirstRow: 1, LastRow: 1, Columns: "A-B"},
 		},
+		ExitRows:     "[]",
+		BlockedSeats: "[]",
 	}
 }
 
@@ -43,9 +40,26 @@ func TestGetSeatsByValidFlightIDReturnsSeats(t *testing.T) {
 	// Arrange
 	mockRepo, seatService := setupSeatService()
 	flightCode := "FR788"
-	seatOptionEntities := getSeatOptionEntites()
-	expectedSeats := getSeats()
-	mockRepo.On("GetByFlightCode", flightCode).Return(seatOptionEntities)
+	expectedSeats := getSeatMap()[:2]
+	mockRepo.On("GetConfigurationByFlightCode", flightCode).Return(getAircraftConfigurationEntity(), nil)
+	mockRepo.On("GetOccupiedSeats", flightCode).Return([]models.Seat{}, nil)
+
+	// Act
+	seats, err := seatService.GetByFlightCode(flightCode)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSeats, seats)
+}
+
+func TestGetSeatsWithOccupiedSeatReturnsUnavailableSeat(t *testing.T) {
+	// Arrange
+	mockRepo, seatService := setupSeatService()
+	flightCode := "FR788"
+	expectedSeats := getSeatMap()[:2]
+	expectedSeats[1].Available = false
+	mockRepo.On("GetConfigurationByFlightCode", flightCode).Return(getAircraftConfigurationEntity(), nil)
+	mockRepo.On("GetOccupiedSeats", flightCode).Return([]models.Seat{{Row: 1, Column: "B"}}, nil)
 
 	// Act
 	seats, err := seatService.GetByFlightCode(flightCode)
@@ -54,3 +68,18 @@ func TestGetSeatsByValidFlightIDReturnsSeats(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSeats, seats)
 }
+
+func TestGetSeatsByFlightWithoutConfigurationThrowsException(t *testing.T) {
+	// Arrange
+	mockRepo, seatService := setupSeatService()
+	flightCode := "FR999"
+	mockRepo.On("GetConfigurationByFlightCode", flightCode).Return(nil, nil)
+
+	// Act
+	seats, err := seatService.GetByFlightCode(flightCode)
+
+	// Assert
+	assert.Equal(t, errors.NewSeatMapNotFoundError(flightCode, 404), err)
+	assert.Nil(t, seats)
+	mockRepo.AssertNotCalled(t, "GetOccupiedSeats", flightCode)
+}